@@ -68,8 +68,22 @@ const (
 	ArgClusterVPCUUID = "vpc-uuid"
 	// ArgClusterNodePool are a cluster's node pools arguments.
 	ArgClusterNodePool = "node-pool"
+	// ArgClusterSpec is a path to a cluster spec, or a flag to output one.
+	ArgClusterSpec = "spec"
 	// ArgClusterUpdateKubeconfig updates the local kubeconfig.
 	ArgClusterUpdateKubeconfig = "update-kubeconfig"
+	// ArgClusterUpgradeGuided runs preflight checks before upgrading a cluster and waits for it to finish.
+	ArgClusterUpgradeGuided = "guided"
+	// ArgClusterlintIncludeGroups are the clusterlint check groups to run.
+	ArgClusterlintIncludeGroups = "include-groups"
+	// ArgClusterlintExcludeGroups are the clusterlint check groups to skip.
+	ArgClusterlintExcludeGroups = "exclude-groups"
+	// ArgClusterlintIncludeChecks are the clusterlint checks to run.
+	ArgClusterlintIncludeChecks = "include-checks"
+	// ArgClusterlintExcludeChecks are the clusterlint checks to skip.
+	ArgClusterlintExcludeChecks = "exclude-checks"
+	// ArgClusterlintRunID is the ID of a previous clusterlint run.
+	ArgClusterlintRunID = "run-id"
 	// ArgNoCache represents whether or not to omit the cache on the next command.
 	ArgNoCache = "no-cache"
 	// ArgNodePoolName is a cluster's node pool name argument.
@@ -84,6 +98,12 @@ const (
 	ArgNodePoolMaxNodes = "max-nodes"
 	// ArgNodePoolNodeIDs is a cluster's node pool nodes argument.
 	ArgNodePoolNodeIDs = "node-ids"
+	// ArgNodePoolReplaceAll selects all of a node pool's nodes for replacement.
+	ArgNodePoolReplaceAll = "all"
+	// ArgNodePoolMaxUnavailable is the number of a node pool's nodes replaced at a time.
+	ArgNodePoolMaxUnavailable = "max-unavailable"
+	// ArgNodePoolsFile is a YAML or JSON file describing a cluster's node pools.
+	ArgNodePoolsFile = "file"
 	// ArgMaintenanceWindow is a cluster's maintenance window argument
 	ArgMaintenanceWindow = "maintenance-window"
 	// ArgMajorVersion is a major version number.
@@ -104,6 +124,8 @@ const (
 	ArgDropletID = "droplet-id"
 	// ArgDropletIDs is a list of droplet IDs.
 	ArgDropletIDs = "droplet-ids"
+	// ArgDroplet is a droplet ID or name argument.
+	ArgDroplet = "droplet"
 	// ArgKernelID is a kernel id argument.
 	ArgKernelID = "kernel-id"
 	// ArgKubernetesLabel is a Kubernetes label argument.
@@ -112,6 +134,16 @@ const (
 	ArgKubernetesTaint = "taint"
 	// ArgKubernetesAlias is a Kubernetes alias argument that saves authentication information under the specified context.
 	ArgKubernetesAlias = "alias"
+	// ArgKubeconfigContextName is the name of the kubeconfig context to save a cluster's credentials under.
+	ArgKubeconfigContextName = "context-name"
+	// ArgKubeconfigNamespace is the default namespace of a kubeconfig context.
+	ArgKubeconfigNamespace = "namespace"
+	// ArgPortForwardAddress is a local address to listen on when forwarding ports to a cluster.
+	ArgPortForwardAddress = "address"
+	// ArgKubeconfigCredentialCache is the backend used to cache a cluster's exec credentials.
+	ArgKubeconfigCredentialCache = "credential-cache"
+	// ArgKubeconfigOutputFile is a standalone kubeconfig file to write a cluster's credentials to.
+	ArgKubeconfigOutputFile = "output-file"
 	// ArgKubeConfigExpirySeconds indicates the length of time the token in a kubeconfig will be valid in seconds.
 	ArgKubeConfigExpirySeconds = "expiry-seconds"
 	// ArgImage is an image argument.
@@ -263,6 +295,12 @@ const (
 	ArgVolumeFilesystemType = "fs-type"
 	// ArgVolumeFilesystemLabel is the filesystem label for a volume.
 	ArgVolumeFilesystemLabel = "fs-label"
+	// ArgVolumeFilesystem is the filesystem a volume is formatted with on a Droplet.
+	ArgVolumeFilesystem = "fs"
+	// ArgVolumeMountPath is the path a volume is mounted at on a Droplet.
+	ArgVolumeMountPath = "mount"
+	// ArgVolumeUnmountFirst unmounts a volume on its Droplet before detaching it.
+	ArgVolumeUnmountFirst = "unmount-first"
 	// ArgVolumeList is the IDs of many volumes.
 	ArgVolumeList = "volumes"
 	// ArgVolumeSnapshotList is the IDs of many volume snapshots.
@@ -310,6 +348,14 @@ const (
 	ArgHealthCheck = "health-check"
 	// ArgForwardingRules is a list of forwarding rules for the load balancer.
 	ArgForwardingRules = "forwarding-rules"
+	// ArgLoadBalancerSpec is a path to a load balancer spec, or a flag to output one.
+	ArgLoadBalancerSpec = "spec"
+	// ArgLoadBalancerPrompt is a flag for confirming load balancer spec changes before they are applied.
+	ArgLoadBalancerPrompt = "prompt"
+	// ArgLoadBalancerWatch is a flag to keep refreshing a load balancer's status.
+	ArgLoadBalancerWatch = "watch"
+	// ArgLoadBalancerWatchInterval is the number of seconds between status refreshes.
+	ArgLoadBalancerWatchInterval = "interval"
 	// ArgHTTPIdleTimeoutSeconds is the http idle time out configuration for the load balancer
 	ArgHTTPIdleTimeoutSeconds = "http-idle-timeout-seconds"
 	// ArgAllowList is the list of firewall rules for ALLOWING traffic to the loadbalancer
@@ -323,6 +369,16 @@ const (
 	ArgInboundRules = "inbound-rules"
 	// ArgOutboundRules is a list of outbound rules for the firewall.
 	ArgOutboundRules = "outbound-rules"
+	// ArgFirewallRulesFile is a YAML or JSON file containing the rules for the firewall.
+	ArgFirewallRulesFile = "rules-file"
+	// ArgFirewallPrompt is a flag for confirming firewall rule changes before they are applied
+	ArgFirewallPrompt = "prompt"
+	// ArgFirewallCheckFrom is the source address of inbound traffic to check against a firewall.
+	ArgFirewallCheckFrom = "from"
+	// ArgFirewallCheckTo is the destination address of outbound traffic to check against a firewall.
+	ArgFirewallCheckTo = "to"
+	// ArgFirewallCheckPort is the port and protocol of traffic to check against a firewall.
+	ArgFirewallCheckPort = "port"
 
 	// ArgProjectID is the ID of a project.
 	ArgProjectID = "project-id"
@@ -363,6 +419,42 @@ const (
 	ArgDatabasePoolMode = "mode"
 	// ArgDatabaseUserMySQLAuthPlugin is a flag for setting the MySQL user auth plugin
 	ArgDatabaseUserMySQLAuthPlugin = "mysql-auth-plugin"
+	// ArgDatabaseConfigJSON is a flag for specifying database engine configuration settings as JSON
+	ArgDatabaseConfigJSON = "config-json"
+	// ArgDatabaseConfigFile is a flag for specifying a file of database engine configuration settings
+	ArgDatabaseConfigFile = "config-file"
+	// ArgDatabaseUser is a flag for specifying the database user to connect as
+	ArgDatabaseUser = "user"
+	// ArgDatabaseName is a flag for specifying the database within a database cluster
+	ArgDatabaseName = "database"
+	// ArgDatabasePrivate is a flag for connecting to a database cluster over its private network
+	ArgDatabasePrivate = "private"
+	// ArgDatabaseReplica is a flag for specifying a read-only replica of a database cluster
+	ArgDatabaseReplica = "replica"
+	// ArgDatabaseReplicaConfirm is a flag for confirming the promotion of a read-only replica
+	ArgDatabaseReplicaConfirm = "confirm"
+	// ArgDatabasePool is a flag for specifying a connection pool of a database cluster
+	ArgDatabasePool = "pool"
+	// ArgDatabaseConnectionFormat is a flag for specifying the driver format of database connection details
+	ArgDatabaseConnectionFormat = "format-as"
+	// ArgDatabaseRestoreName is a flag for specifying the name of a restored database cluster
+	ArgDatabaseRestoreName = "name"
+	// ArgDatabaseRestoreTo is a flag for specifying the point in time to restore a database cluster to
+	ArgDatabaseRestoreTo = "to"
+	// ArgDatabaseRestoreToBackup is a flag for specifying the backup to restore a database cluster from
+	ArgDatabaseRestoreToBackup = "to-backup"
+	// ArgDatabaseCopyFirewallRules is a flag for copying the firewall rules of a source database cluster
+	ArgDatabaseCopyFirewallRules = "copy-firewall-rules"
+	// ArgDatabaseCopyTags is a flag for copying the tags of a source database cluster
+	ArgDatabaseCopyTags = "copy-tags"
+	// ArgDatabaseDumpOutputFile is a flag for specifying the file to write a database dump to
+	ArgDatabaseDumpOutputFile = "output-file"
+	// ArgDatabaseLoadInputFile is a flag for specifying the file to load a database dump from
+	ArgDatabaseLoadInputFile = "input-file"
+	// ArgDatabaseDumpTable is a flag for specifying the tables to dump or load
+	ArgDatabaseDumpTable = "table"
+	// ArgDatabaseDumpSchema is a flag for specifying the schemas to dump or load
+	ArgDatabaseDumpSchema = "schema"
 
 	// ArgPrivateNetworkUUID is the flag for VPC UUID
 	ArgPrivateNetworkUUID = "private-network-uuid"
@@ -388,6 +480,8 @@ const (
 	ArgReadWrite = "read-write"
 	// ArgRegistry indicates the name of the registry.
 	ArgRegistry = "registry"
+	// ArgRegistryNamespaces are the Kubernetes namespaces to sync a registry's pull secret to.
+	ArgRegistryNamespaces = "namespaces"
 	// ArgRegistryExpirySeconds indicates the length of time the token will be valid in seconds.
 	ArgRegistryExpirySeconds = "expiry-seconds"
 	// ArgRegistryReadOnly indicates that a generated registry API token should be read-only.
@@ -423,6 +517,12 @@ const (
 	// ArgDatabaseFirewallRuleUUID is the UUID for the firewall rules.
 	ArgDatabaseFirewallRuleUUID = "uuid"
 
+	// ArgDatabaseFirewallFromTag is the tag whose Droplets database firewall rules are synced with.
+	ArgDatabaseFirewallFromTag = "from-tag"
+
+	// ArgDatabaseFirewallPrune removes the database firewall rules of Droplets without the synced tag.
+	ArgDatabaseFirewallPrune = "prune"
+
 	// Monitoring Args
 
 	// ArgAlertPolicyDescription is the flag to pass in the alert policy description.
@@ -14,9 +14,16 @@ limitations under the License.
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,7 +31,9 @@ import (
 	"github.com/digitalocean/doctl/commands/displayers"
 	"github.com/digitalocean/doctl/do"
 	"github.com/digitalocean/godo"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -34,7 +43,7 @@ const (
 	defaultDatabaseEngine    = "pg"
 	databaseListDetails      = `
 
-This command requires the ID of a database cluster, which you can retrieve by calling:
+This command requires the ID or name of a database cluster, which you can retrieve by calling:
 
 	doctl databases list`
 )
@@ -63,7 +72,7 @@ func Databases() *Command {
 - The size of the machine running the database instance (e.g. ` + "`db-s-1vcpu-1gb`" + `)`
 
 	CmdBuilder(cmd, RunDatabaseList, "list", "List your database clusters", `This command lists the database clusters associated with your account. The following details are provided:`+clusterDetails, Writer, aliasOpt("ls"), displayerType(&displayers.Databases{}))
-	CmdBuilder(cmd, RunDatabaseGet, "get <database-id>", "Get details for a database cluster", `This command retrieves the following details about the specified database cluster: `+clusterDetails+`
+	cmdDatabaseGet := CmdBuilder(cmd, RunDatabaseGet, "get <database-id|database-name>", "Get details for a database cluster", `This command retrieves the following details about the specified database cluster: `+clusterDetails+`
 - A connection string for the database cluster
 - The date and time when the database cluster was created`+databaseListDetails, Writer, aliasOpt("g"), displayerType(&displayers.Databases{}))
 
@@ -83,13 +92,13 @@ There are a number of flags that customize the configuration, all of which are o
 	AddStringFlag(cmdDatabaseCreate, doctl.ArgDatabaseRestoreFromTimestamp, "", "", "The timestamp of an existing database cluster backup in UTC combined date and time format (2006-01-02 15:04:05 +0000 UTC). The most recent backup will be used if excluded.")
 	AddBoolFlag(cmdDatabaseCreate, doctl.ArgCommandWait, "", false, "Boolean that specifies whether to wait for a database to complete before returning control to the terminal")
 
-	cmdDatabaseDelete := CmdBuilder(cmd, RunDatabaseDelete, "delete <database-id>", "Delete a database cluster", `This command deletes the database cluster with the given ID.
+	cmdDatabaseDelete := CmdBuilder(cmd, RunDatabaseDelete, "delete <database-id|database-name>", "Delete a database cluster", `This command deletes the database cluster with the given ID.
 
 To retrieve a list of your database clusters and their IDs, call `+"`"+`doctl databases list`+"`"+`.`, Writer,
 		aliasOpt("rm"))
 	AddBoolFlag(cmdDatabaseDelete, doctl.ArgForce, doctl.ArgShortForce, false, "Delete the database cluster without a confirmation prompt")
 
-	CmdBuilder(cmd, RunDatabaseConnectionGet, "connection <database-id>", "Retrieve connection details for a database cluster", `This command retrieves the following connection details for a database cluster:
+	cmdDatabaseConnectionGet := CmdBuilder(cmd, RunDatabaseConnectionGet, "connection <database-id|database-name>", "Retrieve connection details for a database cluster", `This command retrieves the following connection details for a database cluster:
 
 - The connection string for the database cluster
 - The default database name
@@ -99,15 +108,22 @@ To retrieve a list of your database clusters and their IDs, call `+"`"+`doctl da
 - The randomly-generated password for the default username
 - A boolean indicating if the connection should be made over SSL
 
-While these connection details will work, you may wish to use different connection details, such as the private hostname, a custom username, or a different database.`, Writer,
+While these connection details will work, you may wish to use different connection details, such as the private hostname, a custom username, or a different database.
+
+Use the `+"`"+`--format-as`+"`"+` flag to print the connection details ready to paste into application configuration, such as a `+"`"+`.env`+"`"+` file, a JDBC URL, a Rails `+"`"+`database.yml`+"`"+` entry, or a Kubernetes Secret manifest that includes the CA certificate of the cluster. For example:
+
+	doctl databases connection sunny-db-cluster --pool orders --format-as k8s-secret | kubectl apply -f -`, Writer,
 		aliasOpt("conn"), displayerType(&displayers.DatabaseConnection{}))
+	AddStringFlag(cmdDatabaseConnectionGet, doctl.ArgDatabasePool, "", "", "The name of a connection pool to retrieve connection details for")
+	AddStringFlag(cmdDatabaseConnectionGet, doctl.ArgDatabaseReplica, "", "", "The name of a read-only replica to retrieve connection details for")
+	databaseConnectionFormatFlag(cmdDatabaseConnectionGet)
 
-	CmdBuilder(cmd, RunDatabaseBackupsList, "backups <database-id>", "List database cluster backups", `This command retrieves a list of backups created for the specified database cluster.
+	cmdDatabaseBackupsList := CmdBuilder(cmd, RunDatabaseBackupsList, "backups <database-id|database-name>", "List database cluster backups", `This command retrieves a list of backups created for the specified database cluster.
 
 The list contains the size in GB, and the date and time the backup was taken.`, Writer,
 		aliasOpt("bu"), displayerType(&displayers.DatabaseBackups{}))
 
-	cmdDatabaseResize := CmdBuilder(cmd, RunDatabaseResize, "resize <database-id>", "Resize a database cluster", `This command resizes the specified database cluster.
+	cmdDatabaseResize := CmdBuilder(cmd, RunDatabaseResize, "resize <database-id|database-name>", "Resize a database cluster", `This command resizes the specified database cluster.
 
 You must specify the desired number of nodes and size of the nodes. For example:
 
@@ -118,7 +134,7 @@ Database nodes cannot be resized to smaller sizes due to the risk of data loss.`
 	AddIntFlag(cmdDatabaseResize, doctl.ArgDatabaseNumNodes, "", 0, nodeNumberDetails, requiredOpt())
 	AddStringFlag(cmdDatabaseResize, doctl.ArgSizeSlug, "", "", nodeSizeDetails, requiredOpt())
 
-	cmdDatabaseMigrate := CmdBuilder(cmd, RunDatabaseMigrate, "migrate <database-id>", "Migrate a database cluster to a new region", `This command migrates the specified database cluster to a new region`, Writer,
+	cmdDatabaseMigrate := CmdBuilder(cmd, RunDatabaseMigrate, "migrate <database-id|database-name>", "Migrate a database cluster to a new region", `This command migrates the specified database cluster to a new region`, Writer,
 		aliasOpt("m"))
 	AddStringFlag(cmdDatabaseMigrate, doctl.ArgRegionSlug, "", "", "The region to which the database cluster should be migrated, e.g. `sfo2` or `nyc3`.", requiredOpt())
 	AddStringFlag(cmdDatabaseMigrate, doctl.ArgPrivateNetworkUUID, "", "", "The UUID of a VPC to create the database cluster in; the default VPC for the region will be used if excluded")
@@ -130,14 +146,87 @@ Database nodes cannot be resized to smaller sizes due to the risk of data loss.`
 	AddStringFlag(cmdDatabaseFork, doctl.ArgDatabaseRestoreFromTimestamp, "", "", "The timestamp of an existing database cluster backup in UTC combined date and time format (2006-01-02 15:04:05 +0000 UTC). The most recent backup will be used if excluded.")
 	AddBoolFlag(cmdDatabaseFork, doctl.ArgCommandWait, "", false, "Boolean that specifies whether to wait for a database to complete before returning control to the terminal.")
 
+	cmdDatabaseDump := CmdBuilder(cmd, RunDatabaseDump, "dump <database-id|database-name>", "Write a logical dump of a database", `This command writes a logical dump of a database in the specified cluster with `+"`"+`pg_dump`+"`"+` for PostgreSQL or `+"`"+`mysqldump`+"`"+` for MySQL, which must be installed and in your PATH. The credentials and CA certificate of the cluster are fetched for you.
+
+The dump is written as SQL to the file given with `+"`"+`--output-file`+"`"+`, or to standard output. Files ending in `+"`"+`.gz`+"`"+` are compressed with gzip, and for PostgreSQL, files ending in `+"`"+`.dump`+"`"+` are written as `+"`"+`pg_restore`+"`"+` archives. Use `+"`"+`--table`+"`"+` and `+"`"+`--schema`+"`"+` to dump only some tables or schemas, and `+"`"+`--replica`+"`"+` to take the dump from a read-only replica rather than the primary node. For example:
+
+	doctl databases dump sunny-db-cluster --database app --output-file app.sql.gz`+databaseListDetails, Writer)
+	AddStringFlag(cmdDatabaseDump, doctl.ArgDatabaseName, "", "", "The database to dump. Defaults to the default database of the cluster.")
+	AddStringFlag(cmdDatabaseDump, doctl.ArgDatabaseDumpOutputFile, "", "", "The file to write the dump to. Defaults to standard output.")
+	AddStringSliceFlag(cmdDatabaseDump, doctl.ArgDatabaseDumpTable, "", []string{}, "Only dump the given tables. Can be repeated.")
+	AddStringSliceFlag(cmdDatabaseDump, doctl.ArgDatabaseDumpSchema, "", []string{}, "Only dump the given PostgreSQL schemas. Can be repeated.")
+	AddStringFlag(cmdDatabaseDump, doctl.ArgDatabaseUser, "", "", "The database user to connect as. Defaults to the default user of the cluster.")
+	AddBoolFlag(cmdDatabaseDump, doctl.ArgDatabasePrivate, "", false, "Connect over the private network of the cluster")
+	AddStringFlag(cmdDatabaseDump, doctl.ArgDatabaseReplica, "", "", "The name of a read-only replica to take the dump from")
+
+	cmdDatabaseLoad := CmdBuilder(cmd, RunDatabaseLoad, "load <database-id|database-name>", "Load a logical dump into a database", `This command loads a dump written by `+"`"+`doctl databases dump`+"`"+`, or by `+"`"+`pg_dump`+"`"+` or `+"`"+`mysqldump`+"`"+`, into a database in the specified cluster. SQL dumps are run with `+"`"+`psql`+"`"+` or `+"`"+`mysql`+"`"+`, and `+"`"+`pg_restore`+"`"+` archives with `+"`"+`pg_restore`+"`"+`. The client must be installed and in your PATH.
+
+The dump is read from the file given with `+"`"+`--input-file`+"`"+`, or from standard input, and files ending in `+"`"+`.gz`+"`"+` are decompressed. `+"`"+`--table`+"`"+` and `+"`"+`--schema`+"`"+` can be used to load only part of a `+"`"+`pg_restore`+"`"+` archive. For example:
+
+	doctl databases load sunny-db-staging --database app --input-file app.sql.gz`+databaseListDetails, Writer)
+	AddStringFlag(cmdDatabaseLoad, doctl.ArgDatabaseName, "", "", "The database to load the dump into. Defaults to the default database of the cluster.")
+	AddStringFlag(cmdDatabaseLoad, doctl.ArgDatabaseLoadInputFile, "", "", "The file to read the dump from. Defaults to standard input.")
+	AddStringSliceFlag(cmdDatabaseLoad, doctl.ArgDatabaseDumpTable, "", []string{}, "Only load the given tables of a pg_restore archive. Can be repeated.")
+	AddStringSliceFlag(cmdDatabaseLoad, doctl.ArgDatabaseDumpSchema, "", []string{}, "Only load the given schemas of a pg_restore archive. Can be repeated.")
+	AddStringFlag(cmdDatabaseLoad, doctl.ArgDatabaseUser, "", "", "The database user to connect as. Defaults to the default user of the cluster.")
+	AddBoolFlag(cmdDatabaseLoad, doctl.ArgDatabasePrivate, "", false, "Connect over the private network of the cluster")
+	AddBoolFlag(cmdDatabaseLoad, doctl.ArgForce, doctl.ArgShortForce, false, "Load the dump without a confirmation prompt. Required when reading from standard input.")
+
+	cmdDatabaseRestore := CmdBuilder(cmd, RunDatabaseRestore, "restore <database-id|database-name>", "Restore a database cluster to a point in time as a new cluster", `This command creates a new database cluster from the backups of the specified cluster, with the same engine, version, size, region, and VPC.
+
+Use the `+"`"+`--to`+"`"+` flag to restore the cluster as it was at a point in time, given either as a timestamp such as `+"`"+`2023-02-01T17:32:15Z`+"`"+` or `+"`"+`2023-02-01 17:32:15 +0000 UTC`+"`"+`, or relative to now, such as `+"`"+`2 hours ago`+"`"+` or `+"`"+`90m ago`+"`"+`. The point in time must fall between the oldest backup of the cluster and now. Alternatively, use the `+"`"+`--to-backup`+"`"+` flag with `+"`"+`latest`+"`"+` or the timestamp of a backup listed by `+"`"+`doctl databases backups`+"`"+`. For example:
+
+	doctl databases restore sunny-db-cluster --to "2 hours ago" --name sunny-db-restored --wait
+
+The tags and firewall rules of the source cluster are only copied to the new cluster when the `+"`"+`--copy-tags`+"`"+` and `+"`"+`--copy-firewall-rules`+"`"+` flags are set. Firewall rules are copied once the new cluster is online, so `+"`"+`--copy-firewall-rules`+"`"+` waits for the cluster.`+databaseListDetails, Writer)
+	AddStringFlag(cmdDatabaseRestore, doctl.ArgDatabaseRestoreName, "", "", "The name of the new database cluster", requiredOpt())
+	AddStringFlag(cmdDatabaseRestore, doctl.ArgDatabaseRestoreTo, "", "", "The point in time to restore the database cluster to, e.g. `2023-02-01T17:32:15Z` or `2 hours ago`")
+	AddStringFlag(cmdDatabaseRestore, doctl.ArgDatabaseRestoreToBackup, "", "", "The backup to restore the database cluster from: `latest` or the timestamp of a backup")
+	AddBoolFlag(cmdDatabaseRestore, doctl.ArgDatabaseCopyTags, "", false, "Apply the tags of the source database cluster to the new cluster")
+	AddBoolFlag(cmdDatabaseRestore, doctl.ArgDatabaseCopyFirewallRules, "", false, "Copy the firewall rules of the source database cluster to the new cluster")
+	AddBoolFlag(cmdDatabaseRestore, doctl.ArgCommandWait, "", false, "Boolean that specifies whether to wait for the new database cluster to be online before returning control to the terminal.")
+
+	cmdDatabaseUpgrade := CmdBuilder(cmd, RunDatabaseUpgrade, "upgrade <database-id|database-name>", "Upgrade a database cluster to a new major version", `This command upgrades the specified database cluster to a newer major version of its engine, e.g. from PostgreSQL 14 to 15.
+
+Before the upgrade is started, the version is checked against the versions available for the engine of the cluster, and the cluster must have a backup from the last 24 hours so that its data can be restored if needed. For example:
+
+	doctl databases upgrade ca9f591d-9999-5555-a0ef-1c02d1d1e352 --version 15 --wait`+databaseListDetails, Writer, aliasOpt("up"))
+	AddStringFlag(cmdDatabaseUpgrade, doctl.ArgVersion, "", "", "The major version to upgrade the database cluster to, e.g. 15 for PostgreSQL version 15", requiredOpt())
+	AddBoolFlag(cmdDatabaseUpgrade, doctl.ArgCommandWait, "", false, "Boolean that specifies whether to wait for the upgrade to complete before returning control to the terminal")
+	AddBoolFlag(cmdDatabaseUpgrade, doctl.ArgForce, doctl.ArgShortForce, false, "Upgrade the database cluster without a confirmation prompt")
+
+	cmdDatabaseConnect := CmdBuilder(cmd, RunDatabaseConnect, "connect <database-id|database-name>", "Connect to a database cluster with its command-line client", `This command connects to the specified database cluster with the command-line client of its engine: `+"`"+`psql`+"`"+` for PostgreSQL, `+"`"+`mysql`+"`"+` for MySQL, or `+"`"+`redis-cli`+"`"+` for Redis. The client must be installed and in your PATH.
+
+The connection details and the CA certificate of the cluster are fetched for you, and the connection is made over TLS with the certificate verified. The password is passed to the client in its environment rather than on its command line. For example:
+
+	doctl databases connect sunny-db-cluster --user app --database orders`+databaseListDetails, Writer)
+	AddStringFlag(cmdDatabaseConnect, doctl.ArgDatabaseUser, "", "", "The database user to connect as. Defaults to the default user of the cluster.")
+	AddStringFlag(cmdDatabaseConnect, doctl.ArgDatabaseName, "", "", "The database to connect to. Defaults to the default database of the cluster.")
+	AddBoolFlag(cmdDatabaseConnect, doctl.ArgDatabasePrivate, "", false, "Connect over the private network of the cluster")
+	AddStringFlag(cmdDatabaseConnect, doctl.ArgDatabaseReplica, "", "", "The name of a read-only replica to connect to instead of the primary node")
+
+	cmdDatabaseGet.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseDelete.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseConnectionGet.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseBackupsList.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseResize.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseMigrate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseUpgrade.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseConnect.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseRestore.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseDump.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseLoad.AddValidArgsFunc(databaseValidArgsFunc(nil))
+
 	cmd.AddCommand(databaseReplica())
 	cmd.AddCommand(databaseMaintenanceWindow())
 	cmd.AddCommand(databaseUser())
 	cmd.AddCommand(databaseDB())
 	cmd.AddCommand(databasePool())
 	cmd.AddCommand(sqlMode())
+	cmd.AddCommand(databaseEvictionPolicy())
 	cmd.AddCommand(databaseFirewalls())
 	cmd.AddCommand(databaseOptions())
+	cmd.AddCommand(databaseConfiguration())
 
 	return cmd
 }
@@ -160,7 +249,10 @@ func RunDatabaseGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	db, err := c.Databases().Get(id)
 	if err != nil {
 		return err
@@ -197,7 +289,7 @@ func RunDatabaseCreate(c *CmdConfig) error {
 		dbs := c.Databases()
 		notice("Database creation is in progress, waiting for database to be online")
 
-		err := waitForDatabaseReady(dbs, db.ID)
+		_, err := waitForDatabaseReady(dbs, db.ID, false)
 		if err != nil {
 			return fmt.Errorf(
 				"database couldn't enter the `online` state: %v",
@@ -314,7 +406,7 @@ func RunDatabaseFork(c *CmdConfig) error {
 		dbs := c.Databases()
 		notice("Database forking is in progress, waiting for database to be online")
 
-		err := waitForDatabaseReady(dbs, db.ID)
+		_, err := waitForDatabaseReady(dbs, db.ID, false)
 		if err != nil {
 			return fmt.Errorf(
 				"database couldn't enter the `online` state: %v",
@@ -332,8 +424,6 @@ func RunDatabaseFork(c *CmdConfig) error {
 }
 
 func buildDatabaseForkRequest(c *CmdConfig) (*godo.DatabaseCreateRequest, error) {
-	r := &godo.DatabaseCreateRequest{Name: c.Args[0]}
-
 	existingDatabaseID, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseRestoreFromClusterID)
 	if err != nil {
 		return nil, err
@@ -344,31 +434,40 @@ func buildDatabaseForkRequest(c *CmdConfig) (*godo.DatabaseCreateRequest, error)
 		return nil, err
 	}
 
-	backUpRestore := &godo.DatabaseBackupRestore{}
-	backUpRestore.DatabaseName = existingDatabase.Name
 	restoreFromTimestamp, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseRestoreFromTimestamp)
 	if err != nil {
 		return nil, err
 	}
+	backupCreatedAt := ""
 	if restoreFromTimestamp != "" {
-		dateFormatted, err := convertUTCtoISO8601(restoreFromTimestamp)
+		backupCreatedAt, err = convertUTCtoISO8601(restoreFromTimestamp)
 		if err != nil {
 			return nil, err
 		}
-		backUpRestore.BackupCreatedAt = dateFormatted
 	}
 
-	r.BackupRestore = backUpRestore
-	r.EngineSlug = existingDatabase.EngineSlug
-	r.NumNodes = existingDatabase.NumNodes
-	r.SizeSlug = existingDatabase.SizeSlug
-	r.Region = existingDatabase.RegionSlug
-	r.Version = existingDatabase.VersionSlug
-	r.PrivateNetworkUUID = existingDatabase.PrivateNetworkUUID
-	r.Tags = existingDatabase.Tags
-	r.ProjectID = existingDatabase.ProjectID
+	return buildDatabaseRestoreRequest(c.Args[0], existingDatabase, backupCreatedAt), nil
+}
 
-	return r, nil
+// buildDatabaseRestoreRequest returns a request to create a database cluster
+// like an existing one from its backup at backupCreatedAt, or from its most
+// recent backup if backupCreatedAt is empty.
+func buildDatabaseRestoreRequest(name string, existingDatabase *do.Database, backupCreatedAt string) *godo.DatabaseCreateRequest {
+	return &godo.DatabaseCreateRequest{
+		Name: name,
+		BackupRestore: &godo.DatabaseBackupRestore{
+			DatabaseName:    existingDatabase.Name,
+			BackupCreatedAt: backupCreatedAt,
+		},
+		EngineSlug:         existingDatabase.EngineSlug,
+		NumNodes:           existingDatabase.NumNodes,
+		SizeSlug:           existingDatabase.SizeSlug,
+		Region:             existingDatabase.RegionSlug,
+		Version:            existingDatabase.VersionSlug,
+		PrivateNetworkUUID: existingDatabase.PrivateNetworkUUID,
+		Tags:               existingDatabase.Tags,
+		ProjectID:          existingDatabase.ProjectID,
+	}
 }
 
 func convertUTCtoISO8601(restoreFromTimestamp string) (string, error) {
@@ -388,19 +487,119 @@ func RunDatabaseDelete(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
 	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
 	if err != nil {
 		return err
 	}
 
 	if force || AskForConfirmDelete("database cluster", 1) == nil {
-		id := c.Args[0]
 		return c.Databases().Delete(id)
 	}
 
 	return errOperationAborted
 }
 
+// databaseIDize attempts to make a database cluster ID/name string be a
+// database cluster ID.
+func databaseIDize(c *CmdConfig, idOrName string) (string, error) {
+	return iDize(c, idOrName, "database", "")
+}
+
+// databaseValidArgsFunc completes the first argument of a command with the
+// names of database clusters and, when nested is given, the second argument
+// with the names it returns for the chosen cluster.
+func databaseValidArgsFunc(nested func(dbs do.DatabasesService, databaseID string) ([]string, error)) ValidArgsFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		c, err := NewCmdConfig(cmdNS(cmd), &doctl.LiveConfig{}, ioutil.Discard, args, true)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, err := databaseCompletions(c, nested)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// databaseCompletions returns the completions for the next argument of a
+// database command.
+func databaseCompletions(c *CmdConfig, nested func(dbs do.DatabasesService, databaseID string) ([]string, error)) ([]string, error) {
+	switch {
+	case len(c.Args) == 0:
+		databases, err := c.Databases().List()
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(databases))
+		for _, db := range databases {
+			names = append(names, db.Name)
+		}
+		return names, nil
+	case len(c.Args) == 1 && nested != nil:
+		databaseID, err := databaseIDize(c, c.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return nested(c.Databases(), databaseID)
+	default:
+		return nil, nil
+	}
+}
+
+func databaseUserNames(dbs do.DatabasesService, databaseID string) ([]string, error) {
+	users, err := dbs.ListUsers(databaseID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	return names, nil
+}
+
+func databasePoolNames(dbs do.DatabasesService, databaseID string) ([]string, error) {
+	pools, err := dbs.ListPools(databaseID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(pools))
+	for _, p := range pools {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+func databaseReplicaNames(dbs do.DatabasesService, databaseID string) ([]string, error) {
+	replicas, err := dbs.ListReplicas(databaseID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(replicas))
+	for _, r := range replicas {
+		names = append(names, r.Name)
+	}
+	return names, nil
+}
+
+func databaseDBNames(dbs do.DatabasesService, databaseID string) ([]string, error) {
+	dbNames, err := dbs.ListDBs(databaseID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(dbNames))
+	for _, db := range dbNames {
+		names = append(names, db.Name)
+	}
+	return names, nil
+}
+
 func displayDatabases(c *CmdConfig, short bool, dbs ...do.Database) error {
 	item := &displayers.Databases{
 		Databases: do.Databases(dbs),
@@ -415,13 +614,62 @@ func RunDatabaseConnectionGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
-	connInfo, err := c.Databases().GetConnection(id)
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	format, err := databaseConnectionFormat(c)
+	if err != nil {
+		return err
+	}
+	poolName, err := c.Doit.GetString(c.NS, doctl.ArgDatabasePool)
+	if err != nil {
+		return err
+	}
+	replicaName, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseReplica)
+	if err != nil {
+		return err
+	}
+	if poolName != "" && replicaName != "" {
+		return fmt.Errorf("specify either --%s or --%s", doctl.ArgDatabasePool, doctl.ArgDatabaseReplica)
+	}
+
+	dbs := c.Databases()
+	if format == "" && poolName == "" && replicaName == "" {
+		connInfo, err := dbs.GetConnection(id)
+		if err != nil {
+			return err
+		}
+		return displayDatabaseConnection(c, *connInfo)
+	}
+
+	db, err := dbs.Get(id)
 	if err != nil {
 		return err
 	}
+	conn, name := db.Connection, db.Name
+	switch {
+	case poolName != "":
+		pool, err := dbs.GetPool(id, poolName)
+		if err != nil {
+			return err
+		}
+		conn, name = pool.Connection, pool.Name
+	case replicaName != "":
+		replica, err := dbs.GetReplica(id, replicaName)
+		if err != nil {
+			return err
+		}
+		conn, name = replica.Connection, replica.Name
+	}
+	if conn == nil {
+		return fmt.Errorf("%s has no connection details", name)
+	}
 
-	return displayDatabaseConnection(c, *connInfo)
+	if format == "" {
+		return displayDatabaseConnection(c, do.DatabaseConnection{DatabaseConnection: conn})
+	}
+	return displayDatabaseConnectionAs(c, db, conn, name, format)
 }
 
 func displayDatabaseConnection(c *CmdConfig, conn do.DatabaseConnection) error {
@@ -435,7 +683,10 @@ func RunDatabaseBackupsList(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	backups, err := c.Databases().ListBackups(id)
 	if err != nil {
 		return err
@@ -455,7 +706,10 @@ func RunDatabaseResize(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	r, err := buildDatabaseResizeRequestFromArgs(c)
 	if err != nil {
@@ -489,7 +743,10 @@ func RunDatabaseMigrate(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	r, err := buildDatabaseMigrateRequestFromArgs(c)
 	if err != nil {
@@ -529,7 +786,7 @@ Maintenance windows are hour-long blocks of time during which DigitalOcean perfo
 		},
 	}
 
-	CmdBuilder(cmd, RunDatabaseMaintenanceGet, "get <database-id>",
+	cmdDatabaseMaintenanceGet := CmdBuilder(cmd, RunDatabaseMaintenanceGet, "get <database-id|database-name>",
 		"Retrieve details about a database cluster's maintenance windows", `This command retrieves the following information on currently-scheduled maintenance windows for the specified database cluster:
 
 - The day of the week the maintenance window occurs
@@ -540,7 +797,7 @@ To see a list of your databases and their IDs, run `+"`"+`doctl databases list`+
 		displayerType(&displayers.DatabaseMaintenanceWindow{}))
 
 	cmdDatabaseCreate := CmdBuilder(cmd, RunDatabaseMaintenanceUpdate,
-		"update <database-id>", "Update the maintenance window for a database cluster", `This command allows you to update the maintenance window for the specified database cluster.
+		"update <database-id|database-name>", "Update the maintenance window for a database cluster", `This command allows you to update the maintenance window for the specified database cluster.
 
 Maintenance windows are hour-long blocks of time during which DigitalOcean performs automatic maintenance on databases every week. During this time, health checks, security updates, version upgrades, and more are performed.
 
@@ -554,6 +811,8 @@ To see a list of your databases and their IDs, run `+"`"+`doctl databases list`+
 	AddStringFlag(cmdDatabaseCreate, doctl.ArgDatabaseMaintenanceHour, "", "",
 		"The hour in UTC when maintenance updates will be applied, in 24 hour format (e.g. '16:00')", requiredOpt())
 
+	cmdDatabaseMaintenanceGet.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseCreate.AddValidArgsFunc(databaseValidArgsFunc(nil))
 	return cmd
 }
 
@@ -565,7 +824,10 @@ func RunDatabaseMaintenanceGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	window, err := c.Databases().GetMaintenance(id)
 	if err != nil {
@@ -586,7 +848,10 @@ func RunDatabaseMaintenanceUpdate(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	r, err := buildDatabaseUpdateMaintenanceRequestFromArgs(c)
 	if err != nil {
 		return err
@@ -634,14 +899,14 @@ Database user accounts are scoped to one database cluster, to which they have fu
 Primary user accounts are created by DigitalOcean at database cluster creation time and can't be deleted. Normal user accounts are created by you. Both have administrative privileges on the database cluster.
 
 To retrieve a list of your databases and their IDs, call ` + "`" + `doctl databases list` + "`" + `.`
-	CmdBuilder(cmd, RunDatabaseUserList, "list <database-id>", "Retrieve list of database users",
+	cmdDatabaseUserList := CmdBuilder(cmd, RunDatabaseUserList, "list <database-id|database-name>", "Retrieve list of database users",
 		`This command retrieves a list of users for the specified database with the following details:`+userDetailsDesc, Writer, aliasOpt("ls"), displayerType(&displayers.DatabaseUsers{}))
-	CmdBuilder(cmd, RunDatabaseUserGet, "get <database-id> <user-name>",
+	cmdDatabaseUserGet := CmdBuilder(cmd, RunDatabaseUserGet, "get <database-id|database-name> <user-name>",
 		"Retrieve details about a database user", `This command retrieves the following details about the specified user:`+userDetailsDesc+`
 
 To retrieve a list of database users for a database, call `+"`"+`doctl databases user list <database-id>`+"`"+`.`, Writer, aliasOpt("g"),
 		displayerType(&displayers.DatabaseUsers{}))
-	cmdDatabaseUserCreate := CmdBuilder(cmd, RunDatabaseUserCreate, "create <database-id> <user-name>",
+	cmdDatabaseUserCreate := CmdBuilder(cmd, RunDatabaseUserCreate, "create <database-id|database-name> <user-name>",
 		"Create a database user", `This command creates a user with the username you specify, who will be granted access to the database cluster you specify.
 
 The user will be created with the role set to `+"`"+`normal`+"`"+`, and given an automatically-generated password.
@@ -651,15 +916,20 @@ To retrieve a list of your databases and their IDs, call `+"`"+`doctl databases
 	AddStringFlag(cmdDatabaseUserCreate, doctl.ArgDatabaseUserMySQLAuthPlugin, "", "",
 		"set auth mode for MySQL users")
 
-	CmdBuilder(cmd, RunDatabaseUserResetAuth, "reset <database-id> <user-name> <new-auth-mode>",
+	cmdDatabaseUserResetAuth := CmdBuilder(cmd, RunDatabaseUserResetAuth, "reset <database-id|database-name> <user-name> <new-auth-mode>",
 		"Resets a user's auth", "This command resets the auth password or the MySQL auth plugin for a given user. It will return the new user credentials. When resetting MySQL auth, valid values for `<new-auth-mode>` are `caching_sha2_password` and `mysql_native_password`.", Writer, aliasOpt("rs"))
 
 	cmdDatabaseUserDelete := CmdBuilder(cmd, RunDatabaseUserDelete,
-		"delete <database-id> <user-id>", "Delete a database user", `This command deletes the user with the username you specify, whose account was given access to the database cluster you specify.
+		"delete <database-id|database-name> <user-id>", "Delete a database user", `This command deletes the user with the username you specify, whose account was given access to the database cluster you specify.
 
 To retrieve a list of your databases and their IDs, call `+"`"+`doctl databases list`+"`"+`.`, Writer, aliasOpt("rm"))
 	AddBoolFlag(cmdDatabaseUserDelete, doctl.ArgForce, doctl.ArgShortForce, false, "Delete the user without a confirmation prompt")
 
+	cmdDatabaseUserList.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseUserGet.AddValidArgsFunc(databaseValidArgsFunc(databaseUserNames))
+	cmdDatabaseUserCreate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseUserResetAuth.AddValidArgsFunc(databaseValidArgsFunc(databaseUserNames))
+	cmdDatabaseUserDelete.AddValidArgsFunc(databaseValidArgsFunc(databaseUserNames))
 	return cmd
 }
 
@@ -671,7 +941,10 @@ func RunDatabaseUserList(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	users, err := c.Databases().ListUsers(id)
 	if err != nil {
@@ -687,7 +960,10 @@ func RunDatabaseUserGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	userID := c.Args[1]
 
 	user, err := c.Databases().GetUser(databaseID, userID)
@@ -704,10 +980,11 @@ func RunDatabaseUserCreate(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	var (
-		databaseID = c.Args[0]
-		userName   = c.Args[1]
-	)
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	userName := c.Args[1]
 
 	req := &godo.DatabaseCreateUserRequest{Name: userName}
 
@@ -735,10 +1012,11 @@ func RunDatabaseUserResetAuth(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	var (
-		databaseID = c.Args[0]
-		userName   = c.Args[1]
-	)
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	userName := c.Args[1]
 
 	database, err := c.Databases().Get(databaseID)
 
@@ -775,13 +1053,17 @@ func RunDatabaseUserDelete(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
 	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
 	if err != nil {
 		return err
 	}
 
 	if force || AskForConfirmDelete("database user", 1) == nil {
-		databaseID := c.Args[0]
 		userID := c.Args[1]
 		return c.Databases().DeleteUser(databaseID, userID)
 	}
@@ -989,13 +1271,13 @@ You can get a list of existing connection pools by calling:
 You can get a list of existing database clusters and their IDs by calling:
 
 	doctl databases list`
-	CmdBuilder(cmd, RunDatabasePoolList, "list <database-id>", "List connection pools for a database cluster", `This command lists the existing connection pools for the specified database. The following information will be returned:`+connectionPoolDetails,
+	cmdDatabasePoolList := CmdBuilder(cmd, RunDatabasePoolList, "list <database-id|database-name>", "List connection pools for a database cluster", `This command lists the existing connection pools for the specified database. The following information will be returned:`+connectionPoolDetails,
 		Writer, aliasOpt("ls"), displayerType(&displayers.DatabasePools{}))
-	CmdBuilder(cmd, RunDatabasePoolGet, "get <database-id> <pool-name>",
+	cmdDatabasePoolGet := CmdBuilder(cmd, RunDatabasePoolGet, "get <database-id|database-name> <pool-name>",
 		"Retrieve information about a database connection pool", `This command retrieves the following information about the specified connection pool for the specified database cluster:`+connectionPoolDetails+getPoolDetails, Writer, aliasOpt("g"),
 		displayerType(&displayers.DatabasePools{}))
 	cmdDatabasePoolCreate := CmdBuilder(cmd, RunDatabasePoolCreate,
-		"create <database-id> <pool-name>", "Create a connection pool for a database", `This command creates a connection pool for the specified database cluster and gives it the specified name.
+		"create <database-id|database-name> <pool-name>", "Create a connection pool for a database", `This command creates a connection pool for the specified database cluster and gives it the specified name.
 
 You must also use flags to specify the target database, pool size, and database user's username that will be used for the pool. An example call would be:
 
@@ -1019,12 +1301,37 @@ We recommend starting with a pool size of about half your available connections
 	AddStringFlag(cmdDatabasePoolCreate, doctl.ArgDatabasePoolDBName, "", "",
 		"The name of the specific database within the database cluster", requiredOpt())
 
+	cmdDatabasePoolUpdate := CmdBuilder(cmd, RunDatabasePoolUpdate,
+		"update <database-id|database-name> <pool-name>", "Update a connection pool for a database", `This command updates the size, mode, user, or database of the specified connection pool in place, without dropping the connections of its clients. Settings which are not specified are left unchanged. For example:
+
+	doctl databases pool update sunny-db-cluster mypool --size 20 --mode session`+getPoolDetails, Writer,
+		aliasOpt("u"))
+	AddStringFlag(cmdDatabasePoolUpdate, doctl.ArgDatabasePoolMode, "", "",
+		"The pool mode for the connection pool, e.g. `session`, `transaction`, and `statement`")
+	AddIntFlag(cmdDatabasePoolUpdate, doctl.ArgDatabasePoolSize, "", 0, "pool size")
+	AddStringFlag(cmdDatabasePoolUpdate, doctl.ArgDatabasePoolUserName, "", "",
+		"The username for the database user")
+	AddStringFlag(cmdDatabasePoolUpdate, doctl.ArgDatabasePoolDBName, "", "",
+		"The name of the specific database within the database cluster")
+
+	cmdDatabasePoolPlan := CmdBuilder(cmd, RunDatabasePoolPlan,
+		"plan <database-id|database-name>", "Compare the size of connection pools with the connection limit of a database cluster", `This command adds up the sizes of the connection pools of the specified database cluster and compares the total with the number of connections the cluster accepts. The limit is the cluster's `+"`"+`max_connections`+"`"+` setting, less 3 connections reserved for maintenance. If the cluster does not report that setting, the limit is derived from the memory of its size slug instead, at 25 connections per GiB of memory, less the 3 reserved ones.
+
+A warning is displayed if the pools are oversubscribed, in which case clients of the pools may be refused connections under load.`+getPoolDetails, Writer,
+		displayerType(&displayers.DatabasePoolPlan{}))
+
 	cmdDatabasePoolDelete := CmdBuilder(cmd, RunDatabasePoolDelete,
-		"delete <database-id> <pool-name>", "Delete a connection pool for a database", `This command deletes the specified connection pool for the specified database cluster.`+getPoolDetails, Writer,
+		"delete <database-id|database-name> <pool-name>", "Delete a connection pool for a database", `This command deletes the specified connection pool for the specified database cluster.`+getPoolDetails, Writer,
 		aliasOpt("rm"))
 	AddBoolFlag(cmdDatabasePoolDelete, doctl.ArgForce, doctl.ArgShortForce,
 		false, "Delete connection pool without confirmation prompt")
 
+	cmdDatabasePoolList.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabasePoolGet.AddValidArgsFunc(databaseValidArgsFunc(databasePoolNames))
+	cmdDatabasePoolCreate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabasePoolUpdate.AddValidArgsFunc(databaseValidArgsFunc(databasePoolNames))
+	cmdDatabasePoolPlan.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabasePoolDelete.AddValidArgsFunc(databaseValidArgsFunc(databasePoolNames))
 	return cmd
 }
 
@@ -1036,7 +1343,10 @@ func RunDatabasePoolList(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	pools, err := c.Databases().ListPools(id)
 	if err != nil {
@@ -1052,7 +1362,10 @@ func RunDatabasePoolGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	poolID := c.Args[1]
 
 	pool, err := c.Databases().GetPool(databaseID, poolID)
@@ -1069,7 +1382,10 @@ func RunDatabasePoolCreate(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	r, err := buildDatabaseCreatePoolRequestFromArgs(c)
 	if err != nil {
 		return err
@@ -1083,6 +1399,167 @@ func RunDatabasePoolCreate(c *CmdConfig) error {
 	return displayDatabasePools(c, *pool)
 }
 
+// RunDatabasePoolUpdate updates a database pool for a database cluster
+func RunDatabasePoolUpdate(c *CmdConfig) error {
+	if len(c.Args) < 2 {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	poolName := c.Args[1]
+
+	dbs := c.Databases()
+	pool, err := dbs.GetPool(databaseID, poolName)
+	if err != nil {
+		return err
+	}
+	r, err := buildDatabaseUpdatePoolRequestFromArgs(c, pool)
+	if err != nil {
+		return err
+	}
+
+	err = dbs.UpdatePool(databaseID, poolName, r)
+	if err != nil {
+		return err
+	}
+
+	pool, err = dbs.GetPool(databaseID, poolName)
+	if err != nil {
+		return err
+	}
+
+	return displayDatabasePools(c, *pool)
+}
+
+// buildDatabaseUpdatePoolRequestFromArgs starts from the current settings of
+// a pool, as the API replaces all of them, and applies the flags that are set.
+func buildDatabaseUpdatePoolRequestFromArgs(c *CmdConfig, pool *do.DatabasePool) (*godo.DatabaseUpdatePoolRequest, error) {
+	req := &godo.DatabaseUpdatePoolRequest{
+		User:     pool.User,
+		Size:     pool.Size,
+		Database: pool.Database,
+		Mode:     pool.Mode,
+	}
+
+	mode, err := c.Doit.GetString(c.NS, doctl.ArgDatabasePoolMode)
+	if err != nil {
+		return nil, err
+	}
+	size, err := c.Doit.GetInt(c.NS, doctl.ArgDatabasePoolSize)
+	if err != nil {
+		return nil, err
+	}
+	db, err := c.Doit.GetString(c.NS, doctl.ArgDatabasePoolDBName)
+	if err != nil {
+		return nil, err
+	}
+	user, err := c.Doit.GetString(c.NS, doctl.ArgDatabasePoolUserName)
+	if err != nil {
+		return nil, err
+	}
+	if mode == "" && size == 0 && db == "" && user == "" {
+		return nil, fmt.Errorf("specify at least one of --%s, --%s, --%s or --%s",
+			doctl.ArgDatabasePoolSize, doctl.ArgDatabasePoolMode, doctl.ArgDatabasePoolUserName, doctl.ArgDatabasePoolDBName)
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("--%s must be at least 1", doctl.ArgDatabasePoolSize)
+	}
+
+	if mode != "" {
+		req.Mode = mode
+	}
+	if size > 0 {
+		req.Size = size
+	}
+	if db != "" {
+		req.Database = db
+	}
+	if user != "" {
+		req.User = user
+	}
+
+	return req, nil
+}
+
+// RunDatabasePoolPlan compares the total size of the pools of a database
+// cluster with its connection limit
+func RunDatabasePoolPlan(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
+	dbs := c.Databases()
+	db, err := dbs.Get(databaseID)
+	if err != nil {
+		return err
+	}
+	if db.EngineSlug != "pg" {
+		return fmt.Errorf("connection pools are only available for PostgreSQL database clusters; %s is a %s cluster", db.Name, db.EngineSlug)
+	}
+	limit, err := databaseConnectionLimit(dbs, db)
+	if err != nil {
+		return err
+	}
+	pools, err := dbs.ListPools(databaseID)
+	if err != nil {
+		return err
+	}
+
+	plan := &displayers.DatabasePoolPlan{Pools: pools, ConnectionLimit: limit}
+	if total := plan.TotalSize(); total > limit {
+		warn("The connection pools of %s are oversubscribed: they allocate %d connections, but the cluster accepts %d. Reduce the size of its pools or resize the cluster.", db.Name, total, limit)
+	}
+
+	return c.Display(plan)
+}
+
+// databaseSlugMemory matches the memory of a database size slug, e.g. the
+// "2gb" of "db-s-1vcpu-2gb".
+var databaseSlugMemory = regexp.MustCompile(`(\d+)gb$`)
+
+// databaseConnectionLimit returns the number of connections a PostgreSQL
+// cluster accepts. The max_connections setting of the cluster is used when
+// the API reports it, and otherwise the limit is derived from its size.
+func databaseConnectionLimit(dbs do.DatabasesService, db *do.Database) (int, error) {
+	cfg, err := dbs.GetPostgreSQLConfiguration(db.ID)
+	if err != nil {
+		return 0, err
+	}
+	if cfg.MaxConnections != nil {
+		return *cfg.MaxConnections - databaseReservedConnections, nil
+	}
+	return databaseSizeConnectionLimit(db.SizeSlug)
+}
+
+// databaseReservedConnections is the number of connections of a PostgreSQL
+// cluster that are reserved for maintenance.
+const databaseReservedConnections = 3
+
+// databaseSizeConnectionLimit returns the number of connections a PostgreSQL
+// cluster of the given size accepts: 25 per GiB of memory, less those
+// reserved for maintenance.
+func databaseSizeConnectionLimit(sizeSlug string) (int, error) {
+	m := databaseSlugMemory.FindStringSubmatch(sizeSlug)
+	if m == nil {
+		return 0, fmt.Errorf("unable to determine the memory of database size %q", sizeSlug)
+	}
+	gb, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, err
+	}
+
+	return gb*25 - databaseReservedConnections, nil
+}
+
 func buildDatabaseCreatePoolRequestFromArgs(c *CmdConfig) (*godo.DatabaseCreatePoolRequest, error) {
 	req := &godo.DatabaseCreatePoolRequest{Name: c.Args[1]}
 
@@ -1119,13 +1596,17 @@ func RunDatabasePoolDelete(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
 	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
 	if err != nil {
 		return err
 	}
 
 	if force || AskForConfirmDelete("database pool", 1) == nil {
-		databaseID := c.Args[0]
 		poolID := c.Args[1]
 		return c.Databases().DeletePool(databaseID, poolID)
 	}
@@ -1159,18 +1640,22 @@ You can use these commands to create and delete databases within a cluster, or s
 		},
 	}
 
-	CmdBuilder(cmd, RunDatabaseDBList, "list <database-id>", "Retrieve a list of databases within a cluster", "This command retrieves the names of all databases being hosted in the specified database cluster."+getClusterList, Writer,
+	cmdDatabaseDBList := CmdBuilder(cmd, RunDatabaseDBList, "list <database-id|database-name>", "Retrieve a list of databases within a cluster", "This command retrieves the names of all databases being hosted in the specified database cluster."+getClusterList, Writer,
 		aliasOpt("ls"), displayerType(&displayers.DatabaseDBs{}))
-	CmdBuilder(cmd, RunDatabaseDBGet, "get <database-id> <db-name>", "Retrieve the name of a database within a cluster", "This command retrieves the name of the specified database hosted in the specified database cluster."+getClusterList+getDBList,
+	cmdDatabaseDBGet := CmdBuilder(cmd, RunDatabaseDBGet, "get <database-id|database-name> <db-name>", "Retrieve the name of a database within a cluster", "This command retrieves the name of the specified database hosted in the specified database cluster."+getClusterList+getDBList,
 		Writer, aliasOpt("g"), displayerType(&displayers.DatabaseDBs{}))
-	CmdBuilder(cmd, RunDatabaseDBCreate, "create <database-id> <db-name>",
+	cmdDatabaseDBCreate := CmdBuilder(cmd, RunDatabaseDBCreate, "create <database-id|database-name> <db-name>",
 		"Create a database within a cluster", "This command creates a database with the specified name in the specified database cluster."+getClusterList, Writer, aliasOpt("c"))
 
 	cmdDatabaseDBDelete := CmdBuilder(cmd, RunDatabaseDBDelete,
-		"delete <database-id> <db-name>", "Delete the specified database from the cluster", "This command deletes the specified database from the specified database cluster."+getClusterList+getDBList, Writer, aliasOpt("rm"))
+		"delete <database-id|database-name> <db-name>", "Delete the specified database from the cluster", "This command deletes the specified database from the specified database cluster."+getClusterList+getDBList, Writer, aliasOpt("rm"))
 	AddBoolFlag(cmdDatabaseDBDelete, doctl.ArgForce, doctl.ArgShortForce,
 		false, "Delete the database without a confirmation prompt")
 
+	cmdDatabaseDBList.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseDBGet.AddValidArgsFunc(databaseValidArgsFunc(databaseDBNames))
+	cmdDatabaseDBCreate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseDBDelete.AddValidArgsFunc(databaseValidArgsFunc(databaseDBNames))
 	return cmd
 }
 
@@ -1182,7 +1667,10 @@ func RunDatabaseDBList(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	dbs, err := c.Databases().ListDBs(id)
 	if err != nil {
@@ -1198,8 +1686,11 @@ func RunDatabaseDBGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
-	dbID := c.Args[1]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	dbID := c.Args[1]
 
 	db, err := c.Databases().GetDB(databaseID, dbID)
 	if err != nil {
@@ -1215,7 +1706,10 @@ func RunDatabaseDBCreate(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	req := &godo.DatabaseCreateDBRequest{Name: c.Args[1]}
 
 	db, err := c.Databases().CreateDB(databaseID, req)
@@ -1232,13 +1726,17 @@ func RunDatabaseDBDelete(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
 	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
 	if err != nil {
 		return err
 	}
 
 	if force || AskForConfirmDelete("database", 1) == nil {
-		databaseID := c.Args[0]
 		dbID := c.Args[1]
 		return c.Databases().DeleteDB(databaseID, dbID)
 	}
@@ -1273,10 +1771,10 @@ This command requires that you pass in the replica's name, which you can retriev
 - The region where the database cluster is located (e.g. ` + "`" + `nyc3` + "`" + `, ` + "`" + `sfo2` + "`" + `)
 - The status of the replica (possible values are ` + "`" + `forking` + "`" + ` and ` + "`" + `active` + "`" + `)
 `
-	CmdBuilder(cmd, RunDatabaseReplicaList, "list <database-id>", "Retrieve list of read-only database replicas", `Lists the following details for read-only replicas for the specified database cluster.`+replicaDetails+databaseListDetails,
+	cmdDatabaseReplicaList := CmdBuilder(cmd, RunDatabaseReplicaList, "list <database-id|database-name>", "Retrieve list of read-only database replicas", `Lists the following details for read-only replicas for the specified database cluster.`+replicaDetails+databaseListDetails,
 		Writer, aliasOpt("ls"),
 		displayerType(&displayers.DatabaseReplicas{}))
-	CmdBuilder(cmd, RunDatabaseReplicaGet, "get <database-id> <replica-name>", "Retrieve information about a read-only database replica",
+	cmdDatabaseReplicaGet := CmdBuilder(cmd, RunDatabaseReplicaGet, "get <database-id|database-name> <replica-name>", "Retrieve information about a read-only database replica",
 		`Gets the following details for the specified read-only replica for the specified database cluster:
 
 - The name of the replica
@@ -1288,7 +1786,7 @@ This command requires that you pass in the replica's name, which you can retriev
 		displayerType(&displayers.DatabaseReplicas{}))
 
 	cmdDatabaseReplicaCreate := CmdBuilder(cmd, RunDatabaseReplicaCreate,
-		"create <database-id> <replica-name>", "Create a read-only database replica", `This command creates a read-only database replica for the specified database cluster, giving it the specified name.`+databaseListDetails,
+		"create <database-id|database-name> <replica-name>", "Create a read-only database replica", `This command creates a read-only database replica for the specified database cluster, giving it the specified name.`+databaseListDetails,
 		Writer, aliasOpt("c"))
 	AddStringFlag(cmdDatabaseReplicaCreate, doctl.ArgRegionSlug, "",
 		defaultDatabaseRegion, "Specifies the region (e.g. nyc3, sfo2) in which to create the replica")
@@ -1298,22 +1796,41 @@ This command requires that you pass in the replica's name, which you can retriev
 		"", "The UUID of a VPC to create the replica in; the default VPC for the region will be used if excluded")
 
 	cmdDatabaseReplicaDelete := CmdBuilder(cmd, RunDatabaseReplicaDelete,
-		"delete <database-id> <replica-name>", "Delete a read-only database replica",
+		"delete <database-id|database-name> <replica-name>", "Delete a read-only database replica",
 		`Delete the specified read-only replica for the specified database cluster.`+howToGetReplica+databaseListDetails,
 		Writer, aliasOpt("rm"))
 	AddBoolFlag(cmdDatabaseReplicaDelete, doctl.ArgForce, doctl.ArgShortForce,
 		false, "Deletes the replica without a confirmation prompt")
 
-	CmdBuilder(cmd, RunDatabaseReplicaPromote,
-		"promote <database-id> <replica-name>", "Promote a read-only database replica to become a primary cluster",
-		`This command promotes a read-only database replica to become a primary cluster.`+howToGetReplica+databaseListDetails,
+	cmdDatabaseReplicaStatus := CmdBuilder(cmd, RunDatabaseReplicaStatus, "status <database-id|database-name>", "Compare read-only database replicas with their primary cluster",
+		`Lists the specified database cluster and its read-only replicas side by side, with the role, name, ID, region, size, status, and public and private hosts of each.`,
+		Writer, aliasOpt("s"),
+		displayerType(&displayers.DatabaseReplicaStatus{}))
+
+	cmdDatabaseReplicaPromote := CmdBuilder(cmd, RunDatabaseReplicaPromote,
+		"promote <database-id|database-name> <replica-name>", "Promote a read-only database replica to become a primary cluster",
+		`This command promotes a read-only database replica to become a primary cluster.
+
+Promotion detaches the replica from its cluster and cannot be undone, so it requires the `+"`"+`--confirm`+"`"+` flag. Once promoted, the connection strings of the new primary cluster are printed so application configuration can be updated.`+howToGetReplica+databaseListDetails,
 		Writer, aliasOpt("p"))
+	AddBoolFlag(cmdDatabaseReplicaPromote, doctl.ArgDatabaseReplicaConfirm, "", false,
+		"Confirms that the replica should be promoted to a primary cluster")
+	AddBoolFlag(cmdDatabaseReplicaPromote, doctl.ArgCommandWait, "", false,
+		"Boolean that specifies whether to wait for the promoted cluster to be online before returning control to the terminal")
 
-	CmdBuilder(cmd, RunDatabaseReplicaConnectionGet,
-		"connection <database-id> <replica-name>",
+	cmdDatabaseReplicaConnectionGet := CmdBuilder(cmd, RunDatabaseReplicaConnectionGet,
+		"connection <database-id|database-name> <replica-name>",
 		"Retrieve information for connecting to a read-only database replica",
 		`This command retrieves information for connecting to the specified read-only database replica in the specified database cluster`+howToGetReplica+databaseListDetails, Writer, aliasOpt("conn"))
-
+	databaseConnectionFormatFlag(cmdDatabaseReplicaConnectionGet)
+
+	cmdDatabaseReplicaList.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseReplicaGet.AddValidArgsFunc(databaseValidArgsFunc(databaseReplicaNames))
+	cmdDatabaseReplicaCreate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseReplicaDelete.AddValidArgsFunc(databaseValidArgsFunc(databaseReplicaNames))
+	cmdDatabaseReplicaStatus.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseReplicaPromote.AddValidArgsFunc(databaseValidArgsFunc(databaseReplicaNames))
+	cmdDatabaseReplicaConnectionGet.AddValidArgsFunc(databaseValidArgsFunc(databaseReplicaNames))
 	return cmd
 }
 
@@ -1325,7 +1842,10 @@ func RunDatabaseReplicaList(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	replicas, err := c.Databases().ListReplicas(id)
 	if err != nil {
@@ -1341,7 +1861,10 @@ func RunDatabaseReplicaGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	replicaID := c.Args[1]
 
 	replica, err := c.Databases().GetReplica(databaseID, replicaID)
@@ -1358,7 +1881,10 @@ func RunDatabaseReplicaCreate(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	r, err := buildDatabaseCreateReplicaRequestFromArgs(c)
 	if err != nil {
 		return err
@@ -1402,13 +1928,17 @@ func RunDatabaseReplicaDelete(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
 	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
 	if err != nil {
 		return err
 	}
 
 	if force || AskForConfirmDelete("database replica", 1) == nil {
-		databaseID := c.Args[0]
 		replicaID := c.Args[1]
 		return c.Databases().DeleteReplica(databaseID, replicaID)
 	}
@@ -1422,9 +1952,88 @@ func RunDatabaseReplicaPromote(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	confirm, err := c.Doit.GetBool(c.NS, doctl.ArgDatabaseReplicaConfirm)
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		return fmt.Errorf("promoting a replica detaches it from its database cluster as a separate primary cluster and cannot be undone; pass --%s to promote it", doctl.ArgDatabaseReplicaConfirm)
+	}
+	wait, err := c.Doit.GetBool(c.NS, doctl.ArgCommandWait)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	replicaID := c.Args[1]
-	return c.Databases().PromoteReplica(databaseID, replicaID)
+
+	dbs := c.Databases()
+	replica, err := dbs.GetReplica(databaseID, replicaID)
+	if err != nil {
+		return err
+	}
+
+	err = dbs.PromoteReplica(databaseID, replicaID)
+	if err != nil {
+		return err
+	}
+	notice("Replica %s is being promoted to a primary cluster", replica.Name)
+
+	conn, privateConn := replica.Connection, replica.PrivateConnection
+	if wait {
+		notice("Waiting for the promoted cluster to be online")
+		// the promoted cluster keeps the ID of the replica, but may not be
+		// found for a moment after the promotion has been requested
+		db, err := waitForDatabaseReady(dbs, replica.ID, true)
+		if err != nil {
+			return fmt.Errorf("promoted cluster couldn't enter the `online` state: %v", err)
+		}
+		if db.Connection != nil {
+			conn = db.Connection
+		}
+		if db.PrivateConnection != nil {
+			privateConn = db.PrivateConnection
+		}
+		notice("Replica %s promoted", replica.Name)
+	}
+
+	if conn != nil {
+		fmt.Fprintf(c.Out, "Connection string: %s\n", conn.URI)
+	}
+	if privateConn != nil {
+		fmt.Fprintf(c.Out, "Private connection string: %s\n", privateConn.URI)
+	}
+	notice("Use `doctl databases connection %s --format-as` to print the connection in the format of your driver", replica.Name)
+	return nil
+}
+
+// RunDatabaseReplicaStatus displays a database cluster and its read-only
+// replicas side by side
+func RunDatabaseReplicaStatus(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
+	dbs := c.Databases()
+	db, err := dbs.Get(id)
+	if err != nil {
+		return err
+	}
+	replicas, err := dbs.ListReplicas(id)
+	if err != nil {
+		return err
+	}
+
+	return c.Display(&displayers.DatabaseReplicaStatus{Primary: *db, Replicas: replicas})
 }
 
 func displayDatabaseReplicas(c *CmdConfig, short bool, replicas ...do.DatabaseReplica) error {
@@ -1441,8 +2050,30 @@ func RunDatabaseReplicaConnectionGet(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	replicaID := c.Args[1]
+	format, err := databaseConnectionFormat(c)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		db, err := c.Databases().Get(databaseID)
+		if err != nil {
+			return err
+		}
+		replica, err := c.Databases().GetReplica(databaseID, replicaID)
+		if err != nil {
+			return err
+		}
+		if replica.Connection == nil {
+			return fmt.Errorf("%s has no connection details", replica.Name)
+		}
+		return displayDatabaseConnectionAs(c, db, replica.Connection, replica.Name, format)
+	}
+
 	connInfo, err := c.Databases().GetReplicaConnection(databaseID, replicaID)
 	if err != nil {
 		return err
@@ -1467,16 +2098,18 @@ func sqlMode() *Command {
 	}
 
 	getSqlModeDesc := "This command displays the the configured SQL modes for the specified MySQL database cluster."
-	CmdBuilder(cmd, RunDatabaseGetSQLModes, "get <database-id>",
+	cmdDatabaseGetSQLModes := CmdBuilder(cmd, RunDatabaseGetSQLModes, "get <database-id|database-name>",
 		"Get a MySQL database cluster's SQL modes", getSqlModeDesc, Writer,
 		displayerType(&displayers.DatabaseSQLModes{}), aliasOpt("g"))
 	setSqlModeDesc := `This command configures the SQL modes for the specified MySQL database cluster. The SQL modes should be provided as a space separated list.
 
 This will replace the existing SQL mode configuration completely. Include all of the current values when adding a new one.
 `
-	CmdBuilder(cmd, RunDatabaseSetSQLModes, "set <database-id> <sql-mode-1> ... <sql-mode-n>",
+	cmdDatabaseSetSQLModes := CmdBuilder(cmd, RunDatabaseSetSQLModes, "set <database-id|database-name> <sql-mode-1> ... <sql-mode-n>",
 		"Set a MySQL database cluster's SQL modes", setSqlModeDesc, Writer, aliasOpt("s"))
 
+	cmdDatabaseGetSQLModes.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseSetSQLModes.AddValidArgsFunc(databaseValidArgsFunc(nil))
 	return cmd
 }
 
@@ -1487,7 +2120,10 @@ func RunDatabaseGetSQLModes(c *CmdConfig) error {
 		return err
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	sqlModes, err := c.Databases().GetSQLMode(databaseID)
 	if err != nil {
 		return err
@@ -1507,12 +2143,105 @@ func RunDatabaseSetSQLModes(c *CmdConfig) error {
 		return doctl.NewMissingArgsErr(c.NS)
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	sqlModes := c.Args[1:]
 
 	return c.Databases().SetSQLMode(databaseID, sqlModes...)
 }
 
+func databaseEvictionPolicy() *Command {
+	cmd := &Command{
+		Command: &cobra.Command{
+			Use:     "eviction-policy",
+			Aliases: []string{"ep"},
+			Short:   "Display commands to view and configure a Redis database cluster's eviction policy",
+			Long:    "The subcommands of `doctl databases eviction-policy` are used to view and configure the eviction policy of a Redis database cluster, which determines the keys that are removed when the cluster runs out of memory.",
+		},
+	}
+
+	cmdDatabaseGetEvictionPolicy := CmdBuilder(cmd, RunDatabaseGetEvictionPolicy, "get <database-id|database-name>",
+		"Get a Redis database cluster's eviction policy", "This command displays the eviction policy of the specified Redis database cluster.", Writer,
+		displayerType(&displayers.DatabaseEvictionPolicy{}), aliasOpt("g"))
+	setEvictionPolicyDesc := `This command sets the eviction policy of the specified Redis database cluster. The following policies are available:
+
+- ` + "`" + `noeviction` + "`" + `: Don't evict keys. Writes fail once memory is full.
+- ` + "`" + `allkeys_lru` + "`" + `: Evict the least recently used keys.
+- ` + "`" + `allkeys_random` + "`" + `: Evict keys at random.
+- ` + "`" + `volatile_lru` + "`" + `: Evict the least recently used keys that have an expiration set.
+- ` + "`" + `volatile_random` + "`" + `: Evict keys that have an expiration set at random.
+- ` + "`" + `volatile_ttl` + "`" + `: Evict the keys that have an expiration set and the shortest time to live.`
+	cmdDatabaseSetEvictionPolicy := CmdBuilder(cmd, RunDatabaseSetEvictionPolicy, "set <database-id|database-name> <eviction-policy>",
+		"Set a Redis database cluster's eviction policy", setEvictionPolicyDesc, Writer, aliasOpt("s"))
+
+	cmdDatabaseGetEvictionPolicy.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseSetEvictionPolicy.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	return cmd
+}
+
+// databaseEvictionPolicies are the eviction policies of Redis database clusters.
+var databaseEvictionPolicies = []string{
+	godo.EvictionPolicyNoEviction,
+	godo.EvictionPolicyAllKeysLRU,
+	godo.EvictionPolicyAllKeysRandom,
+	godo.EvictionPolicyVolatileLRU,
+	godo.EvictionPolicyVolatileRandom,
+	godo.EvictionPolicyVolatileTTL,
+}
+
+// RunDatabaseGetEvictionPolicy gets the eviction policy of a Redis database
+func RunDatabaseGetEvictionPolicy(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	policy, err := c.Databases().GetEvictionPolicy(databaseID)
+	if err != nil {
+		return err
+	}
+
+	return c.Display(&displayers.DatabaseEvictionPolicy{
+		DatabaseEvictionPolicy: policy,
+	})
+}
+
+// RunDatabaseSetEvictionPolicy sets the eviction policy of a Redis database
+func RunDatabaseSetEvictionPolicy(c *CmdConfig) error {
+	if len(c.Args) < 2 {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+	if len(c.Args) > 2 {
+		return doctl.NewTooManyArgsErr(c.NS)
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	// accept the hyphenated names Redis itself uses, e.g. allkeys-lru
+	policy := strings.ReplaceAll(strings.ToLower(c.Args[1]), "-", "_")
+
+	valid := false
+	for _, p := range databaseEvictionPolicies {
+		if p == policy {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid eviction policy %q; valid policies are %s", c.Args[1], strings.Join(databaseEvictionPolicies, ", "))
+	}
+
+	return c.Databases().SetEvictionPolicy(databaseID, policy)
+}
+
 func databaseFirewalls() *Command {
 	cmd := &Command{
 		Command: &cobra.Command{
@@ -1547,7 +2276,7 @@ Use this command to replace the firewall rules of a given database. This command
 
 This command also requires a --rule flag. You can pass in multiple --rule flags. Each rule passed in to the --rule flag must be of format type:value
 	- "type" is the type of resource that the firewall rule allows to access the database cluster. The possible values for type are:  "droplet", "k8s", "ip_addr", "tag", or "app"
-	- "value" is either the ID of the specific resource, the name of a tag applied to a group of resources, or the IP address that the firewall rule allows to access the database cluster
+	- "value" is either the ID or name of the specific resource, the name of a tag applied to a group of resources, or the IP address that the firewall rule allows to access the database cluster. The names of Droplets, Kubernetes clusters, and apps are resolved to their IDs.
 
 For example:
 
@@ -1568,7 +2297,7 @@ Use this command to append a single rule to the existing firewall rules of a giv
 
 This command also requires a --rule flag. Each rule passed in to the --rule flag must be of format type:value
 	- "type" is the type of resource that the firewall rule allows to access the database cluster. The possible values for type are:  "droplet", "k8s", "ip_addr", "tag", or "app"
-	- "value" is either the ID of the specific resource, the name of a tag applied to a group of resources, or the IP address that the firewall rule allows to access the database cluster
+	- "value" is either the ID or name of the specific resource, the name of a tag applied to a group of resources, or the IP address that the firewall rule allows to access the database cluster. The names of Droplets, Kubernetes clusters, and apps are resolved to their IDs.
 
 For example:
 
@@ -1593,14 +2322,14 @@ For example:
 This would remove the firewall rule of uuid 12345d-1234-123d-123x-123eee456e for database of id d1234-1c12-1234-b123-12345c4789
 			`
 
-	CmdBuilder(cmd, RunDatabaseFirewallRulesList, "list <database-id>", "Retrieve a list of firewall rules for a given database", firewallRuleDetails+databaseFirewallRuleDetails,
-		Writer, aliasOpt("ls"))
+	cmdDatabaseFirewallRulesList := CmdBuilder(cmd, RunDatabaseFirewallRulesList, "list <database-id|database-name>", "Retrieve a list of firewall rules for a given database", firewallRuleDetails+databaseFirewallRuleDetails,
+		Writer, aliasOpt("ls"), displayerType(&displayers.DatabaseFirewallRules{}))
 
-	cmdDatabaseFirewallUpdate := CmdBuilder(cmd, RunDatabaseFirewallRulesUpdate, "replace <db-id> --rules type:value [--rule type:value]", "Replaces the firewall rules for a given database. The rules passed in to the --rules flag will replace the firewall rules previously assigned to the database,", databaseFirewallUpdateDetails,
+	cmdDatabaseFirewallUpdate := CmdBuilder(cmd, RunDatabaseFirewallRulesUpdate, "replace <database-id|database-name> --rules type:value [--rule type:value]", "Replaces the firewall rules for a given database. The rules passed in to the --rules flag will replace the firewall rules previously assigned to the database,", databaseFirewallUpdateDetails,
 		Writer, aliasOpt("r"))
 	AddStringSliceFlag(cmdDatabaseFirewallUpdate, doctl.ArgDatabaseFirewallRule, "", []string{}, databaseFirewallRulesTxt, requiredOpt())
 
-	cmdDatabaseFirewallCreate := CmdBuilder(cmd, RunDatabaseFirewallRulesAppend, "append <db-id> --rule type:value", "Add a database firewall rule to a given database", databaseFirewallAddDetails,
+	cmdDatabaseFirewallCreate := CmdBuilder(cmd, RunDatabaseFirewallRulesAppend, "append <database-id|database-name> --rule type:value", "Add a database firewall rule to a given database", databaseFirewallAddDetails,
 		Writer, aliasOpt("a"))
 	AddStringFlag(cmdDatabaseFirewallCreate, doctl.ArgDatabaseFirewallRule, "", "", "", requiredOpt())
 
@@ -1608,6 +2337,21 @@ This would remove the firewall rule of uuid 12345d-1234-123d-123x-123eee456e for
 		Writer, aliasOpt("rm"))
 	AddStringFlag(cmdDatabaseFirewallRemove, doctl.ArgDatabaseFirewallRuleUUID, "", "", "", requiredOpt())
 
+	cmdDatabaseFirewallSync := CmdBuilder(cmd, RunDatabaseFirewallRulesSync, "sync <database-id|database-name> --from-tag <tag>", "Sync the Droplet firewall rules of a database with a tag", `Use this command to keep the firewall rules of a database in line with the Droplets carrying a tag. A `+"`"+`droplet`+"`"+` rule is added for each Droplet with the tag. The `+"`"+`droplet`+"`"+` rules of Droplets without the tag, such as Droplets that were allowed individually, are kept unless the `+"`"+`--prune`+"`"+` flag is set. Rules of other types, such as `+"`"+`ip_addr`+"`"+` or `+"`"+`tag`+"`"+` rules, are left unchanged.
+
+The changes are displayed and must be confirmed before they are applied. For example:
+
+	doctl databases firewalls sync d1234-1c12-1234-b123-12345c4789 --from-tag web`,
+		Writer, aliasOpt("s"))
+	AddStringFlag(cmdDatabaseFirewallSync, doctl.ArgDatabaseFirewallFromTag, "", "", "The tag whose Droplets are allowed to access the database", requiredOpt())
+	AddBoolFlag(cmdDatabaseFirewallSync, doctl.ArgDatabaseFirewallPrune, "", false, "Remove the `droplet` rules of Droplets that don't have the tag")
+	AddBoolFlag(cmdDatabaseFirewallSync, doctl.ArgForce, doctl.ArgShortForce, false, "Apply the changes without a confirmation prompt")
+
+	cmdDatabaseFirewallRulesList.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseFirewallUpdate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseFirewallCreate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseFirewallRemove.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseFirewallSync.AddValidArgsFunc(databaseValidArgsFunc(nil))
 	return cmd
 
 }
@@ -1644,7 +2388,10 @@ func RunDatabaseFirewallRulesList(c *CmdConfig) error {
 		return err
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	return displayDatabaseFirewallRules(c, true, id)
 }
@@ -1656,7 +2403,10 @@ func RunDatabaseFirewallRulesUpdate(c *CmdConfig) error {
 		return err
 	}
 
-	id := c.Args[0]
+	id, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	r, err := buildDatabaseUpdateFirewallRulesRequestFromArgs(c)
 	if err != nil {
 		return err
@@ -1688,6 +2438,11 @@ func buildDatabaseUpdateFirewallRulesRequestFromArgs(c *CmdConfig) (*godo.Databa
 	if err != nil {
 		return nil, err
 	}
+	for _, rule := range firewallRulesList {
+		if err := resolveFirewallRule(c, rule); err != nil {
+			return nil, err
+		}
+	}
 	r.Rules = firewallRulesList
 
 	return r, nil
@@ -1723,7 +2478,10 @@ func RunDatabaseFirewallRulesAppend(c *CmdConfig) error {
 		return err
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 	firewallRuleArg, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseFirewallRule)
 	if err != nil {
 		return err
@@ -1734,15 +2492,17 @@ func RunDatabaseFirewallRulesAppend(c *CmdConfig) error {
 		return fmt.Errorf("Unexpected input value [%v], must be a key:value pair", pair)
 	}
 
-	// Slice will house old rules and new rule
-	allRules := []*godo.DatabaseFirewallRule{}
-
-	// Adding new rule to slice.
-	allRules = append(allRules, &godo.DatabaseFirewallRule{
+	newRule := &godo.DatabaseFirewallRule{
 		Type:        pair[0],
 		Value:       pair[1],
 		ClusterUUID: databaseID,
-	})
+	}
+	if err := resolveFirewallRule(c, newRule); err != nil {
+		return err
+	}
+
+	// Slice will house old rules and new rule
+	allRules := []*godo.DatabaseFirewallRule{newRule}
 
 	// Retrieve any existing firewall rules so that we don't destroy existing
 	// rules in the create request.
@@ -1780,7 +2540,10 @@ func RunDatabaseFirewallRulesRemove(c *CmdConfig) error {
 		return err
 	}
 
-	databaseID := c.Args[0]
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
 
 	firewallRuleUUIDArg, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseFirewallRuleUUID)
 	if err != nil {
@@ -1818,7 +2581,568 @@ func RunDatabaseFirewallRulesRemove(c *CmdConfig) error {
 	return displayDatabaseFirewallRules(c, true, databaseID)
 }
 
-func waitForDatabaseReady(dbs do.DatabasesService, dbID string) error {
+// resolveFirewallRule replaces the name of a Droplet, Kubernetes cluster or app
+// in a firewall rule with the ID the API expects.
+func resolveFirewallRule(c *CmdConfig, rule *godo.DatabaseFirewallRule) error {
+	var err error
+	switch rule.Type {
+	case "droplet":
+		if _, convErr := strconv.Atoi(rule.Value); convErr != nil {
+			rule.Value, err = iDize(c, rule.Value, "droplet", "")
+		}
+	case "k8s":
+		rule.Value, err = clusterIDize(c, rule.Value)
+	case "app":
+		rule.Value, err = iDize(c, rule.Value, "app", "")
+	}
+	return err
+}
+
+// RunDatabaseFirewallRulesSync adds droplet firewall rules to a database
+// cluster for the Droplets carrying a tag, and with --prune removes the rules
+// of Droplets without it.
+func RunDatabaseFirewallRulesSync(c *CmdConfig) error {
+	err := firewallRulesArgumentCheck(c)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	tag, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseFirewallFromTag)
+	if err != nil {
+		return err
+	}
+	if tag == "" {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+	prune, err := c.Doit.GetBool(c.NS, doctl.ArgDatabaseFirewallPrune)
+	if err != nil {
+		return err
+	}
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	droplets, err := c.Droplets().ListByTag(tag)
+	if err != nil {
+		return err
+	}
+	rules, err := c.Databases().GetFirewallRules(databaseID)
+	if err != nil {
+		return err
+	}
+
+	tagged := make(map[string]string, len(droplets))
+	for _, d := range droplets {
+		tagged[strconv.Itoa(d.ID)] = d.Name
+	}
+
+	var (
+		synced  []*godo.DatabaseFirewallRule
+		removed []string
+		changes []fieldChange
+		allowed = map[string]bool{}
+	)
+	for _, rule := range rules {
+		if rule.Type == "droplet" {
+			if _, ok := tagged[rule.Value]; !ok && prune {
+				removed = append(removed, rule.Value)
+				continue
+			}
+			allowed[rule.Value] = true
+		}
+		synced = append(synced, &godo.DatabaseFirewallRule{
+			UUID:        rule.UUID,
+			ClusterUUID: rule.ClusterUUID,
+			Type:        rule.Type,
+			Value:       rule.Value,
+		})
+	}
+	if len(removed) > 0 {
+		all, err := c.Droplets().List()
+		if err != nil {
+			return err
+		}
+		names := make(map[string]string, len(all))
+		for _, d := range all {
+			names[strconv.Itoa(d.ID)] = d.Name
+		}
+		for _, id := range removed {
+			from := id
+			if name, ok := names[id]; ok {
+				from = fmt.Sprintf("%s (%s)", id, name)
+			}
+			changes = append(changes, fieldChange{Path: "droplet", From: from})
+		}
+	}
+	for _, d := range droplets {
+		id := strconv.Itoa(d.ID)
+		if allowed[id] {
+			continue
+		}
+		allowed[id] = true
+		changes = append(changes, fieldChange{Path: "droplet", To: fmt.Sprintf("%s (%s)", id, d.Name)})
+		synced = append(synced, &godo.DatabaseFirewallRule{
+			ClusterUUID: databaseID,
+			Type:        "droplet",
+			Value:       id,
+		})
+	}
+
+	fmt.Fprintf(color.Output, "Changes to the firewall rules of database cluster %s to match tag %s:\n", c.Args[0], tag)
+	writeFieldChanges(color.Output, changes)
+	if len(changes) == 0 {
+		return displayDatabaseFirewallRules(c, true, databaseID)
+	}
+
+	if !force && AskForConfirm("apply these changes to the firewall rules?") != nil {
+		return errOperationAborted
+	}
+
+	if err := c.Databases().UpdateFirewallRules(databaseID, &godo.DatabaseUpdateFirewallRulesRequest{
+		Rules: synced,
+	}); err != nil {
+		return err
+	}
+
+	return displayDatabaseFirewallRules(c, true, databaseID)
+}
+
+func databaseConfiguration() *Command {
+	cmd := &Command{
+		Command: &cobra.Command{
+			Use:     "configuration",
+			Aliases: []string{"config", "cfg"},
+			Short:   "Display commands to view and update the engine configuration of database clusters",
+			Long:    "The subcommands of `doctl databases configuration` are used to view and tune the engine settings of PostgreSQL, MySQL, and Redis database clusters, such as `work_mem` or `redis_maxmemory_policy`.",
+		},
+	}
+
+	engineDesc := "The database engine of the cluster: `pg`, `mysql`, or `redis`. The command fails if it does not match the engine of the cluster."
+
+	cmdDatabaseConfigurationGet := CmdBuilder(cmd, RunDatabaseConfigurationGet, "get <database-id|database-name>",
+		"Get the engine configuration of a database cluster", `This command displays the engine settings that have been configured for the specified database cluster. Settings left at their default values are not shown.`+databaseListDetails, Writer, aliasOpt("g"),
+		displayerType(&displayers.DatabaseConfiguration{}))
+	AddStringFlag(cmdDatabaseConfigurationGet, doctl.ArgDatabaseEngine, "", "", engineDesc)
+
+	cmdDatabaseConfigurationUpdate := CmdBuilder(cmd, RunDatabaseConfigurationUpdate, "update <database-id|database-name>",
+		"Update the engine configuration of a database cluster", `This command updates engine settings of the specified database cluster. Provide the settings to change as a JSON object with the `+"`"+`--config-json`+"`"+` flag, or in a JSON or YAML file with the `+"`"+`--config-file`+"`"+` flag. Settings that are not included are left unchanged.
+
+The settings are checked against those supported by the engine of the cluster, and the changes to the current configuration are shown for confirmation before they are applied. For example:
+
+	doctl databases configuration update ca9f591d-f38h-5555-a0ef-1c02d1d1e35 --config-json '{"work_mem": 16, "jit": false}'`+databaseListDetails, Writer, aliasOpt("u"))
+	AddStringFlag(cmdDatabaseConfigurationUpdate, doctl.ArgDatabaseEngine, "", "", engineDesc)
+	AddStringFlag(cmdDatabaseConfigurationUpdate, doctl.ArgDatabaseConfigJSON, "", "", "The settings to update, as a JSON object")
+	AddStringFlag(cmdDatabaseConfigurationUpdate, doctl.ArgDatabaseConfigFile, "", "", "The path to a JSON or YAML file of the settings to update. Use `-` to read from standard input.")
+	AddBoolFlag(cmdDatabaseConfigurationUpdate, doctl.ArgForce, doctl.ArgShortForce, false, "Update the configuration without a confirmation prompt")
+
+	cmdDatabaseConfigurationGet.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	cmdDatabaseConfigurationUpdate.AddValidArgsFunc(databaseValidArgsFunc(nil))
+	return cmd
+}
+
+// databaseConfigurationEngine gets and updates the configuration of the
+// clusters of one database engine.
+type databaseConfigurationEngine struct {
+	// newConfig returns an empty configuration of the engine's type.
+	newConfig func() interface{}
+	get       func(dbs do.DatabasesService, databaseID string) (interface{}, error)
+	update    func(dbs do.DatabasesService, databaseID string, cfg interface{}) error
+}
+
+var databaseConfigurationEngines = map[string]databaseConfigurationEngine{
+	"pg": {
+		newConfig: func() interface{} { return &godo.PostgreSQLConfig{} },
+		get: func(dbs do.DatabasesService, databaseID string) (interface{}, error) {
+			cfg, err := dbs.GetPostgreSQLConfiguration(databaseID)
+			if err != nil {
+				return nil, err
+			}
+			return cfg.PostgreSQLConfig, nil
+		},
+		update: func(dbs do.DatabasesService, databaseID string, cfg interface{}) error {
+			return dbs.UpdatePostgreSQLConfiguration(databaseID, cfg.(*godo.PostgreSQLConfig))
+		},
+	},
+	"mysql": {
+		newConfig: func() interface{} { return &godo.MySQLConfig{} },
+		get: func(dbs do.DatabasesService, databaseID string) (interface{}, error) {
+			cfg, err := dbs.GetMySQLConfiguration(databaseID)
+			if err != nil {
+				return nil, err
+			}
+			return cfg.MySQLConfig, nil
+		},
+		update: func(dbs do.DatabasesService, databaseID string, cfg interface{}) error {
+			return dbs.UpdateMySQLConfiguration(databaseID, cfg.(*godo.MySQLConfig))
+		},
+	},
+	"redis": {
+		newConfig: func() interface{} { return &godo.RedisConfig{} },
+		get: func(dbs do.DatabasesService, databaseID string) (interface{}, error) {
+			cfg, err := dbs.GetRedisConfiguration(databaseID)
+			if err != nil {
+				return nil, err
+			}
+			return cfg.RedisConfig, nil
+		},
+		update: func(dbs do.DatabasesService, databaseID string, cfg interface{}) error {
+			return dbs.UpdateRedisConfiguration(databaseID, cfg.(*godo.RedisConfig))
+		},
+	},
+}
+
+// databaseConfigurationEngineFor returns the configuration engine of the
+// database cluster. An engine given by flag must match the cluster's engine.
+func databaseConfigurationEngineFor(c *CmdConfig, databaseID string) (databaseConfigurationEngine, error) {
+	engine, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseEngine)
+	if err != nil {
+		return databaseConfigurationEngine{}, err
+	}
+	db, err := c.Databases().Get(databaseID)
+	if err != nil {
+		return databaseConfigurationEngine{}, err
+	}
+	if engine != "" && engine != db.EngineSlug {
+		return databaseConfigurationEngine{}, fmt.Errorf("database cluster %s is a %s cluster, not %s", databaseID, db.EngineSlug, engine)
+	}
+	engine = db.EngineSlug
+
+	e, ok := databaseConfigurationEngines[engine]
+	if !ok {
+		return databaseConfigurationEngine{}, fmt.Errorf("configuration is not supported for %s database clusters; supported engines are pg, mysql and redis", engine)
+	}
+	return e, nil
+}
+
+// RunDatabaseConfigurationGet displays the engine configuration of a database cluster
+func RunDatabaseConfigurationGet(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	engine, err := databaseConfigurationEngineFor(c, databaseID)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := engine.get(c.Databases(), databaseID)
+	if err != nil {
+		return err
+	}
+
+	return c.Display(&displayers.DatabaseConfiguration{DatabaseConfiguration: cfg})
+}
+
+// RunDatabaseConfigurationUpdate updates the engine configuration of a
+// database cluster after showing the changes to its current settings.
+func RunDatabaseConfigurationUpdate(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	configJSON, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseConfigJSON)
+	if err != nil {
+		return err
+	}
+	configPath, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseConfigFile)
+	if err != nil {
+		return err
+	}
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	engine, err := databaseConfigurationEngineFor(c, databaseID)
+	if err != nil {
+		return err
+	}
+
+	desired := engine.newConfig()
+	if err := readDatabaseConfiguration(os.Stdin, configJSON, configPath, desired); err != nil {
+		return err
+	}
+
+	dbs := c.Databases()
+	current, err := engine.get(dbs, databaseID)
+	if err != nil {
+		return err
+	}
+
+	currentFields, err := toJSONObject(current)
+	if err != nil {
+		return err
+	}
+	patch, err := toJSONObject(desired)
+	if err != nil {
+		return err
+	}
+	changes, err := diffFields(currentFields, mergeJSONFields(currentFields, patch, nil), nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(color.Output, "Changes to the configuration of database cluster %s:\n", databaseID)
+	writeFieldChanges(color.Output, changes)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if !force && AskForConfirm("apply these changes to the database configuration?") != nil {
+		return errOperationAborted
+	}
+
+	return engine.update(dbs, databaseID, desired)
+}
+
+// readDatabaseConfiguration reads configuration settings given as JSON, or in
+// a JSON or YAML file, into cfg. Settings the engine does not support are
+// rejected.
+func readDatabaseConfiguration(stdin io.Reader, configJSON, path string, cfg interface{}) error {
+	if (configJSON == "") == (path == "") {
+		return fmt.Errorf("specify either --%s or --%s", doctl.ArgDatabaseConfigJSON, doctl.ArgDatabaseConfigFile)
+	}
+
+	b := []byte(configJSON)
+	if path != "" {
+		var r io.Reader
+		if path == "-" && stdin != nil {
+			r = stdin
+		} else {
+			f, err := os.Open(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return fmt.Errorf("opening database configuration: %s does not exist", path)
+				}
+				return fmt.Errorf("opening database configuration: %w", err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		var err error
+		b, err = ioutil.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("reading database configuration: %w", err)
+		}
+	}
+
+	jsonConfig, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return fmt.Errorf("parsing database configuration: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonConfig))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("parsing database configuration: %w", err)
+	}
+
+	fields, err := toJSONObject(cfg)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return errors.New("the database configuration has no settings to update")
+	}
+
+	return nil
+}
+
+const (
+	// databaseUpgradeBackupMaxAge is how recent the last backup of a database
+	// cluster must be for the cluster to be upgraded.
+	databaseUpgradeBackupMaxAge = 24 * time.Hour
+)
+
+// databaseUpgradePollInterval is how often the progress of a database cluster
+// upgrade is checked.
+var databaseUpgradePollInterval = 10 * time.Second
+
+// RunDatabaseUpgrade upgrades a database cluster to a new major version
+func RunDatabaseUpgrade(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	version, err := c.Doit.GetString(c.NS, doctl.ArgVersion)
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		return doctl.NewMissingArgsErr(fmt.Sprintf("%s.%s", c.NS, doctl.ArgVersion))
+	}
+	wait, err := c.Doit.GetBool(c.NS, doctl.ArgCommandWait)
+	if err != nil {
+		return err
+	}
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	dbs := c.Databases()
+	db, err := dbs.Get(databaseID)
+	if err != nil {
+		return err
+	}
+	options, err := dbs.ListOptions()
+	if err != nil {
+		return err
+	}
+	if err := validateDatabaseUpgradeVersion(db, options, version); err != nil {
+		return err
+	}
+
+	backups, err := dbs.ListBackups(databaseID)
+	if err != nil {
+		return err
+	}
+	var latest *do.DatabaseBackup
+	for i := range backups {
+		if latest == nil || backups[i].CreatedAt.After(latest.CreatedAt) {
+			latest = &backups[i]
+		}
+	}
+	if latest == nil {
+		return fmt.Errorf("database cluster %s has no backups; wait for its first backup before upgrading it", db.Name)
+	}
+	if time.Since(latest.CreatedAt) > databaseUpgradeBackupMaxAge {
+		return fmt.Errorf("the most recent backup of database cluster %s is from %s, more than a day ago; wait for its next daily backup before upgrading it",
+			db.Name, latest.CreatedAt.UTC().Format(time.RFC3339))
+	}
+	notice("The most recent backup of database cluster %s is from %s", db.Name, latest.CreatedAt.UTC().Format(time.RFC3339))
+
+	if !force && AskForConfirm(fmt.Sprintf("upgrade database cluster %s from version %s to %s?", db.Name, db.VersionSlug, version)) != nil {
+		return errOperationAborted
+	}
+
+	if err := dbs.UpgradeMajorVersion(databaseID, &godo.UpgradeVersionRequest{Version: version}); err != nil {
+		return err
+	}
+
+	if !wait {
+		notice("Database upgrade is in progress")
+		return nil
+	}
+
+	notice("Database upgrade is in progress, waiting for database to be online with version %s", version)
+	db, err = waitForDatabaseUpgrade(dbs, databaseID, version)
+	if err != nil {
+		return fmt.Errorf("database couldn't complete the upgrade: %v", err)
+	}
+
+	notice("Database upgraded")
+
+	return displayDatabases(c, false, *db)
+}
+
+// validateDatabaseUpgradeVersion checks that a version is available for the
+// engine of a database cluster and is newer than its current version.
+func validateDatabaseUpgradeVersion(db *do.Database, options *do.DatabaseOptions, version string) error {
+	var versions []string
+	switch db.EngineSlug {
+	case "mongodb":
+		versions = options.MongoDBOptions.Versions
+	case "mysql":
+		versions = options.MySQLOptions.Versions
+	case "pg":
+		versions = options.PostgresSQLOptions.Versions
+	case "redis":
+		versions = options.RedisOptions.Versions
+	}
+
+	available := false
+	for _, v := range versions {
+		if v == version {
+			available = true
+			break
+		}
+	}
+	if !available {
+		return fmt.Errorf("version %s is not available for %s database clusters; available versions are %s", version, db.EngineSlug, strings.Join(versions, ", "))
+	}
+
+	current, err := strconv.ParseFloat(db.VersionSlug, 64)
+	if err != nil {
+		return fmt.Errorf("unable to parse the version %q of database cluster %s: %v", db.VersionSlug, db.Name, err)
+	}
+	target, err := strconv.ParseFloat(version, 64)
+	if err != nil {
+		return fmt.Errorf("unable to parse version %q: %v", version, err)
+	}
+	if target <= current {
+		return fmt.Errorf("database cluster %s is on version %s; it can only be upgraded to a newer version", db.Name, db.VersionSlug)
+	}
+
+	return nil
+}
+
+// waitForDatabaseUpgrade waits for a database cluster to be online with the
+// version it is being upgraded to.
+func waitForDatabaseUpgrade(dbs do.DatabasesService, dbID, version string) (*do.Database, error) {
+	const (
+		maxAttempts = 360
+		wantStatus  = "online"
+	)
+	printNewLineSet := false
+
+	for i := 0; i < maxAttempts; i++ {
+		if i != 0 {
+			fmt.Fprint(os.Stderr, ".")
+			if !printNewLineSet {
+				printNewLineSet = true
+				defer fmt.Fprintln(os.Stderr)
+			}
+		}
+
+		// the cluster may still report its old version for a moment after
+		// the upgrade has been requested
+		time.Sleep(databaseUpgradePollInterval)
+
+		db, err := dbs.Get(dbID)
+		if err != nil {
+			return nil, err
+		}
+
+		if db.VersionSlug == version && db.Status == wantStatus {
+			return db, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"timeout waiting for database (%s) to be `online` with version %s",
+		dbID, version,
+	)
+}
+
+// databaseReadyPollInterval is how often a database cluster is checked for
+// being online.
+var databaseReadyPollInterval = 10 * time.Second
+
+// waitForDatabaseReady waits for a database cluster to be online and returns
+// it. With allowNotFound, a cluster that is not found yet is waited for.
+func waitForDatabaseReady(dbs do.DatabasesService, dbID string, allowNotFound bool) (*do.Database, error) {
 	const (
 		maxAttempts = 180
 		wantStatus  = "online"
@@ -1837,18 +3161,20 @@ func waitForDatabaseReady(dbs do.DatabasesService, dbID string) error {
 
 		db, err := dbs.Get(dbID)
 		if err != nil {
-			return err
-		}
-
-		if db.Status == wantStatus {
-			return nil
+			var errResp *godo.ErrorResponse
+			notFound := errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+			if !allowNotFound || !notFound {
+				return nil, err
+			}
+		} else if db.Status == wantStatus {
+			return db, nil
 		}
 
 		attempts++
-		time.Sleep(10 * time.Second)
+		time.Sleep(databaseReadyPollInterval)
 	}
 
-	return fmt.Errorf(
+	return nil, fmt.Errorf(
 		"timeout waiting for database (%s) to enter `online` state",
 		dbID,
 	)
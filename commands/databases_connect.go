@@ -0,0 +1,219 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+)
+
+// RunDatabaseConnect connects to a database cluster with the command-line
+// client of its engine, using credentials fetched from the API.
+func RunDatabaseConnect(c *CmdConfig) error {
+	db, conn, caPath, cleanup, err := databaseClientConnection(c)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	name, args, env, err := databaseClientCommand(db.EngineSlug, conn, caPath)
+	if err != nil {
+		return err
+	}
+
+	cmd := execCommand(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = c.Out
+	cmd.Stderr = os.Stderr
+	return runDatabaseClient(cmd, name, db.EngineSlug)
+}
+
+// runDatabaseClient runs a database client, explaining how to fix it if the
+// client is not installed.
+func runDatabaseClient(cmd *exec.Cmd, name, engine string) error {
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%s was not found in your PATH; install it to connect to %s database clusters", name, engine)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// databaseClientConnection fetches the connection details of the database
+// cluster given as the command's argument for use by a command-line client,
+// applying the --user, --database, --private and --replica flags. The CA
+// certificate of the cluster is written to a temporary file, which the
+// returned function removes.
+func databaseClientConnection(c *CmdConfig) (*do.Database, *godo.DatabaseConnection, string, func(), error) {
+	noop := func() {}
+
+	err := ensureOneArg(c)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+	databaseID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+
+	userName, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseUser)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+	dbName, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseName)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+	private, err := c.Doit.GetBool(c.NS, doctl.ArgDatabasePrivate)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+	replicaName, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseReplica)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+
+	dbs := c.Databases()
+	db, err := dbs.Get(databaseID)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+	conn, err := databaseConnectConnection(dbs, db, replicaName, private)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+
+	if userName != "" && userName != conn.User {
+		user, err := dbs.GetUser(databaseID, userName)
+		if err != nil {
+			return nil, nil, "", noop, err
+		}
+		if user.Password == "" {
+			return nil, nil, "", noop, fmt.Errorf("the password of database user %s is not available; reset it with `doctl databases user reset` to connect as this user", userName)
+		}
+		conn.User = user.Name
+		conn.Password = user.Password
+	}
+	if dbName != "" {
+		conn.Database = dbName
+	}
+
+	ca, err := dbs.GetCA(databaseID)
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+	caFile, err := os.CreateTemp("", "doctl-database-ca-*.crt")
+	if err != nil {
+		return nil, nil, "", noop, err
+	}
+	cleanup := func() { os.Remove(caFile.Name()) }
+	_, err = caFile.Write(ca.Certificate)
+	if closeErr := caFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, "", noop, fmt.Errorf("writing the CA certificate of database cluster %s: %v", db.Name, err)
+	}
+
+	return db, conn, caFile.Name(), cleanup, nil
+}
+
+// databaseConnectConnection returns a copy of the connection details of a
+// database cluster, or of one of its read-only replicas.
+func databaseConnectConnection(dbs do.DatabasesService, db *do.Database, replicaName string, private bool) (*godo.DatabaseConnection, error) {
+	conn, privateConn, target := db.Connection, db.PrivateConnection, "database cluster "+db.Name
+	if replicaName != "" {
+		replica, err := dbs.GetReplica(db.ID, replicaName)
+		if err != nil {
+			return nil, err
+		}
+		conn, privateConn, target = replica.Connection, replica.PrivateConnection, "replica "+replica.Name
+	}
+	if private {
+		conn = privateConn
+	}
+	if conn == nil {
+		kind := "connection"
+		if private {
+			kind = "private connection"
+		}
+		return nil, fmt.Errorf("%s has no %s details", target, kind)
+	}
+
+	copied := *conn
+	return &copied, nil
+}
+
+// databaseClientCommand returns the command-line client of a database engine
+// with the arguments and environment to connect it over TLS. Passwords are
+// passed in the environment so they don't show up in process listings.
+func databaseClientCommand(engine string, conn *godo.DatabaseConnection, caPath string) (string, []string, []string, error) {
+	env := databaseClientEnv(engine, conn, caPath)
+
+	switch engine {
+	case "pg":
+		return "psql", pgClientArgs(conn), env, nil
+	case "mysql":
+		args := mysqlClientArgs(conn, caPath)
+		if conn.Database != "" {
+			args = append(args, conn.Database)
+		}
+		return "mysql", args, env, nil
+	case "redis":
+		args := []string{"-h", conn.Host, "-p", strconv.Itoa(conn.Port), "--tls", "--cacert", caPath}
+		if conn.User != "" {
+			args = append(args, "--user", conn.User)
+		}
+		return "redis-cli", args, env, nil
+	default:
+		return "", nil, nil, fmt.Errorf("connecting is not supported for %s database clusters; supported engines are pg, mysql and redis", engine)
+	}
+}
+
+// databaseClientEnv returns the environment that passes the password and TLS
+// settings of a connection to the clients of a database engine.
+func databaseClientEnv(engine string, conn *godo.DatabaseConnection, caPath string) []string {
+	switch engine {
+	case "pg":
+		return []string{"PGPASSWORD=" + conn.Password, "PGSSLMODE=verify-full", "PGSSLROOTCERT=" + caPath}
+	case "mysql":
+		return []string{"MYSQL_PWD=" + conn.Password}
+	case "redis":
+		return []string{"REDISCLI_AUTH=" + conn.Password}
+	}
+	return nil
+}
+
+// pgClientArgs returns the connection arguments shared by the PostgreSQL
+// clients.
+func pgClientArgs(conn *godo.DatabaseConnection) []string {
+	return []string{"--host", conn.Host, "--port", strconv.Itoa(conn.Port), "--username", conn.User, "--dbname", conn.Database}
+}
+
+// mysqlClientArgs returns the connection arguments shared by the MySQL
+// clients.
+func mysqlClientArgs(conn *godo.DatabaseConnection, caPath string) []string {
+	return []string{"--host", conn.Host, "--port", strconv.Itoa(conn.Port), "--user", conn.User, "--ssl-mode=VERIFY_IDENTITY", "--ssl-ca=" + caPath}
+}
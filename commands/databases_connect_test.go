@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseClientCommand(t *testing.T) {
+	conn := &godo.DatabaseConnection{
+		Host:     "foo-foobar-do-user-1-0.db.ondigitalocean.com",
+		Port:     25060,
+		User:     "doadmin",
+		Password: "foobaz",
+		Database: "defaultdb",
+	}
+
+	tests := []struct {
+		engine string
+		name   string
+		args   []string
+		env    []string
+		err    string
+	}{
+		{
+			engine: "pg",
+			name:   "psql",
+			args:   []string{"--host", conn.Host, "--port", "25060", "--username", "doadmin", "--dbname", "defaultdb"},
+			env:    []string{"PGPASSWORD=foobaz", "PGSSLMODE=verify-full", "PGSSLROOTCERT=/tmp/ca.crt"},
+		},
+		{
+			engine: "mysql",
+			name:   "mysql",
+			args:   []string{"--host", conn.Host, "--port", "25060", "--user", "doadmin", "--ssl-mode=VERIFY_IDENTITY", "--ssl-ca=/tmp/ca.crt", "defaultdb"},
+			env:    []string{"MYSQL_PWD=foobaz"},
+		},
+		{
+			engine: "redis",
+			name:   "redis-cli",
+			args:   []string{"-h", conn.Host, "-p", "25060", "--tls", "--cacert", "/tmp/ca.crt", "--user", "doadmin"},
+			env:    []string{"REDISCLI_AUTH=foobaz"},
+		},
+		{
+			engine: "mongodb",
+			err:    "connecting is not supported for mongodb database clusters; supported engines are pg, mysql and redis",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine, func(t *testing.T) {
+			name, args, env, err := databaseClientCommand(tt.engine, conn, "/tmp/ca.crt")
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.args, args)
+			assert.Equal(t, tt.env, env)
+		})
+	}
+}
+
+// TestDatabaseConnectHelperProcess stands in for the interactive database
+// clients. It exits as soon as it starts.
+func TestDatabaseConnectHelperProcess(t *testing.T) {
+	if os.Getenv("DOCTL_TEST_DATABASE_CLIENT") != "1" {
+		return
+	}
+	os.Exit(0)
+}
+
+func TestDatabaseConnect(t *testing.T) {
+	testCA := &do.DatabaseCA{DatabaseCA: &godo.DatabaseCA{Certificate: []byte("-----BEGIN CERTIFICATE-----")}}
+	t.Setenv("DOCTL_TEST_DATABASE_CLIENT", "1")
+
+	var (
+		gotName string
+		gotArgs []string
+		gotCmd  *exec.Cmd
+		gotCA   []byte
+	)
+	defer func(orig func(string, ...string) *exec.Cmd) { execCommand = orig }(execCommand)
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		gotName, gotArgs = name, args
+		for i, arg := range args {
+			if arg == "--cacert" {
+				gotCA, _ = os.ReadFile(args[i+1])
+			}
+		}
+		gotCmd = exec.Command(os.Args[0], "-test.run=^TestDatabaseConnectHelperProcess$")
+		return gotCmd
+	}
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&testDBCluster, nil)
+		tm.databases.EXPECT().GetCA(testDBCluster.ID).Return(testCA, nil)
+		config.Args = append(config.Args, testDBCluster.ID)
+
+		err := RunDatabaseConnect(config)
+		require.NoError(t, err)
+		assert.Equal(t, "psql", gotName)
+		assert.Equal(t, []string{"--host", testGODOConnection.Host, "--port", "25060", "--username", "doadmin", "--dbname", "defaultdb"}, gotArgs)
+		assert.Contains(t, gotCmd.Env, "PGPASSWORD=foobaz")
+		assert.NotContains(t, gotArgs, "foobaz")
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		redis := *testDBCluster.Database
+		redis.EngineSlug = "redis"
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&do.Database{Database: &redis}, nil)
+		tm.databases.EXPECT().GetReplica(testDBCluster.ID, testDBReplica.Name).Return(&testDBReplica, nil)
+		tm.databases.EXPECT().GetUser(testDBCluster.ID, "app").Return(&do.DatabaseUser{
+			DatabaseUser: &godo.DatabaseUser{Name: "app", Password: "secret"},
+		}, nil)
+		tm.databases.EXPECT().GetCA(testDBCluster.ID).Return(testCA, nil)
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseUser, "app")
+		config.Doit.Set(config.NS, doctl.ArgDatabaseReplica, testDBReplica.Name)
+
+		err := RunDatabaseConnect(config)
+		require.NoError(t, err)
+		assert.Equal(t, "redis-cli", gotName)
+		assert.Equal(t, []string{"--user", "app"}, gotArgs[len(gotArgs)-2:])
+		assert.Contains(t, gotCmd.Env, "REDISCLI_AUTH=secret")
+		assert.Equal(t, []byte("-----BEGIN CERTIFICATE-----"), gotCA)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&testDBCluster, nil)
+		tm.databases.EXPECT().GetUser(testDBCluster.ID, "app").Return(&do.DatabaseUser{
+			DatabaseUser: &godo.DatabaseUser{Name: "app"},
+		}, nil)
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseUser, "app")
+
+		err := RunDatabaseConnect(config)
+		assert.EqualError(t, err, "the password of database user app is not available; reset it with `doctl databases user reset` to connect as this user")
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&testDBCluster, nil)
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabasePrivate, true)
+
+		err := RunDatabaseConnect(config)
+		assert.EqualError(t, err, "database cluster sunny-db-cluster has no private connection details")
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&testDBCluster, nil)
+		tm.databases.EXPECT().GetCA(testDBCluster.ID).Return(testCA, nil)
+		config.Args = append(config.Args, testDBCluster.ID)
+
+		defer func(orig func(string, ...string) *exec.Cmd) { execCommand = orig }(execCommand)
+		execCommand = func(name string, args ...string) *exec.Cmd {
+			return exec.Command("doctl-test-missing-client")
+		}
+
+		err := RunDatabaseConnect(config)
+		assert.EqualError(t, err, "psql was not found in your PATH; install it to connect to pg database clusters")
+	})
+}
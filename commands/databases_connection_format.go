@@ -0,0 +1,229 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"sigs.k8s.io/yaml"
+)
+
+// databaseConnectionFormats are the values accepted by --format-as.
+var databaseConnectionFormats = []string{"dotenv", "jdbc", "libpq", "go-dsn", "rails", "prisma", "k8s-secret"}
+
+// displayDatabaseConnectionAs writes the connection details of a database
+// cluster, pool or replica in the given driver format. name identifies the
+// target and is used to name the Kubernetes Secret.
+func displayDatabaseConnectionAs(c *CmdConfig, db *do.Database, conn *godo.DatabaseConnection, name, format string) error {
+	var ca []byte
+	if format == "k8s-secret" {
+		cert, err := c.Databases().GetCA(db.ID)
+		if err != nil {
+			return err
+		}
+		ca = cert.Certificate
+	}
+
+	out, err := formatDatabaseConnection(format, db.EngineSlug, conn, name, ca)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(c.Out, out)
+	return nil
+}
+
+// formatDatabaseConnection renders connection details so they can be pasted
+// into the configuration of an application.
+func formatDatabaseConnection(format, engine string, conn *godo.DatabaseConnection, name string, ca []byte) (string, error) {
+	unsupported := fmt.Errorf("the %s format is not supported for %s database clusters", format, engine)
+	hostPort := net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port))
+
+	switch format {
+	case "dotenv":
+		var b strings.Builder
+		for _, kv := range databaseConnectionEnv(conn) {
+			fmt.Fprintf(&b, "%s=%s\n", kv[0], dotenvQuote(kv[1]))
+		}
+		return b.String(), nil
+
+	case "jdbc":
+		q := url.Values{}
+		q.Set("user", conn.User)
+		q.Set("password", conn.Password)
+		switch engine {
+		case "pg":
+			q.Set("sslmode", "require")
+			return fmt.Sprintf("jdbc:postgresql://%s/%s?%s\n", hostPort, url.PathEscape(conn.Database), q.Encode()), nil
+		case "mysql":
+			q.Set("sslMode", "REQUIRED")
+			return fmt.Sprintf("jdbc:mysql://%s/%s?%s\n", hostPort, url.PathEscape(conn.Database), q.Encode()), nil
+		}
+		return "", unsupported
+
+	case "libpq":
+		if engine != "pg" {
+			return "", unsupported
+		}
+		return libpqConnString(conn) + "\n", nil
+
+	case "go-dsn":
+		switch engine {
+		case "pg":
+			return libpqConnString(conn) + "\n", nil
+		case "mysql":
+			// the cluster's certificate is signed by a private CA, which the
+			// driver can only verify against a registered TLS config
+			return fmt.Sprintf("%s:%s@tcp(%s)/%s?tls=skip-verify\n", conn.User, conn.Password, hostPort, conn.Database), nil
+		}
+		return conn.URI + "\n", nil
+
+	case "rails":
+		adapter, ssl := "", ""
+		switch engine {
+		case "pg":
+			adapter, ssl = "postgresql", "sslmode: require"
+		case "mysql":
+			adapter, ssl = "mysql2", "ssl_mode: required"
+		default:
+			return "", unsupported
+		}
+		var b strings.Builder
+		fmt.Fprintln(&b, "production:")
+		fmt.Fprintf(&b, "  adapter: %s\n", adapter)
+		fmt.Fprintf(&b, "  host: %s\n", strconv.Quote(conn.Host))
+		fmt.Fprintf(&b, "  port: %d\n", conn.Port)
+		fmt.Fprintf(&b, "  database: %s\n", strconv.Quote(conn.Database))
+		fmt.Fprintf(&b, "  username: %s\n", strconv.Quote(conn.User))
+		fmt.Fprintf(&b, "  password: %s\n", strconv.Quote(conn.Password))
+		fmt.Fprintf(&b, "  %s\n", ssl)
+		return b.String(), nil
+
+	case "prisma":
+		u := &url.URL{
+			User: url.UserPassword(conn.User, conn.Password),
+			Host: hostPort,
+			Path: "/" + conn.Database,
+		}
+		switch engine {
+		case "pg":
+			u.Scheme, u.RawQuery = "postgresql", "sslmode=require"
+		case "mysql":
+			u.Scheme, u.RawQuery = "mysql", "sslaccept=accept_invalid_certs"
+		case "mongodb":
+			return fmt.Sprintf("DATABASE_URL=%s\n", dotenvQuote(conn.URI)), nil
+		default:
+			return "", unsupported
+		}
+		return fmt.Sprintf("DATABASE_URL=%s\n", dotenvQuote(u.String())), nil
+
+	case "k8s-secret":
+		data := make(map[string]string)
+		for _, kv := range databaseConnectionEnv(conn) {
+			data[kv[0]] = kv[1]
+		}
+		if len(ca) > 0 {
+			data["ca.crt"] = string(ca)
+		}
+		secret := map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]string{"name": kubernetesSecretName(name + "-connection")},
+			"type":       "Opaque",
+			"stringData": data,
+		}
+		out, err := yaml.Marshal(secret)
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+
+	return "", fmt.Errorf("invalid connection format %q, must be one of: %s", format, strings.Join(databaseConnectionFormats, ", "))
+}
+
+// databaseConnectionEnv returns the environment variables describing a
+// connection, in the order they should be written.
+func databaseConnectionEnv(conn *godo.DatabaseConnection) [][2]string {
+	env := [][2]string{
+		{"DATABASE_URL", conn.URI},
+		{"DATABASE_HOST", conn.Host},
+		{"DATABASE_PORT", strconv.Itoa(conn.Port)},
+		{"DATABASE_USER", conn.User},
+		{"DATABASE_PASSWORD", conn.Password},
+	}
+	if conn.Database != "" {
+		env = append(env, [2]string{"DATABASE_NAME", conn.Database})
+	}
+	return env
+}
+
+// dotenvQuote quotes a value for a .env file. Single-quoted values are taken
+// literally, so `#`, `$` and spaces are safe in them; values containing a
+// single quote are double-quoted with the special characters escaped.
+func dotenvQuote(v string) string {
+	if !strings.Contains(v, "'") {
+		return "'" + v + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(v) + `"`
+}
+
+// libpqConnString returns a libpq keyword/value connection string, quoting
+// values as libpq requires.
+func libpqConnString(conn *godo.DatabaseConnection) string {
+	quote := func(v string) string {
+		if v != "" && !strings.ContainsAny(v, ` '\`) {
+			return v
+		}
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+	}
+	return fmt.Sprintf("host=%s port=%d dbname=%s user=%s password=%s sslmode=require",
+		quote(conn.Host), conn.Port, quote(conn.Database), quote(conn.User), quote(conn.Password))
+}
+
+var invalidSecretNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// kubernetesSecretName turns a name into a valid Kubernetes object name.
+func kubernetesSecretName(name string) string {
+	return strings.Trim(invalidSecretNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+}
+
+// databaseConnectionFormat returns the value of --format-as, checking that it
+// is a supported format.
+func databaseConnectionFormat(c *CmdConfig) (string, error) {
+	format, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseConnectionFormat)
+	if err != nil || format == "" {
+		return "", err
+	}
+	for _, f := range databaseConnectionFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("invalid connection format %q, must be one of: %s", format, strings.Join(databaseConnectionFormats, ", "))
+}
+
+// databaseConnectionFormatFlag adds the --format-as flag to a connection
+// command.
+func databaseConnectionFormatFlag(cmd *Command) {
+	AddStringFlag(cmd, doctl.ArgDatabaseConnectionFormat, "", "",
+		"Print the connection details in a format ready to paste into application configuration. Possible values: "+strings.Join(databaseConnectionFormats, ", ")+". The connection is encrypted, but only the k8s-secret format includes the CA certificate needed to verify the server.")
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/godo"
+	"github.com/dustin/go-humanize"
+)
+
+// pgArchiveMagic starts the archives written by pg_dump --format=custom.
+var pgArchiveMagic = []byte("PGDMP")
+
+// RunDatabaseDump writes a logical dump of a database to a file or stdout
+// using the dump client of its engine.
+func RunDatabaseDump(c *CmdConfig) error {
+	outputFile, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseDumpOutputFile)
+	if err != nil {
+		return err
+	}
+	tables, err := c.Doit.GetStringSlice(c.NS, doctl.ArgDatabaseDumpTable)
+	if err != nil {
+		return err
+	}
+	schemas, err := c.Doit.GetStringSlice(c.NS, doctl.ArgDatabaseDumpSchema)
+	if err != nil {
+		return err
+	}
+
+	db, conn, caPath, cleanup, err := databaseClientConnection(c)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	archive := strings.HasSuffix(outputFile, ".dump")
+	name, args, err := databaseDumpCommand(db.EngineSlug, conn, caPath, archive, tables, schemas)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = c.Out
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	var gz *gzip.Writer
+	if strings.HasSuffix(outputFile, ".gz") {
+		gz = gzip.NewWriter(out)
+		out = gz
+	}
+
+	progress := newTransferProgress("Dumped", os.Stderr)
+	cmd := execCommand(name, args...)
+	cmd.Env = append(os.Environ(), databaseClientEnv(db.EngineSlug, conn, caPath)...)
+	cmd.Stdout = io.MultiWriter(out, progress)
+	cmd.Stderr = os.Stderr
+
+	notice("Dumping database %s of %s with %s", conn.Database, db.Name, name)
+	err = runDatabaseClient(cmd, name, db.EngineSlug)
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	progress.Done()
+	if err != nil {
+		if outputFile != "" {
+			os.Remove(outputFile)
+		}
+		return err
+	}
+
+	if outputFile != "" {
+		notice("Dumped database %s of %s to %s", conn.Database, db.Name, outputFile)
+	}
+	return nil
+}
+
+// RunDatabaseLoad loads a logical dump from a file or stdin into a database
+// using the client of its engine.
+func RunDatabaseLoad(c *CmdConfig) error {
+	inputFile, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseLoadInputFile)
+	if err != nil {
+		return err
+	}
+	tables, err := c.Doit.GetStringSlice(c.NS, doctl.ArgDatabaseDumpTable)
+	if err != nil {
+		return err
+	}
+	schemas, err := c.Doit.GetStringSlice(c.NS, doctl.ArgDatabaseDumpSchema)
+	if err != nil {
+		return err
+	}
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if inputFile == "" && !force {
+		// The confirmation prompt would read from the dump.
+		return fmt.Errorf("--%s is required to load a dump from stdin", doctl.ArgForce)
+	}
+	if inputFile != "" {
+		f, err := os.Open(inputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	if strings.HasSuffix(inputFile, ".gz") {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", inputFile, err)
+		}
+		defer gz.Close()
+		in = gz
+	}
+	buffered := bufio.NewReader(in)
+	magic, _ := buffered.Peek(len(pgArchiveMagic))
+	archive := bytes.Equal(magic, pgArchiveMagic)
+
+	db, conn, caPath, cleanup, err := databaseClientConnection(c)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	name, args, err := databaseLoadCommand(db.EngineSlug, conn, caPath, archive, tables, schemas)
+	if err != nil {
+		return err
+	}
+
+	if !force && AskForConfirm(fmt.Sprintf("load the dump into database %s of %s? Existing objects with the same names may be overwritten.", conn.Database, db.Name)) != nil {
+		return errOperationAborted
+	}
+
+	progress := newTransferProgress("Loaded", os.Stderr)
+	cmd := execCommand(name, args...)
+	cmd.Env = append(os.Environ(), databaseClientEnv(db.EngineSlug, conn, caPath)...)
+	cmd.Stdin = io.TeeReader(buffered, progress)
+	cmd.Stdout = c.Out
+	cmd.Stderr = os.Stderr
+
+	notice("Loading into database %s of %s with %s", conn.Database, db.Name, name)
+	err = runDatabaseClient(cmd, name, db.EngineSlug)
+	progress.Done()
+	if err != nil {
+		return err
+	}
+
+	notice("Loaded database %s of %s", conn.Database, db.Name)
+	return nil
+}
+
+// databaseDumpCommand returns the client that dumps a database, and its
+// arguments. PostgreSQL databases are dumped as a pg_restore archive when
+// archive is set, and as SQL otherwise.
+func databaseDumpCommand(engine string, conn *godo.DatabaseConnection, caPath string, archive bool, tables, schemas []string) (string, []string, error) {
+	switch engine {
+	case "pg":
+		args := append(pgClientArgs(conn), "--no-owner", "--no-acl")
+		if archive {
+			args = append(args, "--format=custom")
+		}
+		args = append(args, pgFilterArgs(tables, schemas)...)
+		return "pg_dump", args, nil
+	case "mysql":
+		if len(schemas) > 0 {
+			return "", nil, fmt.Errorf("--%s is not supported for MySQL database clusters; use --%s to choose the database to dump", doctl.ArgDatabaseDumpSchema, doctl.ArgDatabaseName)
+		}
+		if archive {
+			return "", nil, fmt.Errorf(".dump archives are only supported for PostgreSQL database clusters; use a .sql or .sql.gz file")
+		}
+		args := append(mysqlClientArgs(conn, caPath), "--single-transaction", "--set-gtid-purged=OFF", "--routines", "--triggers", conn.Database)
+		args = append(args, tables...)
+		return "mysqldump", args, nil
+	default:
+		return "", nil, fmt.Errorf("dumping is not supported for %s database clusters; supported engines are pg and mysql", engine)
+	}
+}
+
+// databaseLoadCommand returns the client that loads a dump into a database,
+// and its arguments. Only pg_restore archives can be filtered by table or
+// schema, as SQL dumps are run as they are.
+func databaseLoadCommand(engine string, conn *godo.DatabaseConnection, caPath string, archive bool, tables, schemas []string) (string, []string, error) {
+	filtered := len(tables) > 0 || len(schemas) > 0
+
+	switch engine {
+	case "pg":
+		if archive {
+			args := append(pgClientArgs(conn), "--no-owner", "--no-acl")
+			args = append(args, pgFilterArgs(tables, schemas)...)
+			return "pg_restore", args, nil
+		}
+		if filtered {
+			return "", nil, fmt.Errorf("--%s and --%s can only be used to load pg_restore archives; dump with an output file ending in .dump to create one", doctl.ArgDatabaseDumpTable, doctl.ArgDatabaseDumpSchema)
+		}
+		return "psql", append(pgClientArgs(conn), "--quiet", "--set", "ON_ERROR_STOP=1"), nil
+	case "mysql":
+		if filtered {
+			return "", nil, fmt.Errorf("--%s and --%s are not supported when loading into MySQL database clusters", doctl.ArgDatabaseDumpTable, doctl.ArgDatabaseDumpSchema)
+		}
+		return "mysql", append(mysqlClientArgs(conn, caPath), conn.Database), nil
+	default:
+		return "", nil, fmt.Errorf("loading is not supported for %s database clusters; supported engines are pg and mysql", engine)
+	}
+}
+
+func pgFilterArgs(tables, schemas []string) []string {
+	var args []string
+	for _, s := range schemas {
+		args = append(args, "--schema="+s)
+	}
+	for _, t := range tables {
+		args = append(args, "--table="+t)
+	}
+	return args
+}
+
+// transferProgress counts the bytes written to it and reports them at most
+// once a second.
+type transferProgress struct {
+	verb string
+	out  io.Writer
+	n    uint64
+	last time.Time
+}
+
+func newTransferProgress(verb string, out io.Writer) *transferProgress {
+	return &transferProgress{verb: verb, out: out, last: time.Now()}
+}
+
+func (p *transferProgress) Write(b []byte) (int, error) {
+	p.n += uint64(len(b))
+	if time.Since(p.last) >= time.Second {
+		p.report()
+	}
+	return len(b), nil
+}
+
+func (p *transferProgress) report() {
+	fmt.Fprintf(p.out, "\r%s %s", p.verb, humanize.Bytes(p.n))
+	p.last = time.Now()
+}
+
+// Done reports the final count.
+func (p *transferProgress) Done() {
+	p.report()
+	fmt.Fprintln(p.out)
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseDumpCommand(t *testing.T) {
+	conn := &godo.DatabaseConnection{Host: "db.example.com", Port: 25060, User: "doadmin", Database: "app"}
+
+	name, args, err := databaseDumpCommand("pg", conn, "/tmp/ca.crt", true, []string{"users"}, []string{"public"})
+	require.NoError(t, err)
+	assert.Equal(t, "pg_dump", name)
+	assert.Equal(t, []string{
+		"--host", "db.example.com", "--port", "25060", "--username", "doadmin", "--dbname", "app",
+		"--no-owner", "--no-acl", "--format=custom", "--schema=public", "--table=users",
+	}, args)
+
+	name, args, err = databaseDumpCommand("mysql", conn, "/tmp/ca.crt", false, []string{"users", "orders"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mysqldump", name)
+	assert.Equal(t, []string{
+		"--host", "db.example.com", "--port", "25060", "--user", "doadmin", "--ssl-mode=VERIFY_IDENTITY", "--ssl-ca=/tmp/ca.crt",
+		"--single-transaction", "--set-gtid-purged=OFF", "--routines", "--triggers", "app", "users", "orders",
+	}, args)
+
+	_, _, err = databaseDumpCommand("mysql", conn, "/tmp/ca.crt", false, nil, []string{"public"})
+	assert.EqualError(t, err, "--schema is not supported for MySQL database clusters; use --database to choose the database to dump")
+
+	_, _, err = databaseDumpCommand("redis", conn, "/tmp/ca.crt", false, nil, nil)
+	assert.EqualError(t, err, "dumping is not supported for redis database clusters; supported engines are pg and mysql")
+}
+
+func TestDatabaseLoadCommand(t *testing.T) {
+	conn := &godo.DatabaseConnection{Host: "db.example.com", Port: 25060, User: "doadmin", Database: "app"}
+
+	name, args, err := databaseLoadCommand("pg", conn, "/tmp/ca.crt", true, []string{"users"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "pg_restore", name)
+	assert.Equal(t, []string{
+		"--host", "db.example.com", "--port", "25060", "--username", "doadmin", "--dbname", "app",
+		"--no-owner", "--no-acl", "--table=users",
+	}, args)
+
+	name, args, err = databaseLoadCommand("pg", conn, "/tmp/ca.crt", false, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "psql", name)
+	assert.Equal(t, []string{"--quiet", "--set", "ON_ERROR_STOP=1"}, args[len(args)-3:])
+
+	_, _, err = databaseLoadCommand("pg", conn, "/tmp/ca.crt", false, []string{"users"}, nil)
+	assert.EqualError(t, err, "--table and --schema can only be used to load pg_restore archives; dump with an output file ending in .dump to create one")
+
+	name, args, err = databaseLoadCommand("mysql", conn, "/tmp/ca.crt", false, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mysql", name)
+	assert.Equal(t, "app", args[len(args)-1])
+}
+
+// TestDatabaseClientHelperProcess stands in for the database clients. It
+// writes a header and then copies its input to its output.
+func TestDatabaseClientHelperProcess(t *testing.T) {
+	if os.Getenv("DOCTL_TEST_DATABASE_CLIENT") != "1" {
+		return
+	}
+	os.Stdout.WriteString("-- dumped by " + os.Getenv("PGSSLMODE") + "\n")
+	io.Copy(os.Stdout, os.Stdin)
+	os.Exit(0)
+}
+
+func TestDatabaseDumpAndLoad(t *testing.T) {
+	testCA := &do.DatabaseCA{DatabaseCA: &godo.DatabaseCA{Certificate: []byte("-----BEGIN CERTIFICATE-----")}}
+	t.Setenv("DOCTL_TEST_DATABASE_CLIENT", "1")
+
+	var gotName string
+	var gotArgs []string
+	defer func(orig func(string, ...string) *exec.Cmd) { execCommand = orig }(execCommand)
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		gotName, gotArgs = name, args
+		return exec.Command(os.Args[0], "-test.run=^TestDatabaseClientHelperProcess$")
+	}
+
+	dumpFile := filepath.Join(t.TempDir(), "app.sql.gz")
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&testDBCluster, nil)
+		tm.databases.EXPECT().GetCA(testDBCluster.ID).Return(testCA, nil)
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseName, "app")
+		config.Doit.Set(config.NS, doctl.ArgDatabaseDumpOutputFile, dumpFile)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseDumpTable, []string{"users"})
+
+		err := RunDatabaseDump(config)
+		require.NoError(t, err)
+		assert.Equal(t, "pg_dump", gotName)
+		assert.Contains(t, gotArgs, "--table=users")
+
+		f, err := os.Open(dumpFile)
+		require.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		dump, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, "-- dumped by verify-full\n", string(dump))
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&testDBCluster, nil)
+		tm.databases.EXPECT().GetCA(testDBCluster.ID).Return(testCA, nil)
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseName, "app")
+		config.Doit.Set(config.NS, doctl.ArgDatabaseLoadInputFile, dumpFile)
+		config.Doit.Set(config.NS, doctl.ArgForce, true)
+		var buf bytes.Buffer
+		config.Out = &buf
+
+		err := RunDatabaseLoad(config)
+		require.NoError(t, err)
+		assert.Equal(t, "psql", gotName)
+		assert.Equal(t, "-- dumped by verify-full\n-- dumped by verify-full\n", buf.String())
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Args = append(config.Args, testDBCluster.ID)
+
+		err := RunDatabaseLoad(config)
+		assert.EqualError(t, err, "--force is required to load a dump from stdin")
+	})
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+)
+
+// RunDatabaseRestore creates a database cluster from the backups of an
+// existing cluster, as it was at a point in time.
+func RunDatabaseRestore(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+	sourceID, err := databaseIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
+	name, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseRestoreName)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+	to, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseRestoreTo)
+	if err != nil {
+		return err
+	}
+	toBackup, err := c.Doit.GetString(c.NS, doctl.ArgDatabaseRestoreToBackup)
+	if err != nil {
+		return err
+	}
+	if (to == "") == (toBackup == "") {
+		return fmt.Errorf("specify either --%s or --%s", doctl.ArgDatabaseRestoreTo, doctl.ArgDatabaseRestoreToBackup)
+	}
+	copyTags, err := c.Doit.GetBool(c.NS, doctl.ArgDatabaseCopyTags)
+	if err != nil {
+		return err
+	}
+	copyFirewallRules, err := c.Doit.GetBool(c.NS, doctl.ArgDatabaseCopyFirewallRules)
+	if err != nil {
+		return err
+	}
+	wait, err := c.Doit.GetBool(c.NS, doctl.ArgCommandWait)
+	if err != nil {
+		return err
+	}
+
+	dbs := c.Databases()
+	source, err := dbs.Get(sourceID)
+	if err != nil {
+		return err
+	}
+	backups, err := dbs.ListBackups(sourceID)
+	if err != nil {
+		return err
+	}
+	restoreAt, err := databaseRestorePoint(source.Name, backups, to, toBackup, time.Now())
+	if err != nil {
+		return err
+	}
+
+	r := buildDatabaseRestoreRequest(name, source, restoreAt.UTC().Format(time.RFC3339))
+	if !copyTags {
+		r.Tags = nil
+	}
+
+	notice("Restoring database cluster %s as it was at %s to %s", source.Name, restoreAt.UTC().Format(time.RFC3339), name)
+	db, err := dbs.Create(r)
+	if err != nil {
+		return err
+	}
+
+	if wait || copyFirewallRules {
+		connection := db.Connection
+		notice("Database restore is in progress, waiting for database to be online")
+
+		_, err := waitForDatabaseReady(dbs, db.ID, false)
+		if err != nil {
+			return fmt.Errorf(
+				"database couldn't enter the `online` state: %v",
+				err,
+			)
+		}
+
+		db, err = dbs.Get(db.ID)
+		if err != nil {
+			return err
+		}
+		db.Connection = connection
+	}
+
+	if copyFirewallRules {
+		if err := copyDatabaseFirewallRules(dbs, sourceID, db.ID); err != nil {
+			return fmt.Errorf("database %s was restored, but its firewall rules could not be copied: %v", name, err)
+		}
+	}
+
+	notice("Database restored")
+
+	return displayDatabases(c, false, *db)
+}
+
+// copyDatabaseFirewallRules replaces the firewall rules of a database cluster
+// with those of another.
+func copyDatabaseFirewallRules(dbs do.DatabasesService, fromID, toID string) error {
+	rules, err := dbs.GetFirewallRules(fromID)
+	if err != nil {
+		return err
+	}
+
+	copied := make([]*godo.DatabaseFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		copied = append(copied, &godo.DatabaseFirewallRule{
+			ClusterUUID: toID,
+			Type:        rule.Type,
+			Value:       rule.Value,
+		})
+	}
+
+	return dbs.UpdateFirewallRules(toID, &godo.DatabaseUpdateFirewallRulesRequest{
+		Rules: copied,
+	})
+}
+
+// databaseRestorePoint returns the point in time a database cluster should be
+// restored to, checking it against the restore window of its backups.
+func databaseRestorePoint(name string, backups do.DatabaseBackups, to, toBackup string, now time.Time) (time.Time, error) {
+	if len(backups) == 0 {
+		return time.Time{}, fmt.Errorf("database cluster %s has no backups to restore from", name)
+	}
+	oldest, newest := backups[0].CreatedAt, backups[0].CreatedAt
+	for _, b := range backups[1:] {
+		if b.CreatedAt.Before(oldest) {
+			oldest = b.CreatedAt
+		}
+		if b.CreatedAt.After(newest) {
+			newest = b.CreatedAt
+		}
+	}
+
+	if toBackup != "" {
+		if toBackup == "latest" {
+			return newest, nil
+		}
+		at, err := parseRestoreTime(toBackup, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, b := range backups {
+			if b.CreatedAt.Equal(at) {
+				return b.CreatedAt, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("database cluster %s has no backup created at %s; list its backups with `doctl databases backups %s`", name, at.UTC().Format(time.RFC3339), name)
+	}
+
+	at, err := parseRestoreTime(to, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if at.Before(oldest) || at.After(now) {
+		return time.Time{}, fmt.Errorf("cannot restore database cluster %s to %s; it can be restored to any time from %s to now",
+			name, at.UTC().Format(time.RFC3339), oldest.UTC().Format(time.RFC3339))
+	}
+	return at, nil
+}
+
+var relativeRestoreTime = regexp.MustCompile(`^(\d+)\s*([a-z]+)\s+ago$`)
+
+// restoreTimeUnits are the units accepted in relative restore times such as
+// "2 hours ago".
+var restoreTimeUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// parseRestoreTime parses a point in time given either as a timestamp or
+// relative to now, e.g. "2 hours ago" or "1h30m ago".
+func parseRestoreTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+
+	if m := relativeRestoreTime.FindStringSubmatch(lower); m != nil {
+		if unit, ok := restoreTimeUnits[m[2]]; ok {
+			n, err := strconv.Atoi(m[1])
+			if err == nil {
+				return now.Add(-time.Duration(n) * unit), nil
+			}
+		}
+	}
+	if strings.HasSuffix(lower, " ago") {
+		if d, err := time.ParseDuration(strings.TrimSpace(strings.TrimSuffix(lower, " ago"))); err == nil {
+			return now.Add(-d), nil
+		}
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05 -0700 MST", "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q; use a timestamp such as 2023-02-01T17:32:15Z or a relative time such as \"2 hours ago\"", s)
+}
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseRestoreTime(t *testing.T) {
+	now := time.Date(2023, 2, 1, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+		err  string
+	}{
+		{in: "2 hours ago", want: now.Add(-2 * time.Hour)},
+		{in: "1 day ago", want: now.Add(-24 * time.Hour)},
+		{in: "45m ago", want: now.Add(-45 * time.Minute)},
+		{in: "1h30m ago", want: now.Add(-90 * time.Minute)},
+		{in: "2023-02-01T17:32:15Z", want: time.Date(2023, 2, 1, 17, 32, 15, 0, time.UTC)},
+		{in: "2023-02-01 17:32:15 +0000 UTC", want: time.Date(2023, 2, 1, 17, 32, 15, 0, time.UTC)},
+		{in: "yesterday", err: `invalid time "yesterday"; use a timestamp such as 2023-02-01T17:32:15Z or a relative time such as "2 hours ago"`},
+		{in: "2 fortnights ago", err: `invalid time "2 fortnights ago"; use a timestamp such as 2023-02-01T17:32:15Z or a relative time such as "2 hours ago"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseRestoreTime(tt.in, now)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestDatabaseRestorePoint(t *testing.T) {
+	now := time.Date(2023, 2, 3, 12, 0, 0, 0, time.UTC)
+	backup := func(at time.Time) do.DatabaseBackup {
+		return do.DatabaseBackup{DatabaseBackup: &godo.DatabaseBackup{CreatedAt: at}}
+	}
+	oldest := time.Date(2023, 2, 1, 17, 32, 15, 0, time.UTC)
+	newest := time.Date(2023, 2, 2, 17, 32, 15, 0, time.UTC)
+	backups := do.DatabaseBackups{backup(newest), backup(oldest)}
+
+	at, err := databaseRestorePoint("sunny-db-cluster", backups, "", "latest", now)
+	require.NoError(t, err)
+	assert.Equal(t, newest, at)
+
+	at, err = databaseRestorePoint("sunny-db-cluster", backups, "", "2023-02-01 17:32:15 +0000 UTC", now)
+	require.NoError(t, err)
+	assert.Equal(t, oldest, at)
+
+	_, err = databaseRestorePoint("sunny-db-cluster", backups, "", "2023-02-01T18:00:00Z", now)
+	assert.EqualError(t, err, "database cluster sunny-db-cluster has no backup created at 2023-02-01T18:00:00Z; list its backups with `doctl databases backups sunny-db-cluster`")
+
+	at, err = databaseRestorePoint("sunny-db-cluster", backups, "2 hours ago", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-2*time.Hour), at)
+
+	_, err = databaseRestorePoint("sunny-db-cluster", backups, "3 days ago", "", now)
+	assert.EqualError(t, err, "cannot restore database cluster sunny-db-cluster to 2023-01-31T12:00:00Z; it can be restored to any time from 2023-02-01T17:32:15Z to now")
+
+	_, err = databaseRestorePoint("sunny-db-cluster", nil, "", "latest", now)
+	assert.EqualError(t, err, "database cluster sunny-db-cluster has no backups to restore from")
+}
+
+func TestDatabaseRestore(t *testing.T) {
+	latest := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	backups := do.DatabaseBackups{{DatabaseBackup: &godo.DatabaseBackup{CreatedAt: latest}}}
+
+	source := *testDBCluster.Database
+	source.Tags = []string{"prod"}
+	restored := *testDBCluster.Database
+	restored.ID = "0bd2a0e4-5a93-4ab5-8a5c-7a0e0c3a6e4f"
+	restored.Name = "sunny-db-restored"
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&do.Database{Database: &source}, nil)
+		tm.databases.EXPECT().ListBackups(testDBCluster.ID).Return(backups, nil)
+		tm.databases.EXPECT().Create(&godo.DatabaseCreateRequest{
+			Name: "sunny-db-restored",
+			BackupRestore: &godo.DatabaseBackupRestore{
+				DatabaseName:    source.Name,
+				BackupCreatedAt: latest.Format(time.RFC3339),
+			},
+			EngineSlug:         source.EngineSlug,
+			NumNodes:           source.NumNodes,
+			SizeSlug:           source.SizeSlug,
+			Region:             source.RegionSlug,
+			Version:            source.VersionSlug,
+			PrivateNetworkUUID: source.PrivateNetworkUUID,
+			Tags:               []string{"prod"},
+		}).Return(&do.Database{Database: &restored}, nil)
+		tm.databases.EXPECT().Get(restored.ID).Return(&do.Database{Database: &restored}, nil).Times(2)
+		tm.databases.EXPECT().GetFirewallRules(testDBCluster.ID).Return(do.DatabaseFirewallRules{
+			{DatabaseFirewallRule: &godo.DatabaseFirewallRule{UUID: "rule-1", ClusterUUID: testDBCluster.ID, Type: "tag", Value: "web"}},
+		}, nil)
+		tm.databases.EXPECT().UpdateFirewallRules(restored.ID, &godo.DatabaseUpdateFirewallRulesRequest{
+			Rules: []*godo.DatabaseFirewallRule{{ClusterUUID: restored.ID, Type: "tag", Value: "web"}},
+		}).Return(nil)
+
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseRestoreName, "sunny-db-restored")
+		config.Doit.Set(config.NS, doctl.ArgDatabaseRestoreToBackup, "latest")
+		config.Doit.Set(config.NS, doctl.ArgDatabaseCopyTags, true)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseCopyFirewallRules, true)
+
+		err := RunDatabaseRestore(config)
+		assert.NoError(t, err)
+	})
+
+	// a failure to get the restored cluster once it is online is returned
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.databases.EXPECT().Get(testDBCluster.ID).Return(&do.Database{Database: &source}, nil)
+		tm.databases.EXPECT().ListBackups(testDBCluster.ID).Return(backups, nil)
+		tm.databases.EXPECT().Create(gomock.Any()).Return(&do.Database{Database: &restored}, nil)
+		gomock.InOrder(
+			tm.databases.EXPECT().Get(restored.ID).Return(&do.Database{Database: &restored}, nil),
+			tm.databases.EXPECT().Get(restored.ID).Return(nil, errTest),
+		)
+
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseRestoreName, "sunny-db-restored")
+		config.Doit.Set(config.NS, doctl.ArgDatabaseRestoreToBackup, "latest")
+		config.Doit.Set(config.NS, doctl.ArgCommandWait, true)
+
+		err := RunDatabaseRestore(config)
+		assert.Equal(t, errTest, err)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Args = append(config.Args, testDBCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgDatabaseRestoreName, "sunny-db-restored")
+
+		err := RunDatabaseRestore(config)
+		assert.EqualError(t, err, "specify either --to or --to-backup")
+	})
+}
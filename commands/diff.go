@@ -0,0 +1,313 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/fatih/color"
+	"sigs.k8s.io/yaml"
+)
+
+// elementKeyFn identifies an element of a JSON array of objects so that
+// arrays can be diffed and merged element by element instead of as a whole.
+type elementKeyFn func(map[string]interface{}) string
+
+// fieldChange is a single field that differs between two versions of a resource.
+type fieldChange struct {
+	Path string
+	From interface{}
+	To   interface{}
+}
+
+func (fc fieldChange) added() bool   { return fc.From == nil }
+func (fc fieldChange) removed() bool { return fc.To == nil }
+
+// diffFields compares two values field by field using their JSON
+// representation. Arrays whose path is listed in keyed are compared element by
+// element; all other arrays are compared as a whole.
+func diffFields(current, desired interface{}, keyed map[string]elementKeyFn) ([]fieldChange, error) {
+	currentFields := map[string]interface{}{}
+	if err := flattenJSONFields(current, keyed, currentFields); err != nil {
+		return nil, err
+	}
+	desiredFields := map[string]interface{}{}
+	if err := flattenJSONFields(desired, keyed, desiredFields); err != nil {
+		return nil, err
+	}
+
+	paths := map[string]bool{}
+	for p := range currentFields {
+		paths[p] = true
+	}
+	for p := range desiredFields {
+		paths[p] = true
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var changes []fieldChange
+	for _, p := range sorted {
+		from, to := currentFields[p], desiredFields[p]
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, fieldChange{Path: p, From: from, To: to})
+		}
+	}
+
+	return changes, nil
+}
+
+func flattenJSONFields(v interface{}, keyed map[string]elementKeyFn, out map[string]interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return err
+	}
+
+	flattenJSONValue("", "", generic, keyed, out)
+	return nil
+}
+
+func flattenJSONValue(path, schemaPath string, v interface{}, keyed map[string]elementKeyFn, out map[string]interface{}) {
+	switch t := v.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		if len(t) == 0 {
+			return
+		}
+		for k, child := range t {
+			flattenJSONValue(joinFieldPath(path, k), joinFieldPath(schemaPath, k), child, keyed, out)
+		}
+	case []interface{}:
+		keyFn, ok := keyed[schemaPath]
+		if !ok || len(t) == 0 {
+			if len(t) > 0 {
+				out[path] = t
+			}
+			return
+		}
+		for _, elem := range t {
+			m, isMap := elem.(map[string]interface{})
+			if !isMap {
+				out[path] = t
+				return
+			}
+			flattenJSONValue(fmt.Sprintf("%s[%s]", path, keyFn(m)), schemaPath, m, keyed, out)
+		}
+	default:
+		out[path] = t
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// writeFieldChanges writes changes as colored lines prefixed with +, - or ~.
+func writeFieldChanges(w io.Writer, changes []fieldChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+
+	for _, c := range changes {
+		switch {
+		case c.added():
+			color.New(color.FgGreen).Fprintf(w, "+ %s: %s\n", c.Path, formatFieldValue(c.To))
+		case c.removed():
+			color.New(color.FgRed).Fprintf(w, "- %s: %s\n", c.Path, formatFieldValue(c.From))
+		default:
+			color.New(color.FgYellow).Fprintf(w, "~ %s: %s -> %s\n", c.Path, formatFieldValue(c.From), formatFieldValue(c.To))
+		}
+	}
+}
+
+func formatFieldValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// mergeJSONFields applies a partial JSON document to a base document. Objects
+// are merged recursively, null values remove a field, and arrays whose path is
+// listed in keyed have their elements inserted or merged by key. All other
+// values in patch replace the ones in base.
+func mergeJSONFields(base, patch map[string]interface{}, keyed map[string]elementKeyFn) map[string]interface{} {
+	return mergeJSONObject("", base, patch, keyed)
+}
+
+func mergeJSONObject(schemaPath string, base, patch map[string]interface{}, keyed map[string]elementKeyFn) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+
+	for k, pv := range patch {
+		path := joinFieldPath(schemaPath, k)
+		if pv == nil {
+			delete(out, k)
+			continue
+		}
+
+		switch p := pv.(type) {
+		case map[string]interface{}:
+			if b, ok := out[k].(map[string]interface{}); ok {
+				out[k] = mergeJSONObject(path, b, p, keyed)
+				continue
+			}
+		case []interface{}:
+			if keyFn, ok := keyed[path]; ok {
+				if b, ok := out[k].([]interface{}); ok {
+					out[k] = mergeJSONArray(path, b, p, keyFn, keyed)
+					continue
+				}
+			}
+		}
+		out[k] = pv
+	}
+
+	return out
+}
+
+func mergeJSONArray(schemaPath string, base, patch []interface{}, keyFn elementKeyFn, keyed map[string]elementKeyFn) []interface{} {
+	out := append([]interface{}(nil), base...)
+
+	index := map[string]int{}
+	for i, elem := range out {
+		if m, ok := elem.(map[string]interface{}); ok {
+			index[keyFn(m)] = i
+		}
+	}
+
+	for _, elem := range patch {
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			out = append(out, elem)
+			continue
+		}
+		if i, exists := index[keyFn(m)]; exists {
+			out[i] = mergeJSONObject(schemaPath, out[i].(map[string]interface{}), m, keyed)
+			continue
+		}
+		index[keyFn(m)] = len(out)
+		out = append(out, m)
+	}
+
+	return out
+}
+
+// toJSONObject converts a value to its generic JSON object representation.
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// fromJSONObject converts a generic JSON object into v.
+func fromJSONObject(m map[string]interface{}, v interface{}) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// stringsDiff is the result of comparing two sets of strings.
+type stringsDiff struct {
+	removed   []string
+	added     []string
+	unchanged []string
+}
+
+func (d stringsDiff) changed() bool {
+	return len(d.removed) > 0 || len(d.added) > 0
+}
+
+// diffStrings compares two sets of strings, ignoring order and duplicates.
+func diffStrings(current, desired []string) stringsDiff {
+	var d stringsDiff
+
+	inCurrent := map[string]bool{}
+	for _, s := range current {
+		inCurrent[s] = true
+	}
+	inDesired := map[string]bool{}
+	for _, s := range desired {
+		inDesired[s] = true
+	}
+
+	for s := range inCurrent {
+		if inDesired[s] {
+			d.unchanged = append(d.unchanged, s)
+		} else {
+			d.removed = append(d.removed, s)
+		}
+	}
+	for s := range inDesired {
+		if !inCurrent[s] {
+			d.added = append(d.added, s)
+		}
+	}
+
+	sort.Strings(d.removed)
+	sort.Strings(d.added)
+	sort.Strings(d.unchanged)
+
+	return d
+}
+
+// writeSpec writes a resource spec as YAML, or as JSON when the output format
+// is json.
+func writeSpec(out io.Writer, spec interface{}, format string) error {
+	if format == "json" {
+		e := json.NewEncoder(out)
+		e.SetIndent("", "  ")
+		return e.Encode(spec)
+	}
+
+	b, err := yaml.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("marshaling the spec as yaml: %v", err)
+	}
+	_, err = out.Write(b)
+	return err
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKeyedByName = map[string]elementKeyFn{
+	"rules": func(m map[string]interface{}) string { return fmt.Sprint(m["name"]) },
+}
+
+func TestDiffFields(t *testing.T) {
+	current := map[string]interface{}{
+		"name":  "web",
+		"size":  1,
+		"tags":  []string{"a"},
+		"check": map[string]interface{}{"port": 80, "path": "/"},
+		"rules": []map[string]interface{}{
+			{"name": "http", "port": 80},
+			{"name": "ssh", "port": 22},
+		},
+	}
+	desired := map[string]interface{}{
+		"name":  "web",
+		"tags":  []string{"a", "b"},
+		"check": map[string]interface{}{"port": 8080, "path": "/"},
+		"rules": []map[string]interface{}{
+			{"name": "ssh", "port": 22},
+			{"name": "https", "port": 443},
+		},
+	}
+
+	changes, err := diffFields(current, desired, testKeyedByName)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writeFieldChanges(&buf, changes)
+	assert.Equal(t, `~ check.port: 80 -> 8080
+- rules[http].name: http
+- rules[http].port: 80
++ rules[https].name: https
++ rules[https].port: 443
+- size: 1
+~ tags: ["a"] -> ["a","b"]
+`, buf.String())
+
+	changes, err = diffFields(current, current, testKeyedByName)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	buf.Reset()
+	writeFieldChanges(&buf, changes)
+	assert.Equal(t, "No changes.\n", buf.String())
+}
+
+func TestMergeJSONFields(t *testing.T) {
+	base := map[string]interface{}{
+		"name":  "web",
+		"size":  float64(1),
+		"tags":  []interface{}{"a"},
+		"check": map[string]interface{}{"port": float64(80), "path": "/"},
+		"rules": []interface{}{
+			map[string]interface{}{"name": "http", "port": float64(80)},
+			map[string]interface{}{"name": "ssh", "port": float64(22)},
+		},
+	}
+	patch := map[string]interface{}{
+		"size":  nil,
+		"tags":  []interface{}{"b"},
+		"check": map[string]interface{}{"port": float64(8080)},
+		"rules": []interface{}{
+			map[string]interface{}{"name": "http", "port": float64(8080)},
+			map[string]interface{}{"name": "https", "port": float64(443)},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":  "web",
+		"tags":  []interface{}{"b"},
+		"check": map[string]interface{}{"port": float64(8080), "path": "/"},
+		"rules": []interface{}{
+			map[string]interface{}{"name": "http", "port": float64(8080)},
+			map[string]interface{}{"name": "ssh", "port": float64(22)},
+			map[string]interface{}{"name": "https", "port": float64(443)},
+		},
+	}, mergeJSONFields(base, patch, testKeyedByName))
+
+	// The base document is left untouched.
+	assert.Equal(t, float64(80), base["check"].(map[string]interface{})["port"])
+}
@@ -14,6 +14,9 @@ limitations under the License.
 package displayers
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"sort"
 	"strconv"
@@ -309,6 +312,79 @@ func (dr *DatabaseReplicas) KV() []map[string]interface{} {
 	return out
 }
 
+type DatabaseReplicaStatus struct {
+	Primary  do.Database         `json:"primary"`
+	Replicas do.DatabaseReplicas `json:"replicas"`
+}
+
+var _ Displayable = &DatabaseReplicaStatus{}
+
+func (drs *DatabaseReplicaStatus) JSON(out io.Writer) error {
+	return writeJSON(drs, out)
+}
+
+func (drs *DatabaseReplicaStatus) Cols() []string {
+	return []string{
+		"Role",
+		"Name",
+		"ID",
+		"Region",
+		"Size",
+		"Status",
+		"Host",
+		"PrivateHost",
+	}
+}
+
+func (drs *DatabaseReplicaStatus) ColMap() map[string]string {
+	return map[string]string{
+		"Role":        "Role",
+		"Name":        "Name",
+		"ID":          "ID",
+		"Region":      "Region",
+		"Size":        "Size",
+		"Status":      "Status",
+		"Host":        "Host",
+		"PrivateHost": "Private Host",
+	}
+}
+
+func (drs *DatabaseReplicaStatus) KV() []map[string]interface{} {
+	host := func(conn *godo.DatabaseConnection) string {
+		if conn == nil {
+			return ""
+		}
+		return conn.Host
+	}
+
+	p := drs.Primary
+	out := []map[string]interface{}{{
+		"Role":        "primary",
+		"Name":        p.Name,
+		"ID":          p.ID,
+		"Region":      p.RegionSlug,
+		"Size":        p.SizeSlug,
+		"Status":      p.Status,
+		"Host":        host(p.Connection),
+		"PrivateHost": host(p.PrivateConnection),
+	}}
+
+	for _, r := range drs.Replicas {
+		out = append(out, map[string]interface{}{
+			"Role":        "replica",
+			"Name":        r.Name,
+			"ID":          r.ID,
+			"Region":      r.Region,
+			"Size":        r.Size,
+			"Status":      r.Status,
+			"Host":        host(r.Connection),
+			"PrivateHost": host(r.PrivateConnection),
+		})
+	}
+
+	return out
+}
+
 type DatabaseOptions struct {
 	DatabaseOptions do.DatabaseOptions
 }
@@ -533,6 +609,66 @@ func (dp *DatabasePools) KV() []map[string]interface{} {
 	return out
 }
 
+type DatabasePoolPlan struct {
+	Pools           do.DatabasePools `json:"pools"`
+	ConnectionLimit int              `json:"connection_limit"`
+}
+
+var _ Displayable = &DatabasePoolPlan{}
+
+// TotalSize returns the number of connections allocated to all pools.
+func (dp *DatabasePoolPlan) TotalSize() int {
+	total := 0
+	for _, p := range dp.Pools {
+		total += p.Size
+	}
+	return total
+}
+
+func (dp *DatabasePoolPlan) JSON(out io.Writer) error {
+	return writeJSON(struct {
+		*DatabasePoolPlan
+		TotalSize int `json:"total_size"`
+		Available int `json:"available"`
+	}{dp, dp.TotalSize(), dp.ConnectionLimit - dp.TotalSize()}, out)
+}
+
+func (dp *DatabasePoolPlan) Cols() []string {
+	return []string{
+		"Pools",
+		"TotalSize",
+		"ConnectionLimit",
+		"Available",
+		"Utilization",
+	}
+}
+
+func (dp *DatabasePoolPlan) ColMap() map[string]string {
+	return map[string]string{
+		"Pools":           "Pools",
+		"TotalSize":       "Total Pool Size",
+		"ConnectionLimit": "Connection Limit",
+		"Available":       "Available",
+		"Utilization":     "Utilization",
+	}
+}
+
+func (dp *DatabasePoolPlan) KV() []map[string]interface{} {
+	total := dp.TotalSize()
+	utilization := "-"
+	if dp.ConnectionLimit > 0 {
+		utilization = fmt.Sprintf("%d%%", total*100/dp.ConnectionLimit)
+	}
+
+	return []map[string]interface{}{{
+		"Pools":           len(dp.Pools),
+		"TotalSize":       total,
+		"ConnectionLimit": dp.ConnectionLimit,
+		"Available":       dp.ConnectionLimit - total,
+		"Utilization":     utilization,
+	}}
+}
+
 type DatabaseMaintenanceWindow struct {
 	DatabaseMaintenanceWindow do.DatabaseMaintenanceWindow
 }
@@ -640,6 +776,36 @@ func (dsm *DatabaseSQLModes) KV() []map[string]interface{} {
 	return out
 }
 
+type DatabaseEvictionPolicy struct {
+	DatabaseEvictionPolicy string
+}
+
+var _ Displayable = &DatabaseEvictionPolicy{}
+
+func (dep *DatabaseEvictionPolicy) JSON(out io.Writer) error {
+	return writeJSON(struct {
+		EvictionPolicy string `json:"eviction_policy"`
+	}{dep.DatabaseEvictionPolicy}, out)
+}
+
+func (dep *DatabaseEvictionPolicy) Cols() []string {
+	return []string{
+		"EvictionPolicy",
+	}
+}
+
+func (dep *DatabaseEvictionPolicy) ColMap() map[string]string {
+	return map[string]string{
+		"EvictionPolicy": "Eviction Policy",
+	}
+}
+
+func (dep *DatabaseEvictionPolicy) KV() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"EvictionPolicy": dep.DatabaseEvictionPolicy},
+	}
+}
+
 type DatabaseFirewallRules struct {
 	DatabaseFirewallRules do.DatabaseFirewallRules
 }
@@ -684,3 +850,79 @@ func (dr *DatabaseFirewallRules) KV() []map[string]interface{} {
 
 	return out
 }
+
+// DatabaseConfiguration displays the engine configuration of a database
+// cluster as one row per setting. Settings that are not set are omitted.
+type DatabaseConfiguration struct {
+	DatabaseConfiguration interface{}
+}
+
+var _ Displayable = &DatabaseConfiguration{}
+
+func (dc *DatabaseConfiguration) JSON(out io.Writer) error {
+	return writeJSON(dc.DatabaseConfiguration, out)
+}
+
+func (dc *DatabaseConfiguration) Cols() []string {
+	return []string{
+		"Key",
+		"Value",
+	}
+}
+
+func (dc *DatabaseConfiguration) ColMap() map[string]string {
+	return map[string]string{
+		"Key":   "Key",
+		"Value": "Value",
+	}
+}
+
+func (dc *DatabaseConfiguration) KV() []map[string]interface{} {
+	settings := map[string]interface{}{}
+	if b, err := json.Marshal(dc.DatabaseConfiguration); err == nil {
+		var generic map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if dec.Decode(&generic) == nil {
+			flattenDatabaseSettings("", generic, settings)
+		}
+	}
+
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]map[string]interface{}, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, map[string]interface{}{
+			"Key":   k,
+			"Value": settings[k],
+		})
+	}
+
+	return out
+}
+
+// flattenDatabaseSettings flattens nested settings such as pgbouncer's into
+// dotted keys.
+func flattenDatabaseSettings(prefix string, settings map[string]interface{}, out map[string]interface{}) {
+	for k, v := range settings {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		switch t := v.(type) {
+		case map[string]interface{}:
+			flattenDatabaseSettings(k, t, out)
+		case []interface{}:
+			values := make([]string, 0, len(t))
+			for _, e := range t {
+				values = append(values, fmt.Sprint(e))
+			}
+			out[k] = strings.Join(values, ",")
+		default:
+			out[k] = t
+		}
+	}
+}
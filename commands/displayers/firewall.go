@@ -146,3 +146,80 @@ func dropletListHelper(IDs []int) string {
 
 	return strings.Join(output, ",")
 }
+
+// FirewallCheckResult is the outcome of checking traffic against the
+// firewall rules that apply to a Droplet.
+type FirewallCheckResult struct {
+	Direction    string `json:"direction"`
+	Protocol     string `json:"protocol"`
+	Port         int    `json:"port,omitempty"`
+	Peer         string `json:"peer"`
+	Allowed      bool   `json:"allowed"`
+	FirewallID   string `json:"firewall_id,omitempty"`
+	FirewallName string `json:"firewall_name,omitempty"`
+	Rule         string `json:"rule,omitempty"`
+}
+
+type FirewallCheck struct {
+	Results []FirewallCheckResult
+}
+
+var _ Displayable = &FirewallCheck{}
+
+func (f *FirewallCheck) JSON(out io.Writer) error {
+	return writeJSON(f.Results, out)
+}
+
+func (f *FirewallCheck) Cols() []string {
+	return []string{
+		"Result",
+		"Direction",
+		"Protocol",
+		"Port",
+		"Peer",
+		"FirewallID",
+		"FirewallName",
+		"Rule",
+	}
+}
+
+func (f *FirewallCheck) ColMap() map[string]string {
+	return map[string]string{
+		"Result":       "Result",
+		"Direction":    "Direction",
+		"Protocol":     "Protocol",
+		"Port":         "Port",
+		"Peer":         "Peer",
+		"FirewallID":   "Firewall ID",
+		"FirewallName": "Firewall Name",
+		"Rule":         "Rule",
+	}
+}
+
+func (f *FirewallCheck) KV() []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(f.Results))
+
+	for _, r := range f.Results {
+		result := "deny"
+		if r.Allowed {
+			result = "allow"
+		}
+		port := ""
+		if r.Port > 0 {
+			port = strconv.Itoa(r.Port)
+		}
+		o := map[string]interface{}{
+			"Result":       result,
+			"Direction":    r.Direction,
+			"Protocol":     r.Protocol,
+			"Port":         port,
+			"Peer":         r.Peer,
+			"FirewallID":   r.FirewallID,
+			"FirewallName": r.FirewallName,
+			"Rule":         r.Rule,
+		}
+		out = append(out, o)
+	}
+
+	return out
+}
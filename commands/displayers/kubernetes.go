@@ -1,6 +1,7 @@
 package displayers
 
 import (
+	"fmt"
 	"io"
 	"strings"
 
@@ -341,3 +342,259 @@ func flattenAssociatedResourceIDs(resources []*godo.AssociatedResource) (out []s
 
 	return out
 }
+
+type KubernetesClusterlintDiagnostics struct {
+	Diagnostics []*godo.ClusterlintDiagnostic
+}
+
+var _ Displayable = &KubernetesClusterlintDiagnostics{}
+
+func (d *KubernetesClusterlintDiagnostics) JSON(out io.Writer) error {
+	return writeJSON(d.Diagnostics, out)
+}
+
+func (d *KubernetesClusterlintDiagnostics) Cols() []string {
+	return []string{
+		"Severity",
+		"Kind",
+		"Namespace",
+		"Name",
+		"Check",
+		"Message",
+	}
+}
+
+func (d *KubernetesClusterlintDiagnostics) ColMap() map[string]string {
+	return map[string]string{
+		"Severity":  "Severity",
+		"Kind":      "Kind",
+		"Namespace": "Namespace",
+		"Name":      "Name",
+		"Check":     "Check",
+		"Message":   "Message",
+	}
+}
+
+func (d *KubernetesClusterlintDiagnostics) KV() []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(d.Diagnostics))
+
+	for _, diag := range d.Diagnostics {
+		o := map[string]interface{}{
+			"Severity": diag.Severity,
+			"Check":    diag.CheckName,
+			"Message":  diag.Message,
+		}
+		if diag.Object != nil {
+			o["Kind"] = diag.Object.Kind
+			o["Namespace"] = diag.Object.Namespace
+			o["Name"] = diag.Object.Name
+		}
+		out = append(out, o)
+	}
+
+	return out
+}
+
+// KubernetesCostItem is the estimated monthly cost of one of a cluster's
+// billable resources. Node pools that autoscale also have a minimum and a
+// maximum cost.
+type KubernetesCostItem struct {
+	Type              string  `json:"type"`
+	ID                string  `json:"id,omitempty"`
+	Name              string  `json:"name"`
+	Size              string  `json:"size,omitempty"`
+	Count             int     `json:"count"`
+	MinCount          int     `json:"min_count"`
+	MaxCount          int     `json:"max_count"`
+	UnitPriceMonthly  float64 `json:"unit_price_monthly"`
+	PriceMonthly      float64 `json:"price_monthly"`
+	MinPriceMonthly   float64 `json:"min_price_monthly"`
+	MaxPriceMonthly   float64 `json:"max_price_monthly"`
+	PriceUnknown      bool    `json:"price_unknown,omitempty"`
+	CapacityVCPUs     int     `json:"capacity_vcpus,omitempty"`
+	CapacityMemoryMiB int     `json:"capacity_memory_mib,omitempty"`
+}
+
+// KubernetesCostReport is the estimated monthly cost of a cluster and the
+// resources associated with it.
+type KubernetesCostReport struct {
+	ClusterID            string               `json:"cluster_id"`
+	ClusterName          string               `json:"cluster_name"`
+	Items                []KubernetesCostItem `json:"items"`
+	TotalPriceMonthly    float64              `json:"total_price_monthly"`
+	MinTotalPriceMonthly float64              `json:"min_total_price_monthly"`
+	MaxTotalPriceMonthly float64              `json:"max_total_price_monthly"`
+}
+
+type KubernetesCost struct {
+	Report *KubernetesCostReport
+}
+
+var _ Displayable = &KubernetesCost{}
+
+func (k *KubernetesCost) JSON(out io.Writer) error {
+	return writeJSON(k.Report, out)
+}
+
+func (k *KubernetesCost) Cols() []string {
+	return []string{
+		"Type",
+		"Name",
+		"Size",
+		"Count",
+		"Range",
+		"VCPUs",
+		"Memory",
+		"UnitPrice",
+		"Monthly",
+		"MonthlyRange",
+	}
+}
+
+func (k *KubernetesCost) ColMap() map[string]string {
+	return map[string]string{
+		"Type":         "Type",
+		"Name":         "Name",
+		"Size":         "Size",
+		"Count":        "Count",
+		"Range":        "Min/Max",
+		"VCPUs":        "VCPUs",
+		"Memory":       "Memory (MiB)",
+		"UnitPrice":    "Unit Price",
+		"Monthly":      "Monthly",
+		"MonthlyRange": "Monthly Min/Max",
+	}
+}
+
+func (k *KubernetesCost) KV() []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(k.Report.Items)+1)
+
+	for _, item := range k.Report.Items {
+		o := map[string]interface{}{
+			"Type":         item.Type,
+			"Name":         item.Name,
+			"Size":         item.Size,
+			"Count":        item.Count,
+			"Range":        "",
+			"VCPUs":        "",
+			"Memory":       "",
+			"UnitPrice":    fmt.Sprintf("%0.2f", item.UnitPriceMonthly),
+			"Monthly":      fmt.Sprintf("%0.2f", item.PriceMonthly),
+			"MonthlyRange": "",
+		}
+		if item.MinCount != item.MaxCount {
+			o["Range"] = fmt.Sprintf("%d-%d", item.MinCount, item.MaxCount)
+			o["MonthlyRange"] = fmt.Sprintf("%0.2f-%0.2f", item.MinPriceMonthly, item.MaxPriceMonthly)
+		}
+		if item.CapacityVCPUs > 0 {
+			o["VCPUs"] = item.CapacityVCPUs
+			o["Memory"] = item.CapacityMemoryMiB
+		}
+		if item.PriceUnknown {
+			o["UnitPrice"] = "unknown"
+			o["Monthly"] = "unknown"
+		}
+		out = append(out, o)
+	}
+
+	total := map[string]interface{}{
+		"Type":         "total",
+		"Name":         k.Report.ClusterName,
+		"Size":         "",
+		"Count":        "",
+		"Range":        "",
+		"VCPUs":        "",
+		"Memory":       "",
+		"UnitPrice":    "",
+		"Monthly":      fmt.Sprintf("%0.2f", k.Report.TotalPriceMonthly),
+		"MonthlyRange": "",
+	}
+	if k.Report.MinTotalPriceMonthly != k.Report.MaxTotalPriceMonthly {
+		total["MonthlyRange"] = fmt.Sprintf("%0.2f-%0.2f", k.Report.MinTotalPriceMonthly, k.Report.MaxTotalPriceMonthly)
+	}
+	out = append(out, total)
+
+	return out
+}
+
+// KubernetesRegistryNamespace reports whether a namespace has the registry's
+// pull secret, and whether its default service account uses it.
+type KubernetesRegistryNamespace struct {
+	Namespace             string `json:"namespace"`
+	Secret                bool   `json:"secret"`
+	DefaultServiceAccount bool   `json:"default_service_account"`
+}
+
+// KubernetesRegistryClusterStatus is the registry integration status of a
+// cluster.
+type KubernetesRegistryClusterStatus struct {
+	ClusterID       string                        `json:"cluster_id"`
+	ClusterName     string                        `json:"cluster_name"`
+	RegistryEnabled bool                          `json:"registry_enabled"`
+	SecretName      string                        `json:"secret_name"`
+	Namespaces      []KubernetesRegistryNamespace `json:"namespaces"`
+	Error           string                        `json:"error,omitempty"`
+}
+
+type KubernetesRegistryStatus struct {
+	Clusters []KubernetesRegistryClusterStatus
+}
+
+var _ Displayable = &KubernetesRegistryStatus{}
+
+func (r *KubernetesRegistryStatus) JSON(out io.Writer) error {
+	return writeJSON(r.Clusters, out)
+}
+
+func (r *KubernetesRegistryStatus) Cols() []string {
+	return []string{
+		"ID",
+		"Name",
+		"RegistryEnabled",
+		"SecretName",
+		"Namespaces",
+		"ServiceAccounts",
+	}
+}
+
+func (r *KubernetesRegistryStatus) ColMap() map[string]string {
+	return map[string]string{
+		"ID":              "ID",
+		"Name":            "Name",
+		"RegistryEnabled": "Registry Enabled",
+		"SecretName":      "Secret",
+		"Namespaces":      "Namespaces With Secret",
+		"ServiceAccounts": "Default Service Accounts Using Secret",
+	}
+}
+
+func (r *KubernetesRegistryStatus) KV() []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(r.Clusters))
+
+	for _, cluster := range r.Clusters {
+		var namespaces, serviceAccounts []string
+		for _, ns := range cluster.Namespaces {
+			if ns.Secret {
+				namespaces = append(namespaces, ns.Namespace)
+			}
+			if ns.DefaultServiceAccount {
+				serviceAccounts = append(serviceAccounts, ns.Namespace)
+			}
+		}
+		o := map[string]interface{}{
+			"ID":              cluster.ClusterID,
+			"Name":            cluster.ClusterName,
+			"RegistryEnabled": cluster.RegistryEnabled,
+			"SecretName":      cluster.SecretName,
+			"Namespaces":      strings.Join(namespaces, ","),
+			"ServiceAccounts": strings.Join(serviceAccounts, ","),
+		}
+		if cluster.Error != "" {
+			o["Namespaces"] = "unknown: " + cluster.Error
+			o["ServiceAccounts"] = "unknown"
+		}
+		out = append(out, o)
+	}
+
+	return out
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/digitalocean/doctl/do"
@@ -136,3 +137,120 @@ func prettyPrintStruct(obj interface{}) string {
 
 	return strings.Join(output, ",")
 }
+
+// LoadBalancerBackendStatus is the state of a Droplet behind a load balancer.
+type LoadBalancerBackendStatus struct {
+	DropletID   int    `json:"droplet_id"`
+	DropletName string `json:"droplet_name,omitempty"`
+	Status      string `json:"status"`
+	PublicIPv4  string `json:"public_ipv4,omitempty"`
+	PrivateIPv4 string `json:"private_ipv4,omitempty"`
+}
+
+// LoadBalancerRuleStatus is the state of a load balancer's forwarding rule,
+// including the expiry of its TLS certificate if it has one.
+type LoadBalancerRuleStatus struct {
+	Rule            string `json:"rule"`
+	Status          string `json:"status"`
+	CertificateID   string `json:"certificate_id,omitempty"`
+	CertificateName string `json:"certificate_name,omitempty"`
+	NotAfter        string `json:"certificate_not_after,omitempty"`
+	DaysLeft        *int   `json:"certificate_days_left,omitempty"`
+}
+
+// LoadBalancerStatusReport combines a load balancer's status with the status
+// of its backends and forwarding rules.
+type LoadBalancerStatusReport struct {
+	ID              string                      `json:"id"`
+	Name            string                      `json:"name"`
+	IP              string                      `json:"ip"`
+	Status          string                      `json:"status"`
+	HealthCheck     string                      `json:"health_check,omitempty"`
+	Backends        []LoadBalancerBackendStatus `json:"backends"`
+	ForwardingRules []LoadBalancerRuleStatus    `json:"forwarding_rules"`
+}
+
+type LoadBalancerStatus struct {
+	Report *LoadBalancerStatusReport
+}
+
+var _ Displayable = &LoadBalancerStatus{}
+
+func (lb *LoadBalancerStatus) JSON(out io.Writer) error {
+	return writeJSON(lb.Report, out)
+}
+
+func (lb *LoadBalancerStatus) Cols() []string {
+	return []string{
+		"Component",
+		"Name",
+		"Status",
+		"Details",
+	}
+}
+
+func (lb *LoadBalancerStatus) ColMap() map[string]string {
+	return map[string]string{
+		"Component": "Component",
+		"Name":      "Name",
+		"Status":    "Status",
+		"Details":   "Details",
+	}
+}
+
+func (lb *LoadBalancerStatus) KV() []map[string]interface{} {
+	r := lb.Report
+	out := make([]map[string]interface{}, 0, 1+len(r.Backends)+len(r.ForwardingRules))
+
+	out = append(out, map[string]interface{}{
+		"Component": "load-balancer",
+		"Name":      r.Name,
+		"Status":    r.Status,
+		"Details":   strings.TrimSpace(r.IP + " " + r.HealthCheck),
+	})
+
+	for _, b := range r.Backends {
+		name := strconv.Itoa(b.DropletID)
+		if b.DropletName != "" {
+			name = fmt.Sprintf("%s (%d)", b.DropletName, b.DropletID)
+		}
+		details := make([]string, 0, 2)
+		if b.PublicIPv4 != "" {
+			details = append(details, "public:"+b.PublicIPv4)
+		}
+		if b.PrivateIPv4 != "" {
+			details = append(details, "private:"+b.PrivateIPv4)
+		}
+		out = append(out, map[string]interface{}{
+			"Component": "droplet",
+			"Name":      name,
+			"Status":    b.Status,
+			"Details":   strings.Join(details, ","),
+		})
+	}
+
+	for _, fr := range r.ForwardingRules {
+		details := ""
+		if fr.CertificateID != "" {
+			cert := fr.CertificateName
+			if cert == "" {
+				cert = fr.CertificateID
+			}
+			details = "certificate:" + cert
+			if fr.NotAfter != "" {
+				details += ",not_after:" + fr.NotAfter
+			}
+			if fr.DaysLeft != nil {
+				details += fmt.Sprintf(",days_left:%d", *fr.DaysLeft)
+			}
+		}
+		out = append(out, map[string]interface{}{
+			"Component": "rule",
+			"Name":      fr.Rule,
+			"Status":    fr.Status,
+			"Details":   details,
+		})
+	}
+
+	return out
+}
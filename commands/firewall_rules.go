@@ -0,0 +1,610 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/doctl/commands/displayers"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	multierror "github.com/hashicorp/go-multierror"
+	"sigs.k8s.io/yaml"
+)
+
+// firewallRulesFile is the YAML or JSON document accepted by `--rules-file`.
+// It mirrors the inbound_rules and outbound_rules attributes of a firewall.
+type firewallRulesFile struct {
+	InboundRules  []firewallRuleSpec `json:"inbound_rules"`
+	OutboundRules []firewallRuleSpec `json:"outbound_rules"`
+}
+
+type firewallRuleSpec struct {
+	Protocol     string             `json:"protocol"`
+	Ports        firewallPorts      `json:"ports,omitempty"`
+	Sources      *godo.Sources      `json:"sources,omitempty"`
+	Destinations *godo.Destinations `json:"destinations,omitempty"`
+}
+
+// firewallPorts accepts both `ports: 22` and `ports: "8000-9000"`.
+type firewallPorts string
+
+func (p *firewallPorts) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err == nil {
+		*p = firewallPorts(n.String())
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("ports must be a number or a string")
+	}
+	*p = firewallPorts(s)
+	return nil
+}
+
+// readFirewallRulesFile reads and validates a rules file. A path of "-" reads from stdin.
+func readFirewallRulesFile(stdin io.Reader, path string) (*firewallRulesFile, error) {
+	var r io.Reader
+	if path == "-" && stdin != nil {
+		r = stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("opening rules file: %s does not exist", path)
+			}
+			return nil, fmt.Errorf("opening rules file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading rules file: %w", err)
+	}
+
+	rules, err := parseFirewallRules(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing rules file: %w", err)
+	}
+
+	if err := rules.validate(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func parseFirewallRules(b []byte) (*firewallRulesFile, error) {
+	jsonRules, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonRules))
+	dec.DisallowUnknownFields()
+
+	var rules firewallRulesFile
+	if err := dec.Decode(&rules); err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+// validate checks every rule locally so mistakes are reported all at once,
+// before anything is sent to the API.
+func (f *firewallRulesFile) validate() error {
+	var errs error
+
+	if len(f.InboundRules) == 0 && len(f.OutboundRules) == 0 {
+		return fmt.Errorf("rules file must contain at least one inbound or outbound rule")
+	}
+
+	for i, r := range f.InboundRules {
+		field := fmt.Sprintf("inbound_rules[%d]", i)
+		if err := validateFirewallProtocolAndPorts(r.Protocol, string(r.Ports)); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %v", field, err))
+		}
+		if r.Destinations != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: inbound rules take sources, not destinations", field))
+		}
+		if r.Sources == nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: sources are required", field))
+			continue
+		}
+		for _, err := range validateFirewallTargets(r.Sources.Addresses, r.Sources.Tags, r.Sources.DropletIDs, r.Sources.LoadBalancerUIDs, r.Sources.KubernetesIDs) {
+			errs = multierror.Append(errs, fmt.Errorf("%s.sources: %v", field, err))
+		}
+	}
+
+	for i, r := range f.OutboundRules {
+		field := fmt.Sprintf("outbound_rules[%d]", i)
+		if err := validateFirewallProtocolAndPorts(r.Protocol, string(r.Ports)); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %v", field, err))
+		}
+		if r.Sources != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: outbound rules take destinations, not sources", field))
+		}
+		if r.Destinations == nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: destinations are required", field))
+			continue
+		}
+		for _, err := range validateFirewallTargets(r.Destinations.Addresses, r.Destinations.Tags, r.Destinations.DropletIDs, r.Destinations.LoadBalancerUIDs, r.Destinations.KubernetesIDs) {
+			errs = multierror.Append(errs, fmt.Errorf("%s.destinations: %v", field, err))
+		}
+	}
+
+	return errs
+}
+
+// godoRules converts the file's rules into their godo representation.
+func (f *firewallRulesFile) godoRules() ([]godo.InboundRule, []godo.OutboundRule) {
+	inbound := make([]godo.InboundRule, 0, len(f.InboundRules))
+	for _, r := range f.InboundRules {
+		inbound = append(inbound, godo.InboundRule{
+			Protocol:  strings.ToLower(r.Protocol),
+			PortRange: string(r.Ports),
+			Sources:   r.Sources,
+		})
+	}
+
+	outbound := make([]godo.OutboundRule, 0, len(f.OutboundRules))
+	for _, r := range f.OutboundRules {
+		outbound = append(outbound, godo.OutboundRule{
+			Protocol:     strings.ToLower(r.Protocol),
+			PortRange:    string(r.Ports),
+			Destinations: r.Destinations,
+		})
+	}
+
+	return inbound, outbound
+}
+
+func validateFirewallProtocolAndPorts(protocol, ports string) error {
+	switch strings.ToLower(protocol) {
+	case "icmp":
+		if ports != "" {
+			return fmt.Errorf("ports cannot be set for protocol icmp")
+		}
+		return nil
+	case "tcp", "udp":
+	case "":
+		return fmt.Errorf("protocol is required")
+	default:
+		return fmt.Errorf("unsupported protocol %q; must be one of tcp, udp or icmp", protocol)
+	}
+
+	if ports == "" {
+		return fmt.Errorf("ports are required for protocol %s", protocol)
+	}
+	if ports == "all" || ports == "0" {
+		return nil
+	}
+
+	_, _, err := parseFirewallPortRange(ports)
+	return err
+}
+
+// parseFirewallPortRange parses a single port or a range of ports such as
+// `8000-9000`. "all" and "0" are returned as the full port range.
+func parseFirewallPortRange(ports string) (int, int, error) {
+	if ports == "" || ports == "all" || ports == "0" {
+		return 1, 65535, nil
+	}
+
+	lowStr, highStr := ports, ports
+	if parts := strings.SplitN(ports, "-", 2); len(parts) == 2 {
+		lowStr, highStr = parts[0], parts[1]
+	}
+
+	low, err := strconv.Atoi(lowStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ports %q", ports)
+	}
+	high, err := strconv.Atoi(highStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ports %q", ports)
+	}
+	if low < 1 || high > 65535 || low > high {
+		return 0, 0, fmt.Errorf("invalid ports %q; ports must be between 1 and 65535 and ranges must be ascending", ports)
+	}
+
+	return low, high, nil
+}
+
+func validateFirewallTargets(addresses, tags []string, dropletIDs []int, lbUIDs, k8sIDs []string) []error {
+	var errs []error
+
+	if len(addresses)+len(tags)+len(dropletIDs)+len(lbUIDs)+len(k8sIDs) == 0 {
+		errs = append(errs, fmt.Errorf("at least one address, tag, Droplet ID, load balancer UID or Kubernetes ID is required"))
+	}
+
+	for _, a := range addresses {
+		if _, _, err := net.ParseCIDR(a); err == nil {
+			continue
+		}
+		if net.ParseIP(a) == nil {
+			errs = append(errs, fmt.Errorf("invalid address %q; must be an IP address or CIDR", a))
+		}
+	}
+
+	for _, t := range tags {
+		if t == "" {
+			errs = append(errs, fmt.Errorf("tags cannot be empty"))
+		}
+	}
+
+	for _, id := range dropletIDs {
+		if id <= 0 {
+			errs = append(errs, fmt.Errorf("invalid Droplet ID %d", id))
+		}
+	}
+
+	for _, id := range lbUIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			errs = append(errs, fmt.Errorf("invalid load balancer UID %q", id))
+		}
+	}
+
+	for _, id := range k8sIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			errs = append(errs, fmt.Errorf("invalid Kubernetes ID %q", id))
+		}
+	}
+
+	return errs
+}
+
+// firewallRuleString renders a rule in the same key:value form used by
+// `--inbound-rules` and `--outbound-rules`, with the targets sorted so that
+// equivalent rules render identically.
+func firewallRuleString(protocol, ports string, addresses, tags []string, dropletIDs []int, lbUIDs, k8sIDs []string) string {
+	protocol = strings.ToLower(protocol)
+	parts := []string{"protocol:" + protocol}
+	if protocol != "icmp" {
+		if ports == "" || ports == "0" {
+			ports = "all"
+		}
+		parts = append(parts, "ports:"+ports)
+	}
+
+	var targets []string
+	for _, a := range addresses {
+		targets = append(targets, "address:"+a)
+	}
+	for _, t := range tags {
+		targets = append(targets, "tag:"+t)
+	}
+	for _, id := range dropletIDs {
+		targets = append(targets, "droplet_id:"+strconv.Itoa(id))
+	}
+	for _, id := range lbUIDs {
+		targets = append(targets, "load_balancer_uid:"+id)
+	}
+	for _, id := range k8sIDs {
+		targets = append(targets, "kubernetes_id:"+id)
+	}
+	sort.Strings(targets)
+
+	return strings.Join(append(parts, targets...), ",")
+}
+
+func inboundRuleStrings(rules []godo.InboundRule) []string {
+	out := make([]string, 0, len(rules))
+	for _, r := range rules {
+		s := r.Sources
+		if s == nil {
+			s = &godo.Sources{}
+		}
+		out = append(out, firewallRuleString(r.Protocol, r.PortRange, s.Addresses, s.Tags, s.DropletIDs, s.LoadBalancerUIDs, s.KubernetesIDs))
+	}
+	return out
+}
+
+func outboundRuleStrings(rules []godo.OutboundRule) []string {
+	out := make([]string, 0, len(rules))
+	for _, r := range rules {
+		d := r.Destinations
+		if d == nil {
+			d = &godo.Destinations{}
+		}
+		out = append(out, firewallRuleString(r.Protocol, r.PortRange, d.Addresses, d.Tags, d.DropletIDs, d.LoadBalancerUIDs, d.KubernetesIDs))
+	}
+	return out
+}
+
+// writeFirewallRulesDiff writes a rule-by-rule diff between a live firewall and
+// the desired rules, and reports whether any rule would change.
+func writeFirewallRulesDiff(w io.Writer, live *do.Firewall, inbound []godo.InboundRule, outbound []godo.OutboundRule) bool {
+	in := diffStrings(inboundRuleStrings(live.InboundRules), inboundRuleStrings(inbound))
+	out := diffStrings(outboundRuleStrings(live.OutboundRules), outboundRuleStrings(outbound))
+
+	fmt.Fprintf(w, "Rule changes for firewall %s (%s):\n", live.Name, live.ID)
+	writeRuleDiffSection(w, "Inbound rules", in)
+	writeRuleDiffSection(w, "Outbound rules", out)
+
+	changed := in.changed() || out.changed()
+	if !changed {
+		fmt.Fprintln(w, "No rule changes.")
+	}
+
+	return changed
+}
+
+func writeRuleDiffSection(w io.Writer, title string, d stringsDiff) {
+	if len(d.removed)+len(d.added)+len(d.unchanged) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "  %s:\n", title)
+	for _, s := range d.unchanged {
+		fmt.Fprintf(w, "      %s\n", s)
+	}
+	for _, s := range d.removed {
+		color.New(color.FgRed).Fprintf(w, "    - %s\n", s)
+	}
+	for _, s := range d.added {
+		color.New(color.FgGreen).Fprintf(w, "    + %s\n", s)
+	}
+}
+
+// firewallTraffic describes a connection to check against firewall rules.
+type firewallTraffic struct {
+	inbound  bool
+	protocol string
+	port     int
+	peer     net.IP
+}
+
+// parseFirewallTrafficPort parses a `--port` value such as `443/tcp`, `53/udp`,
+// `22` (TCP is assumed) or `icmp`.
+func parseFirewallTrafficPort(s string) (string, int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "icmp" {
+		return "icmp", 0, nil
+	}
+
+	portStr, protocol := s, "tcp"
+	if parts := strings.SplitN(s, "/", 2); len(parts) == 2 {
+		portStr, protocol = parts[0], parts[1]
+	}
+	if protocol != "tcp" && protocol != "udp" {
+		return "", 0, fmt.Errorf("invalid port %q; protocol must be tcp or udp", s)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port %q; must be a port between 1 and 65535 followed by an optional /tcp or /udp, or icmp", s)
+	}
+
+	return protocol, port, nil
+}
+
+// firewallPeerResolver resolves the addresses behind the Droplet, tag, load
+// balancer and Kubernetes targets of firewall rules, caching each lookup.
+type firewallPeerResolver struct {
+	droplets      do.DropletsService
+	loadBalancers do.LoadBalancersService
+	cache         map[string][]net.IP
+}
+
+func newFirewallPeerResolver(ds do.DropletsService, lbs do.LoadBalancersService) *firewallPeerResolver {
+	return &firewallPeerResolver{
+		droplets:      ds,
+		loadBalancers: lbs,
+		cache:         map[string][]net.IP{},
+	}
+}
+
+func (r *firewallPeerResolver) lookup(key string, fn func() ([]net.IP, error)) []net.IP {
+	if ips, ok := r.cache[key]; ok {
+		return ips
+	}
+
+	ips, err := fn()
+	if err != nil {
+		warn("Unable to resolve %s; rules referencing it are not evaluated: %v", key, err)
+	}
+	r.cache[key] = ips
+
+	return ips
+}
+
+func (r *firewallPeerResolver) dropletIPs(id int) []net.IP {
+	return r.lookup(fmt.Sprintf("Droplet %d", id), func() ([]net.IP, error) {
+		d, err := r.droplets.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		return dropletIPAddresses(*d), nil
+	})
+}
+
+func (r *firewallPeerResolver) taggedIPs(tag string) []net.IP {
+	return r.lookup(fmt.Sprintf("tag %q", tag), func() ([]net.IP, error) {
+		droplets, err := r.droplets.ListByTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		var ips []net.IP
+		for _, d := range droplets {
+			ips = append(ips, dropletIPAddresses(d)...)
+		}
+		return ips, nil
+	})
+}
+
+func (r *firewallPeerResolver) loadBalancerIPs(id string) []net.IP {
+	return r.lookup(fmt.Sprintf("load balancer %s", id), func() ([]net.IP, error) {
+		lb, err := r.loadBalancers.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if ip := net.ParseIP(lb.IP); ip != nil {
+			return []net.IP{ip}, nil
+		}
+		return nil, nil
+	})
+}
+
+// matches reports whether ip is covered by any of the targets of a rule.
+// Kubernetes clusters are matched through the `k8s:<cluster-id>` tag carried
+// by their worker nodes.
+func (r *firewallPeerResolver) matches(ip net.IP, addresses, tags []string, dropletIDs []int, lbUIDs, k8sIDs []string) bool {
+	for _, a := range addresses {
+		if _, cidr, err := net.ParseCIDR(a); err == nil {
+			if cidr.Contains(ip) {
+				return true
+			}
+		} else if parsed := net.ParseIP(a); parsed != nil && parsed.Equal(ip) {
+			return true
+		}
+	}
+
+	var ips []net.IP
+	for _, id := range dropletIDs {
+		ips = append(ips, r.dropletIPs(id)...)
+	}
+	for _, tag := range tags {
+		ips = append(ips, r.taggedIPs(tag)...)
+	}
+	for _, id := range k8sIDs {
+		ips = append(ips, r.taggedIPs("k8s:"+id)...)
+	}
+	for _, id := range lbUIDs {
+		ips = append(ips, r.loadBalancerIPs(id)...)
+	}
+
+	for _, candidate := range ips {
+		if candidate.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dropletIPAddresses(d do.Droplet) []net.IP {
+	var ips []net.IP
+	if d.Networks == nil {
+		return ips
+	}
+	for _, n := range d.Networks.V4 {
+		if ip := net.ParseIP(n.IPAddress); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	for _, n := range d.Networks.V6 {
+		if ip := net.ParseIP(n.IPAddress); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+func firewallRuleCoversTraffic(protocol, ports string, t firewallTraffic) bool {
+	if strings.ToLower(protocol) != t.protocol {
+		return false
+	}
+	if t.protocol == "icmp" {
+		return true
+	}
+
+	low, high, err := parseFirewallPortRange(ports)
+	if err != nil {
+		return false
+	}
+	return t.port >= low && t.port <= high
+}
+
+// checkFirewallTraffic evaluates traffic against the rules of every firewall
+// applied to a Droplet. Traffic is allowed when no firewall applies or when
+// any rule of any applied firewall matches it; one result is returned per
+// matching rule, or a single deny result when none match.
+func checkFirewallTraffic(firewalls do.Firewalls, t firewallTraffic, r *firewallPeerResolver) []displayers.FirewallCheckResult {
+	base := displayers.FirewallCheckResult{
+		Direction: "outbound",
+		Protocol:  t.protocol,
+		Port:      t.port,
+		Peer:      t.peer.String(),
+	}
+	if t.inbound {
+		base.Direction = "inbound"
+	}
+
+	if len(firewalls) == 0 {
+		res := base
+		res.Allowed = true
+		res.Rule = "no cloud firewalls apply to this Droplet"
+		return []displayers.FirewallCheckResult{res}
+	}
+
+	var results []displayers.FirewallCheckResult
+	for _, fw := range firewalls {
+		var rules []string
+		if t.inbound {
+			for i, rule := range fw.InboundRules {
+				s := rule.Sources
+				if s == nil || !firewallRuleCoversTraffic(rule.Protocol, rule.PortRange, t) {
+					continue
+				}
+				if r.matches(t.peer, s.Addresses, s.Tags, s.DropletIDs, s.LoadBalancerUIDs, s.KubernetesIDs) {
+					rules = append(rules, inboundRuleStrings(fw.InboundRules[i:i+1])...)
+				}
+			}
+		} else {
+			for i, rule := range fw.OutboundRules {
+				d := rule.Destinations
+				if d == nil || !firewallRuleCoversTraffic(rule.Protocol, rule.PortRange, t) {
+					continue
+				}
+				if r.matches(t.peer, d.Addresses, d.Tags, d.DropletIDs, d.LoadBalancerUIDs, d.KubernetesIDs) {
+					rules = append(rules, outboundRuleStrings(fw.OutboundRules[i:i+1])...)
+				}
+			}
+		}
+
+		for _, rule := range rules {
+			res := base
+			res.Allowed = true
+			res.FirewallID = fw.ID
+			res.FirewallName = fw.Name
+			res.Rule = rule
+			results = append(results, res)
+		}
+	}
+
+	if len(results) == 0 {
+		res := base
+		res.Rule = fmt.Sprintf("no rule in the %d applied firewall(s) matches", len(firewalls))
+		results = append(results, res)
+	}
+
+	return results
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/digitalocean/doctl/commands/displayers"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFirewallRules(t *testing.T) {
+	rules, err := parseFirewallRules([]byte(`
+inbound_rules:
+  - protocol: tcp
+    ports: 22
+    sources:
+      addresses: ["0.0.0.0/0", "::/0"]
+  - protocol: udp
+    ports: 8000-9000
+    sources:
+      droplet_ids: [123]
+outbound_rules:
+  - protocol: icmp
+    destinations:
+      kubernetes_ids: ["ab06e011-6dd1-4034-9293-201f71aba299"]
+`))
+	require.NoError(t, err)
+	require.NoError(t, rules.validate())
+
+	inbound, outbound := rules.godoRules()
+	assert.Equal(t, []godo.InboundRule{
+		{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0", "::/0"}}},
+		{Protocol: "udp", PortRange: "8000-9000", Sources: &godo.Sources{DropletIDs: []int{123}}},
+	}, inbound)
+	assert.Equal(t, []godo.OutboundRule{
+		{Protocol: "icmp", Destinations: &godo.Destinations{KubernetesIDs: []string{"ab06e011-6dd1-4034-9293-201f71aba299"}}},
+	}, outbound)
+}
+
+func TestParseFirewallRulesUnknownField(t *testing.T) {
+	_, err := parseFirewallRules([]byte(`
+inbound_rules:
+  - protocol: tcp
+    port: 22
+`))
+	assert.Error(t, err)
+}
+
+func TestFirewallRulesValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		errs  []string
+	}{
+		{
+			name:  "empty",
+			rules: `{}`,
+			errs:  []string{"at least one inbound or outbound rule"},
+		},
+		{
+			name: "bad protocol and ports",
+			rules: `
+inbound_rules:
+  - protocol: sctp
+    ports: 22
+    sources: {addresses: [0.0.0.0/0]}
+  - protocol: tcp
+    ports: 9000-8000
+    sources: {addresses: [0.0.0.0/0]}
+  - protocol: icmp
+    ports: 22
+    sources: {addresses: [0.0.0.0/0]}
+  - protocol: udp
+    sources: {addresses: [0.0.0.0/0]}
+`,
+			errs: []string{
+				`inbound_rules[0]: unsupported protocol "sctp"`,
+				`inbound_rules[1]: invalid ports "9000-8000"`,
+				`inbound_rules[2]: ports cannot be set for protocol icmp`,
+				`inbound_rules[3]: ports are required for protocol udp`,
+			},
+		},
+		{
+			name: "bad targets",
+			rules: `
+inbound_rules:
+  - protocol: tcp
+    ports: all
+    destinations: {addresses: [0.0.0.0/0]}
+outbound_rules:
+  - protocol: tcp
+    ports: 443
+    destinations:
+      addresses: [10.0.0.0/33]
+      load_balancer_uids: [lb-uuid]
+  - protocol: tcp
+    ports: 443
+    destinations: {}
+`,
+			errs: []string{
+				`inbound_rules[0]: inbound rules take sources, not destinations`,
+				`inbound_rules[0]: sources are required`,
+				`outbound_rules[0].destinations: invalid address "10.0.0.0/33"`,
+				`outbound_rules[0].destinations: invalid load balancer UID "lb-uuid"`,
+				`outbound_rules[1].destinations: at least one address`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := parseFirewallRules([]byte(tt.rules))
+			require.NoError(t, err)
+
+			err = rules.validate()
+			require.Error(t, err)
+			for _, e := range tt.errs {
+				assert.Contains(t, err.Error(), e)
+			}
+		})
+	}
+}
+
+func TestWriteFirewallRulesDiff(t *testing.T) {
+	live := &do.Firewall{Firewall: &godo.Firewall{
+		ID:   "ab06e011-6dd1-4034-9293-201f71aba299",
+		Name: "web",
+		InboundRules: []godo.InboundRule{
+			{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+			{Protocol: "tcp", PortRange: "0", Sources: &godo.Sources{Tags: []string{"b", "a"}}},
+		},
+		OutboundRules: []godo.OutboundRule{
+			{Protocol: "icmp", PortRange: "0", Destinations: &godo.Destinations{Addresses: []string{"0.0.0.0/0"}}},
+		},
+	}}
+
+	inbound := []godo.InboundRule{
+		{Protocol: "tcp", PortRange: "all", Sources: &godo.Sources{Tags: []string{"a", "b"}}},
+		{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+	}
+	outbound := []godo.OutboundRule{
+		{Protocol: "icmp", Destinations: &godo.Destinations{Addresses: []string{"0.0.0.0/0"}}},
+	}
+
+	var buf bytes.Buffer
+	changed := writeFirewallRulesDiff(&buf, live, inbound, outbound)
+	assert.True(t, changed)
+	assert.Equal(t, `Rule changes for firewall web (ab06e011-6dd1-4034-9293-201f71aba299):
+  Inbound rules:
+      protocol:tcp,ports:all,tag:a,tag:b
+    - protocol:tcp,ports:22,address:0.0.0.0/0
+    + protocol:tcp,ports:443,address:0.0.0.0/0
+  Outbound rules:
+      protocol:icmp,address:0.0.0.0/0
+`, buf.String())
+
+	buf.Reset()
+	changed = writeFirewallRulesDiff(&buf, live, live.InboundRules, live.OutboundRules)
+	assert.False(t, changed)
+	assert.Contains(t, buf.String(), "No rule changes.")
+}
+
+func TestParseFirewallTrafficPort(t *testing.T) {
+	tests := []struct {
+		in       string
+		protocol string
+		port     int
+		err      bool
+	}{
+		{in: "443/tcp", protocol: "tcp", port: 443},
+		{in: "53/UDP", protocol: "udp", port: 53},
+		{in: "22", protocol: "tcp", port: 22},
+		{in: "icmp", protocol: "icmp"},
+		{in: "70000/tcp", err: true},
+		{in: "443/sctp", err: true},
+		{in: "https", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			protocol, port, err := parseFirewallTrafficPort(tt.in)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.protocol, protocol)
+			assert.Equal(t, tt.port, port)
+		})
+	}
+}
+
+func TestCheckFirewallTraffic(t *testing.T) {
+	firewalls := do.Firewalls{
+		{Firewall: &godo.Firewall{
+			ID:   "fw-1",
+			Name: "web",
+			InboundRules: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: []string{"10.0.0.0/8"}}},
+				{Protocol: "tcp", PortRange: "8000-9000", Sources: &godo.Sources{Tags: []string{"frontend"}}},
+				{Protocol: "udp", PortRange: "0", Sources: &godo.Sources{DropletIDs: []int{2}}},
+			},
+			OutboundRules: []godo.OutboundRule{
+				{Protocol: "icmp", Destinations: &godo.Destinations{Addresses: []string{"0.0.0.0/0"}}},
+			},
+		}},
+	}
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		frontend := do.Droplet{Droplet: &godo.Droplet{ID: 3, Networks: &godo.Networks{
+			V4: []godo.NetworkV4{{IPAddress: "192.0.2.10", Type: "public"}},
+		}}}
+		tm.droplets.EXPECT().ListByTag("frontend").Return(do.Droplets{frontend}, nil)
+		tm.droplets.EXPECT().Get(2).Return(&testDroplet, nil)
+
+		r := newFirewallPeerResolver(config.Droplets(), config.LoadBalancers())
+
+		results := checkFirewallTraffic(firewalls, firewallTraffic{inbound: true, protocol: "tcp", port: 22, peer: net.ParseIP("10.1.2.3")}, r)
+		assert.Equal(t, []displayers.FirewallCheckResult{{
+			Direction:    "inbound",
+			Protocol:     "tcp",
+			Port:         22,
+			Peer:         "10.1.2.3",
+			Allowed:      true,
+			FirewallID:   "fw-1",
+			FirewallName: "web",
+			Rule:         "protocol:tcp,ports:22,address:10.0.0.0/8",
+		}}, results)
+
+		results = checkFirewallTraffic(firewalls, firewallTraffic{inbound: true, protocol: "tcp", port: 8080, peer: net.ParseIP("192.0.2.10")}, r)
+		assert.Len(t, results, 1)
+		assert.True(t, results[0].Allowed)
+		assert.Equal(t, "protocol:tcp,ports:8000-9000,tag:frontend", results[0].Rule)
+
+		results = checkFirewallTraffic(firewalls, firewallTraffic{inbound: true, protocol: "udp", port: 53, peer: net.ParseIP("8.8.8.8")}, r)
+		assert.Len(t, results, 1)
+		assert.True(t, results[0].Allowed)
+
+		results = checkFirewallTraffic(firewalls, firewallTraffic{inbound: true, protocol: "tcp", port: 22, peer: net.ParseIP("192.0.2.10")}, r)
+		assert.Len(t, results, 1)
+		assert.False(t, results[0].Allowed)
+		assert.Equal(t, "no rule in the 1 applied firewall(s) matches", results[0].Rule)
+
+		results = checkFirewallTraffic(firewalls, firewallTraffic{protocol: "icmp", peer: net.ParseIP("1.1.1.1")}, r)
+		assert.Len(t, results, 1)
+		assert.True(t, results[0].Allowed)
+		assert.Equal(t, "outbound", results[0].Direction)
+
+		results = checkFirewallTraffic(nil, firewallTraffic{inbound: true, protocol: "tcp", port: 22, peer: net.ParseIP("1.1.1.1")}, r)
+		assert.True(t, results[0].Allowed)
+	})
+}
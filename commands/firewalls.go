@@ -16,6 +16,8 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"os"
 	"strconv"
 	"strings"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/digitalocean/doctl/commands/displayers"
 	"github.com/digitalocean/doctl/do"
 	"github.com/digitalocean/godo"
+	"github.com/fatih/color"
 
 	"github.com/spf13/cobra"
 )
@@ -59,22 +62,47 @@ Inbound access rules specify the protocol (TCP, UDP, or ICMP), ports, and source
 	outboundRulesTxt := "A comma-separate key-value list the defines an outbound rule, e.g.: `protocol:tcp,ports:22,address:0.0.0.0/0`. Use a quoted string of space-separated values for multiple rules."
 	dropletIDRulesTxt := "A comma-separated list of Droplet IDs to place behind the cloud firewall, e.g.: `123,456`"
 	tagNameRulesTxt := "A comma-separated list of tag names to apply to the cloud firewall, e.g.: `frontend,backend`"
+	rulesFileTxt := "Path to a YAML or JSON file containing the firewall's `inbound_rules` and `outbound_rules`; use `-` to read from stdin. Cannot be combined with `--inbound-rules` or `--outbound-rules`"
+	rulesFileDetail := `
+
+Instead of the ` + "`" + `--inbound-rules` + "`" + ` and ` + "`" + `--outbound-rules` + "`" + ` flags, the rules can be read from a YAML or JSON file using the ` + "`" + `--rules-file` + "`" + ` flag. The file uses the same structure as the firewall's ` + "`" + `inbound_rules` + "`" + ` and ` + "`" + `outbound_rules` + "`" + ` attributes, for example:
+
+    inbound_rules:
+      - protocol: tcp
+        ports: 22
+        sources:
+          addresses: ["0.0.0.0/0", "::/0"]
+      - protocol: tcp
+        ports: 8000-9000
+        sources:
+          tags: [frontend]
+    outbound_rules:
+      - protocol: icmp
+        destinations:
+          addresses: ["0.0.0.0/0"]
+
+The rules are validated locally before any request is made.`
 
 	CmdBuilder(cmd, RunFirewallGet, "get <id>", "Retrieve information about a cloud firewall", `Use this command to get information about an existing cloud firewall, including:`+fwDetail, Writer, aliasOpt("g"), displayerType(&displayers.Firewall{}))
 
-	cmdFirewallCreate := CmdBuilder(cmd, RunFirewallCreate, "create", "Create a new cloud firewall", `Use this command to create a cloud firewall. This command must contain at least one inbound or outbound access rule.`, Writer, aliasOpt("c"), displayerType(&displayers.Firewall{}))
+	cmdFirewallCreate := CmdBuilder(cmd, RunFirewallCreate, "create", "Create a new cloud firewall", `Use this command to create a cloud firewall. This command must contain at least one inbound or outbound access rule.`+rulesFileDetail, Writer, aliasOpt("c"), displayerType(&displayers.Firewall{}))
 	AddStringFlag(cmdFirewallCreate, doctl.ArgFirewallName, "", "", "Firewall name", requiredOpt())
 	AddStringFlag(cmdFirewallCreate, doctl.ArgInboundRules, "", "", inboundRulesTxt)
 	AddStringFlag(cmdFirewallCreate, doctl.ArgOutboundRules, "", "", outboundRulesTxt)
 	AddStringSliceFlag(cmdFirewallCreate, doctl.ArgDropletIDs, "", []string{}, dropletIDRulesTxt)
 	AddStringSliceFlag(cmdFirewallCreate, doctl.ArgTagNames, "", []string{}, tagNameRulesTxt)
+	AddStringFlag(cmdFirewallCreate, doctl.ArgFirewallRulesFile, "", "", rulesFileTxt)
 
-	cmdFirewallUpdate := CmdBuilder(cmd, RunFirewallUpdate, "update <id>", "Update a cloud firewall's configuration", `Use this command to update the configuration of an existing cloud firewall. The request should contain a full representation of the Firewall, including existing attributes. Note: Any attributes that are not provided will be reset to their default values.`, Writer, aliasOpt("u"), displayerType(&displayers.Firewall{}))
+	cmdFirewallUpdate := CmdBuilder(cmd, RunFirewallUpdate, "update <id>", "Update a cloud firewall's configuration", `Use this command to update the configuration of an existing cloud firewall. The request should contain a full representation of the Firewall, including existing attributes. Note: Any attributes that are not provided will be reset to their default values.`+rulesFileDetail+`
+
+When `+"`"+`--rules-file`+"`"+` is used, the changes to the firewall's rules are shown before they are applied, and the Droplet IDs and tags of the firewall are kept unless `+"`"+`--droplet-ids`+"`"+` or `+"`"+`--tag-names`+"`"+` is passed. Use the `+"`"+`--prompt`+"`"+` flag to confirm the changes before they are applied.`, Writer, aliasOpt("u"), displayerType(&displayers.Firewall{}))
 	AddStringFlag(cmdFirewallUpdate, doctl.ArgFirewallName, "", "", "Firewall name", requiredOpt())
 	AddStringFlag(cmdFirewallUpdate, doctl.ArgInboundRules, "", "", inboundRulesTxt)
 	AddStringFlag(cmdFirewallUpdate, doctl.ArgOutboundRules, "", "", outboundRulesTxt)
 	AddStringSliceFlag(cmdFirewallUpdate, doctl.ArgDropletIDs, "", []string{}, dropletIDRulesTxt)
 	AddStringSliceFlag(cmdFirewallUpdate, doctl.ArgTagNames, "", []string{}, tagNameRulesTxt)
+	AddStringFlag(cmdFirewallUpdate, doctl.ArgFirewallRulesFile, "", "", rulesFileTxt)
+	AddBoolFlag(cmdFirewallUpdate, doctl.ArgFirewallPrompt, "", false, "Confirm the changes from `--rules-file` before applying them")
 
 	CmdBuilder(cmd, RunFirewallList, "list", "List the cloud firewalls on your account", `Use this command to retrieve a list of cloud firewalls.`, Writer, aliasOpt("ls"), displayerType(&displayers.Firewall{}))
 
@@ -103,6 +131,16 @@ Inbound access rules specify the protocol (TCP, UDP, or ICMP), ports, and source
 	AddStringFlag(cmdRemoveRules, doctl.ArgInboundRules, "", "", inboundRulesTxt)
 	AddStringFlag(cmdRemoveRules, doctl.ArgOutboundRules, "", "", outboundRulesTxt)
 
+	cmdCheck := CmdBuilder(cmd, RunFirewallCheck, "check", "Check whether traffic to or from a Droplet is allowed", `Use this command to check whether the cloud firewalls applied to a Droplet allow a connection.
+
+All firewalls applied to the Droplet, either directly or through one of its tags, are evaluated locally. Use `+"`"+`--from`+"`"+` to check inbound traffic from an address or `+"`"+`--to`+"`"+` to check outbound traffic to an address. Each matching rule and the firewall it belongs to is reported; if no rule matches, the traffic is denied.
+
+Rules that reference Droplets, tags, load balancers or Kubernetes clusters are matched against their current IP addresses. Cloud firewalls are stateful, so responses to allowed connections are always permitted.`, Writer, displayerType(&displayers.FirewallCheck{}))
+	AddStringFlag(cmdCheck, doctl.ArgDroplet, "", "", "The ID or name of the Droplet to check", requiredOpt())
+	AddStringFlag(cmdCheck, doctl.ArgFirewallCheckFrom, "", "", "The source IP address of inbound traffic to check")
+	AddStringFlag(cmdCheck, doctl.ArgFirewallCheckTo, "", "", "The destination IP address of outbound traffic to check")
+	AddStringFlag(cmdCheck, doctl.ArgFirewallCheckPort, "", "", "The port and protocol to check, e.g. `443/tcp`, `53/udp` or `icmp`", requiredOpt())
+
 	return cmd
 }
 
@@ -153,7 +191,38 @@ func RunFirewallUpdate(c *CmdConfig) error {
 		return err
 	}
 
+	rulesFile, err := c.Doit.GetString(c.NS, doctl.ArgFirewallRulesFile)
+	if err != nil {
+		return err
+	}
+
 	fs := c.Firewalls()
+	if rulesFile != "" {
+		prompt, err := c.Doit.GetBool(c.NS, doctl.ArgFirewallPrompt)
+		if err != nil {
+			return err
+		}
+
+		live, err := fs.Get(fID)
+		if err != nil {
+			return err
+		}
+
+		// the update replaces the whole firewall, so keep the Droplets and
+		// tags that were not given on the command line
+		if !c.Doit.IsSet(doctl.ArgDropletIDs) {
+			r.DropletIDs = live.DropletIDs
+		}
+		if !c.Doit.IsSet(doctl.ArgTagNames) {
+			r.Tags = live.Tags
+		}
+
+		changed := writeFirewallRulesDiff(color.Output, live, r.InboundRules, r.OutboundRules)
+		if changed && prompt && AskForConfirm("apply these changes to the firewall?") != nil {
+			return errOperationAborted
+		}
+	}
+
 	f, err := fs.Update(fID, r)
 	if err != nil {
 		return err
@@ -339,22 +408,39 @@ func buildFirewallRequestFromArgs(c *CmdConfig, r *godo.FirewallRequest) error {
 		return err
 	}
 
-	inboundRules, err := extractInboundRules(ira)
+	ora, err := c.Doit.GetString(c.NS, doctl.ArgOutboundRules)
 	if err != nil {
 		return err
 	}
-	r.InboundRules = inboundRules
 
-	ora, err := c.Doit.GetString(c.NS, doctl.ArgOutboundRules)
+	rulesFile, err := c.Doit.GetString(c.NS, doctl.ArgFirewallRulesFile)
 	if err != nil {
 		return err
 	}
 
-	outboundRules, err := extractOutboundRules(ora)
-	if err != nil {
-		return err
+	if rulesFile != "" {
+		if ira != "" || ora != "" {
+			return fmt.Errorf("The `--%s` flag cannot be combined with `--%s` or `--%s`", doctl.ArgFirewallRulesFile, doctl.ArgInboundRules, doctl.ArgOutboundRules)
+		}
+
+		rules, err := readFirewallRulesFile(os.Stdin, rulesFile)
+		if err != nil {
+			return err
+		}
+		r.InboundRules, r.OutboundRules = rules.godoRules()
+	} else {
+		inboundRules, err := extractInboundRules(ira)
+		if err != nil {
+			return err
+		}
+		r.InboundRules = inboundRules
+
+		outboundRules, err := extractOutboundRules(ora)
+		if err != nil {
+			return err
+		}
+		r.OutboundRules = outboundRules
 	}
-	r.OutboundRules = outboundRules
 
 	dropletIDsList, err := c.Doit.GetStringSlice(c.NS, doctl.ArgDropletIDs)
 	if err != nil {
@@ -490,3 +576,99 @@ func extractRule(ruleStr string, sd string) (map[string]interface{}, error) {
 
 	return rule, nil
 }
+
+// RunFirewallCheck checks traffic against the firewalls applied to a Droplet.
+func RunFirewallCheck(c *CmdConfig) error {
+	dropletIDOrName, err := c.Doit.GetString(c.NS, doctl.ArgDroplet)
+	if err != nil {
+		return err
+	}
+
+	from, err := c.Doit.GetString(c.NS, doctl.ArgFirewallCheckFrom)
+	if err != nil {
+		return err
+	}
+
+	to, err := c.Doit.GetString(c.NS, doctl.ArgFirewallCheckTo)
+	if err != nil {
+		return err
+	}
+
+	portStr, err := c.Doit.GetString(c.NS, doctl.ArgFirewallCheckPort)
+	if err != nil {
+		return err
+	}
+
+	if (from == "") == (to == "") {
+		return fmt.Errorf("Exactly one of `--%s` or `--%s` must be provided", doctl.ArgFirewallCheckFrom, doctl.ArgFirewallCheckTo)
+	}
+
+	t := firewallTraffic{inbound: from != ""}
+	peer := from
+	if !t.inbound {
+		peer = to
+	}
+	t.peer = net.ParseIP(peer)
+	if t.peer == nil {
+		return fmt.Errorf("invalid IP address %q", peer)
+	}
+
+	t.protocol, t.port, err = parseFirewallTrafficPort(portStr)
+	if err != nil {
+		return err
+	}
+
+	droplet, err := dropletByIDOrName(c.Droplets(), dropletIDOrName)
+	if err != nil {
+		return err
+	}
+
+	firewalls, err := firewallsForDroplet(c.Firewalls(), droplet)
+	if err != nil {
+		return err
+	}
+
+	resolver := newFirewallPeerResolver(c.Droplets(), c.LoadBalancers())
+	item := &displayers.FirewallCheck{Results: checkFirewallTraffic(firewalls, t, resolver)}
+	return c.Display(item)
+}
+
+// firewallsForDroplet returns the firewalls applied to a Droplet, either
+// directly or through any of its tags.
+func firewallsForDroplet(fs do.FirewallsService, droplet *do.Droplet) (do.Firewalls, error) {
+	direct, err := fs.ListByDroplet(droplet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var firewalls do.Firewalls
+	for _, fw := range direct {
+		seen[fw.ID] = true
+		firewalls = append(firewalls, fw)
+	}
+
+	if len(droplet.Tags) == 0 {
+		return firewalls, nil
+	}
+
+	all, err := fs.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fw := range all {
+		if seen[fw.ID] {
+			continue
+		}
+		for _, tag := range fw.Tags {
+			if contains(droplet.Tags, tag) {
+				seen[fw.ID] = true
+				firewalls = append(firewalls, fw)
+				break
+			}
+		}
+	}
+
+	return firewalls, nil
+}
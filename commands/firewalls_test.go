@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -26,7 +29,7 @@ var (
 func TestFirewallCommand(t *testing.T) {
 	cmd := Firewall()
 	assert.NotNil(t, cmd)
-	assertCommandNames(t, cmd, "get", "create", "update", "list", "list-by-droplet", "delete", "add-droplets", "remove-droplets", "add-tags", "remove-tags", "add-rules", "remove-rules")
+	assertCommandNames(t, cmd, "get", "create", "update", "list", "list-by-droplet", "delete", "add-droplets", "remove-droplets", "add-tags", "remove-tags", "add-rules", "remove-rules", "check")
 }
 
 func TestFirewallGet(t *testing.T) {
@@ -121,6 +124,129 @@ func TestFirewallUpdate(t *testing.T) {
 	})
 }
 
+func TestFirewallCreateFromRulesFile(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		rulesFile := filepath.Join(t.TempDir(), "fw.yaml")
+		err := os.WriteFile(rulesFile, []byte(`inbound_rules:
+  - protocol: tcp
+    ports: 22
+    sources:
+      addresses: ["0.0.0.0/0"]
+outbound_rules:
+  - protocol: icmp
+    destinations:
+      tags: [web]
+`), 0600)
+		assert.NoError(t, err)
+
+		firewallCreateRequest := &godo.FirewallRequest{
+			Name: "firewall",
+			InboundRules: []godo.InboundRule{
+				{
+					Protocol:  "tcp",
+					PortRange: "22",
+					Sources:   &godo.Sources{Addresses: []string{"0.0.0.0/0"}},
+				},
+			},
+			OutboundRules: []godo.OutboundRule{
+				{
+					Protocol:     "icmp",
+					Destinations: &godo.Destinations{Tags: []string{"web"}},
+				},
+			},
+			DropletIDs: []int{},
+		}
+		tm.firewalls.EXPECT().Create(firewallCreateRequest).Return(&testFirewall, nil)
+
+		config.Doit.Set(config.NS, doctl.ArgFirewallName, "firewall")
+		config.Doit.Set(config.NS, doctl.ArgFirewallRulesFile, rulesFile)
+
+		err = RunFirewallCreate(config)
+		assert.NoError(t, err)
+	})
+}
+
+func TestFirewallCreateRulesFileConflict(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Doit.Set(config.NS, doctl.ArgFirewallName, "firewall")
+		config.Doit.Set(config.NS, doctl.ArgFirewallRulesFile, "fw.yaml")
+		config.Doit.Set(config.NS, doctl.ArgInboundRules, "protocol:icmp,address:0.0.0.0/0")
+
+		err := RunFirewallCreate(config)
+		assert.Error(t, err)
+	})
+}
+
+func TestFirewallUpdateFromRulesFile(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		fID := "ab06e011-6dd1-4034-9293-201f71aba299"
+		rulesFile := filepath.Join(t.TempDir(), "fw.json")
+		err := os.WriteFile(rulesFile, []byte(`{"inbound_rules": [{"protocol": "tcp", "ports": "443", "sources": {"addresses": ["0.0.0.0/0"]}}]}`), 0600)
+		assert.NoError(t, err)
+
+		live := &do.Firewall{Firewall: &godo.Firewall{
+			ID:   fID,
+			Name: "firewall",
+			InboundRules: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "80", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+			},
+			DropletIDs: []int{1, 2},
+			Tags:       []string{"web"},
+		}}
+		firewallUpdateRequest := &godo.FirewallRequest{
+			Name: "firewall",
+			InboundRules: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+			},
+			OutboundRules: []godo.OutboundRule{},
+			DropletIDs:    []int{1, 2},
+			Tags:          []string{"web"},
+		}
+		tm.firewalls.EXPECT().Get(fID).Return(live, nil)
+		tm.firewalls.EXPECT().Update(fID, firewallUpdateRequest).Return(&testFirewall, nil)
+
+		config.Args = append(config.Args, fID)
+		config.Doit.Set(config.NS, doctl.ArgFirewallName, "firewall")
+		config.Doit.Set(config.NS, doctl.ArgFirewallRulesFile, rulesFile)
+
+		err = RunFirewallUpdate(config)
+		assert.NoError(t, err)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		fID := "ab06e011-6dd1-4034-9293-201f71aba299"
+		rulesFile := filepath.Join(t.TempDir(), "fw.json")
+		err := os.WriteFile(rulesFile, []byte(`{"inbound_rules": [{"protocol": "tcp", "ports": "443", "sources": {"addresses": ["0.0.0.0/0"]}}]}`), 0600)
+		assert.NoError(t, err)
+
+		live := &do.Firewall{Firewall: &godo.Firewall{
+			ID:         fID,
+			Name:       "firewall",
+			DropletIDs: []int{1, 2},
+			Tags:       []string{"web"},
+		}}
+		firewallUpdateRequest := &godo.FirewallRequest{
+			Name: "firewall",
+			InboundRules: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+			},
+			OutboundRules: []godo.OutboundRule{},
+			DropletIDs:    []int{3},
+			Tags:          []string{"web"},
+		}
+		tm.firewalls.EXPECT().Get(fID).Return(live, nil)
+		tm.firewalls.EXPECT().Update(fID, firewallUpdateRequest).Return(&testFirewall, nil)
+
+		config.Args = append(config.Args, fID)
+		config.Doit.Set(config.NS, doctl.ArgFirewallName, "firewall")
+		config.Doit.Set(config.NS, doctl.ArgFirewallRulesFile, rulesFile)
+		config.Doit.Set(config.NS, doctl.ArgDropletIDs, []string{"3"})
+
+		err = RunFirewallUpdate(config)
+		assert.NoError(t, err)
+	})
+}
+
 func TestFirewallList(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		tm.firewalls.EXPECT().List().Return(testFirewallList, nil)
@@ -294,3 +420,88 @@ func TestFirewallRemoveRules(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestFirewallCheck(t *testing.T) {
+	droplet := do.Droplet{Droplet: &godo.Droplet{ID: 1, Name: "web-1", Tags: []string{"web"}}}
+	direct := do.Firewall{Firewall: &godo.Firewall{
+		ID:   "fw-direct",
+		Name: "ssh",
+		InboundRules: []godo.InboundRule{
+			{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}},
+		},
+		DropletIDs: []int{1},
+	}}
+	tagged := do.Firewall{Firewall: &godo.Firewall{
+		ID:   "fw-tagged",
+		Name: "https",
+		InboundRules: []godo.InboundRule{
+			{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"203.0.113.0/24"}}},
+		},
+		Tags: []string{"web"},
+	}}
+	unrelated := do.Firewall{Firewall: &godo.Firewall{ID: "fw-other", Tags: []string{"db"}}}
+
+	tests := []struct {
+		name string
+		from string
+		port string
+		want string
+	}{
+		{
+			name: "allowed by a tagged firewall",
+			from: "203.0.113.5",
+			port: "443/tcp",
+			want: `Result    Direction    Protocol    Port    Peer           Firewall ID    Firewall Name    Rule
+allow     inbound      tcp         443     203.0.113.5    fw-tagged      https            protocol:tcp,ports:443,address:203.0.113.0/24
+`,
+		},
+		{
+			name: "allowed by a Droplet firewall",
+			from: "198.51.100.7",
+			port: "22/tcp",
+			want: `Result    Direction    Protocol    Port    Peer            Firewall ID    Firewall Name    Rule
+allow     inbound      tcp         22      198.51.100.7    fw-direct      ssh              protocol:tcp,ports:22,address:0.0.0.0/0
+`,
+		},
+		{
+			name: "denied",
+			from: "198.51.100.7",
+			port: "443/tcp",
+			want: `Result    Direction    Protocol    Port    Peer            Firewall ID    Firewall Name    Rule
+deny      inbound      tcp         443     198.51.100.7                                    no rule in the 2 applied firewall(s) matches
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+				tm.droplets.EXPECT().List().Return(do.Droplets{droplet}, nil)
+				tm.firewalls.EXPECT().ListByDroplet(1).Return(do.Firewalls{direct}, nil)
+				tm.firewalls.EXPECT().List().Return(do.Firewalls{direct, tagged, unrelated}, nil)
+
+				config.Doit.Set(config.NS, doctl.ArgDroplet, "web-1")
+				config.Doit.Set(config.NS, doctl.ArgFirewallCheckFrom, tt.from)
+				config.Doit.Set(config.NS, doctl.ArgFirewallCheckPort, tt.port)
+				var buf bytes.Buffer
+				config.Out = &buf
+
+				err := RunFirewallCheck(config)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, buf.String())
+			})
+		})
+	}
+}
+
+func TestFirewallCheckRequiresOneDirection(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Doit.Set(config.NS, doctl.ArgDroplet, "web-1")
+		config.Doit.Set(config.NS, doctl.ArgFirewallCheckFrom, "203.0.113.5")
+		config.Doit.Set(config.NS, doctl.ArgFirewallCheckTo, "203.0.113.6")
+		config.Doit.Set(config.NS, doctl.ArgFirewallCheckPort, "443/tcp")
+
+		err := RunFirewallCheck(config)
+		assert.Error(t, err)
+	})
+}
@@ -14,10 +14,13 @@ limitations under the License.
 package commands
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -30,15 +33,21 @@ import (
 	"github.com/digitalocean/doctl/commands/displayers"
 	"github.com/digitalocean/doctl/do"
 	"github.com/digitalocean/godo"
+	"github.com/fatih/color"
 	"github.com/google/uuid"
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
 	clientauthentication "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -237,7 +246,7 @@ func kubernetesCluster() *Command {
 - The slug identifier for the version of Kubernetes used for the cluster. If set to a minor version (e.g. ` + "`" + `1.14` + "`" + `), the latest version within it will be used (e.g. ` + "`" + `1.14.6-do.1` + "`" + `); if set to ` + "`" + `latest` + "`" + `, the latest published version will be used.
 - A boolean value indicating whether the cluster will be automatically upgraded to new patch releases during its maintenance window.
 - An object containing a "state" attribute whose value is set to a string indicating the current status of the node. Potential values include ` + "`" + `running` + "`" + `, ` + "`" + `provisioning` + "`" + `, and ` + "`" + `errored` + "`" + `.`
-	CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterGet, "get <id|name>", "Retrieve details about a Kubernetes cluster", `
+	cmdKubeClusterGet := CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterGet, "get <id|name>", "Retrieve details about a Kubernetes cluster", `
 This command retrieves the following details about a Kubernetes cluster: `+clusterDetails+`
 - The base URL of the cluster's Kubernetes API server.
 - The public IPv4 address of the cluster's Kubernetes API server.
@@ -246,8 +255,12 @@ This command retrieves the following details about a Kubernetes cluster: `+clust
 - An array of tags applied to the Kubernetes cluster. All clusters are automatically tagged `+"`"+`k8s`+"`"+` and `+"`"+`k8s:$K8S_CLUSTER_ID`+"`"+`.
 - A time value given in ISO8601 combined date and time format that represents when the Kubernetes cluster was created.
 - A time value given in ISO8601 combined date and time format that represents when the Kubernetes cluster was last updated.
-`+nodePoolDetails,
+`+nodePoolDetails+`
+
+Use the `+"`"+`--spec`+"`"+` flag to output the cluster's configuration as a spec that can be used with `+"`"+`doctl kubernetes cluster create --spec`+"`"+`.`,
 		Writer, aliasOpt("g"), displayerType(&displayers.KubernetesClusters{}))
+	AddBoolFlag(cmdKubeClusterGet, doctl.ArgClusterSpec, "", false,
+		"Output the cluster's configuration as a YAML spec, or JSON with `--output json`")
 	CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterList, "list", "Retrieve the list of Kubernetes clusters for your account", `
 This command retrieves the following details about all Kubernetes clusters that are on your account:`+clusterDetails+nodePoolDetails,
 		Writer, aliasOpt("ls"), displayerType(&displayers.KubernetesClusters{}))
@@ -257,13 +270,40 @@ This command returns a list of slugs representing Kubernetes versions you can us
 `, Writer, aliasOpt("gu"))
 
 	cmdKubeClusterCreate := CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterCreate(defaultKubernetesNodeSize,
-		defaultKubernetesNodeCount), "create <name>", "Create a Kubernetes cluster", `
+		defaultKubernetesNodeCount), "create [<name>]", "Create a Kubernetes cluster", `
 Creates a Kubernetes cluster given the specified options, using the specified name. Before creating the cluster, you can use `+"`"+`doctl kubernetes options`+"`"+` to see possible values for the various configuration flags.
 
 If no configuration flags are used, a three-node cluster with a single node pool will be created in the nyc1 region, using the latest Kubernetes version.
 
-After creating a cluster, a configuration context will be added to kubectl and made active so that you can begin managing your new cluster immediately.`,
+After creating a cluster, a configuration context will be added to kubectl and made active so that you can begin managing your new cluster immediately.
+
+Instead of flags, the cluster's configuration can be provided as a YAML or JSON spec using the `+"`"+`--spec`+"`"+` flag. The spec uses the same fields as the cluster create API request, plus `+"`"+`one_click_apps`+"`"+` for the 1-Click Applications to install, for example:
+
+    name: example-cluster
+    region: nyc1
+    version: latest
+    vpc_uuid: 5a4981aa-9653-4bd1-bef5-d6bff52042e4
+    tags: [production]
+    ha: true
+    auto_upgrade: true
+    surge_upgrade: true
+    maintenance_policy:
+      day: sunday
+      start_time: "04:00"
+    node_pools:
+      - name: workers
+        size: s-4vcpu-8gb
+        auto_scale: true
+        min_nodes: 2
+        max_nodes: 6
+        labels:
+          role: worker
+    one_click_apps: [monitoring]
+
+A name given as an argument overrides the name in the spec. Use `+"`"+`doctl kubernetes cluster get <id|name> --spec`+"`"+` to export the spec of an existing cluster.`,
 		Writer, aliasOpt("c"))
+	AddStringFlag(cmdKubeClusterCreate, doctl.ArgClusterSpec, "", "",
+		"Path to a YAML or JSON cluster spec; use `-` to read from stdin. When used, the other cluster configuration flags are ignored")
 	AddStringFlag(cmdKubeClusterCreate, doctl.ArgRegionSlug, "", defaultKubernetesRegion,
 		"Cluster region. Possible values: see `doctl kubernetes options regions`", requiredOpt())
 	AddStringFlag(cmdKubeClusterCreate, doctl.ArgClusterVersionSlug, "", "latest",
@@ -333,11 +373,17 @@ This command updates the specified configuration values for the specified Kubern
 	cmdKubeClusterUpgrade := CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterUpgrade,
 		"upgrade <id|name>", "Upgrades a cluster to a new Kubernetes version", `
 
-This command upgrades the specified Kubernetes cluster. By default, this will upgrade the cluster to the latest available release, but you can also specify any version listed for your cluster by using `+"`"+`doctl k8s get-upgrades`+"`"+`.`, Writer)
+This command upgrades the specified Kubernetes cluster. By default, this will upgrade the cluster to the latest available release, but you can also specify any version listed for your cluster by using `+"`"+`doctl k8s get-upgrades`+"`"+`.
+
+With the `+"`"+`--guided`+"`"+` flag, the command first shows the available upgrade path and runs preflight checks: it runs clusterlint, warns about any use of deprecated APIs, and checks whether surge upgrades are enabled. After confirmation, it triggers the upgrade and waits until every node that existed before the upgrade has been replaced and the cluster is running the new version, then prints a report.`, Writer)
 	AddStringFlag(cmdKubeClusterUpgrade, doctl.ArgClusterVersionSlug, "", "latest",
 		`The desired Kubernetes version. Possible values: see `+"`"+`doctl k8s get-upgrades <cluster>`+"`"+`.
 The special value `+"`"+`latest`+"`"+` will select the most recent patch version for your cluster's minor version.
 For example, if a cluster is on 1.12.1 and upgrades are available to 1.12.3 and 1.13.1, 1.12.3 will be `+"`"+`latest`+"`"+`.`)
+	AddBoolFlag(cmdKubeClusterUpgrade, doctl.ArgClusterUpgradeGuided, "", false,
+		"Show the upgrade path, run preflight checks, and wait for every node to be replaced before printing a report")
+	AddBoolFlag(cmdKubeClusterUpgrade, doctl.ArgForce, doctl.ArgShortForce, false,
+		"With `--guided`, upgrade without a confirmation prompt, even if clusterlint reports errors")
 
 	cmdKubeClusterDelete := CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterDelete,
 		"delete <id|name>...", "Delete Kubernetes clusters ", `
@@ -372,6 +418,55 @@ This command retrieves the following details:
 - Load balancer IDs for load balancers managed by the Kubernetes cluster.`,
 		Writer, aliasOpt("ar"), displayerType(&displayers.KubernetesAssociatedResources{}))
 
+	CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterReport, "report <id|name>",
+		"Estimate the monthly cost and capacity of a Kubernetes cluster", `
+This command estimates the monthly cost of the specified Kubernetes cluster. The report lists:
+
+- Each node pool, with its node size, node count, capacity and cost. For node pools that autoscale, the minimum and maximum number of nodes and the cost range are also shown.
+- The high-availability control plane, if it is enabled.
+- The load balancers and volumes associated with the cluster.
+
+Node prices come from the DigitalOcean API. Load balancer, volume and control plane prices are list prices in USD. The estimate does not include bandwidth, volume snapshots or taxes.`,
+		Writer, displayerType(&displayers.KubernetesCost{}))
+
+	cmdKubeClusterPortForward := CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterPortForward,
+		"port-forward <id|name> <resource> <[local-port:]remote-port>...", "Forward local ports to a pod, service or deployment in a Kubernetes cluster", `
+This command forwards one or more local ports to a pod in the specified Kubernetes cluster, using the cluster's credentials from the DigitalOcean API. It does not need kubectl or a kubeconfig.
+
+The resource can be a pod (`+"`"+`pod/<name>`+"`"+` or just `+"`"+`<name>`+"`"+`), a service (`+"`"+`svc/<name>`+"`"+`) or a deployment (`+"`"+`deploy/<name>`+"`"+`). For services and deployments, a running pod that backs them is chosen. The remote port of a service is one of the service's ports, and is translated to the port of the pod it targets.
+
+Ports are given as `+"`"+`<local-port>:<remote-port>`+"`"+`, `+"`"+`<port>`+"`"+` to use the same port locally, or `+"`"+`:<remote-port>`+"`"+` to pick a random local port. Forwarding runs until interrupted with Ctrl+C.
+
+For example, to reach a dashboard service on http://localhost:8080:
+
+	doctl kubernetes cluster port-forward example-cluster svc/dashboard 8080:80 -n monitoring`, Writer, aliasOpt("pf"))
+	AddStringFlag(cmdKubeClusterPortForward, doctl.ArgObjectNamespace, "n", metav1.NamespaceDefault, "Namespace of the resource")
+	AddStringSliceFlag(cmdKubeClusterPortForward, doctl.ArgPortForwardAddress, "", []string{"localhost"},
+		"Local addresses to listen on; use a comma-separated list to listen on several addresses")
+
+	cmdKubeClusterLint := CmdBuilder(cmd, k8sCmdService.RunKubernetesClusterLint, "lint <id|name>",
+		"Run clusterlint diagnostics against a Kubernetes cluster", `
+This command runs clusterlint against the specified Kubernetes cluster. Clusterlint checks the resources in the cluster for common problems, such as the use of deprecated APIs, that may cause issues during upgrades or maintenance.
+
+By default, the run is started in the background and its ID is printed. Use the `+"`"+`--wait`+"`"+` flag to wait for it to finish and show its diagnostics, or the `+"`"+`--run-id`+"`"+` flag to fetch the diagnostics of an earlier run.
+
+Diagnostics are grouped by severity, object and check. The command exits with a non-zero status when any diagnostic has a severity of `+"`"+`error`+"`"+`, so it can be used as a gate before upgrading a cluster.
+
+For the list of checks and groups, see https://github.com/digitalocean/clusterlint.`,
+		Writer, displayerType(&displayers.KubernetesClusterlintDiagnostics{}))
+	AddStringSliceFlag(cmdKubeClusterLint, doctl.ArgClusterlintIncludeGroups, "", nil,
+		"A comma-separated list of check groups to run, for example: `basic,doks`")
+	AddStringSliceFlag(cmdKubeClusterLint, doctl.ArgClusterlintExcludeGroups, "", nil,
+		"A comma-separated list of check groups to skip")
+	AddStringSliceFlag(cmdKubeClusterLint, doctl.ArgClusterlintIncludeChecks, "", nil,
+		"A comma-separated list of checks to run, for example: `unused-config-map,bare-pods`")
+	AddStringSliceFlag(cmdKubeClusterLint, doctl.ArgClusterlintExcludeChecks, "", nil,
+		"A comma-separated list of checks to skip")
+	AddBoolFlag(cmdKubeClusterLint, doctl.ArgCommandWait, "", false,
+		"Wait for the clusterlint run to finish and show its diagnostics")
+	AddStringFlag(cmdKubeClusterLint, doctl.ArgClusterlintRunID, "", "",
+		"Show the diagnostics of an earlier clusterlint run instead of starting a new one")
+
 	return cmd
 }
 
@@ -395,18 +490,34 @@ This command prints out the raw YAML for the specified cluster's kubeconfig.	`,
 	execCredDesc := "INTERNAL: This hidden command is for printing a cluster's exec credential"
 	cmdExecCredential := CmdBuilder(cmd, k8sCmdService.RunKubernetesKubeconfigExecCredential, "exec-credential <cluster-id>", execCredDesc, execCredDesc, Writer, hiddenCmd())
 	AddStringFlag(cmdExecCredential, doctl.ArgVersion, "", "", "")
+	AddStringFlag(cmdExecCredential, doctl.ArgKubeconfigCredentialCache, "", credentialCacheFile, "")
 
 	cmdSaveConfig := CmdBuilder(cmd, k8sCmdService.RunKubernetesKubeconfigSave, "save <cluster-id|cluster-name>", "Save a cluster's credentials to your local kubeconfig", `
 This command adds the credentials for the specified cluster to your local kubeconfig. After this, your kubectl installation can directly manage the specified cluster.
+
+The context is named `+"`"+`do-<region>-<cluster-name>`+"`"+` unless another name is given with `+"`"+`--context-name`+"`"+` or its equivalent `+"`"+`--alias`+"`"+`.
 		`, Writer, aliasOpt("s"))
 	AddBoolFlag(cmdSaveConfig, doctl.ArgSetCurrentContext, "", true, "Boolean indicating whether to set the current kubectl context to that of the new cluster")
 	AddIntFlag(cmdSaveConfig, doctl.ArgKubeConfigExpirySeconds, "", 0,
 		"The length of time the cluster credentials will be valid for in seconds. By default, the credentials are automatically renewed as needed.")
 	AddStringFlag(cmdSaveConfig, doctl.ArgKubernetesAlias, "", "", "An alias for the cluster context name. Defaults to 'do-<region>-<cluster-name>'.")
+	AddStringFlag(cmdSaveConfig, doctl.ArgKubeconfigContextName, "", "", "The name of the kubeconfig context for the cluster, the same as `--alias`. Defaults to 'do-<region>-<cluster-name>'.")
+	AddStringFlag(cmdSaveConfig, doctl.ArgKubeconfigNamespace, "", "", "The default namespace of the kubeconfig context")
+	AddStringFlag(cmdSaveConfig, doctl.ArgKubeconfigOutputFile, "", "",
+		"Write the cluster's credentials to a standalone kubeconfig file at this path instead of merging them into your local kubeconfig")
+	AddStringFlag(cmdSaveConfig, doctl.ArgKubeconfigCredentialCache, "", "",
+		"Where kubectl caches the credentials it gets from doctl: `file` (the default), `encrypted-file` (encrypted with a key derived from your access token), or `keyring` (the Secret Service API on Linux)")
 
 	CmdBuilder(cmd, k8sCmdService.RunKubernetesKubeconfigRemove, "remove <cluster-id|cluster-name>", "Remove a cluster's credentials from your local kubeconfig", `
 This command removes the specified cluster's credentials from your local kubeconfig. After running this command, you will not be able to use `+"`"+`kubectl`+"`"+` to interact with your cluster.
 `, Writer, aliasOpt("d", "rm"))
+
+	cmdPruneConfig := CmdBuilder(cmd, k8sCmdService.RunKubernetesKubeconfigPrune, "prune", "Remove contexts of deleted clusters from your local kubeconfig", `
+This command removes the contexts of Kubernetes clusters that no longer exist in your account from your local kubeconfig, together with their cluster and user entries and cached credentials. Contexts are matched to clusters by the cluster ID in their `+"`"+`doctl`+"`"+` credential command.
+
+Only contexts whose credential command uses the current authentication context are checked, so contexts of clusters in other teams or accounts are left alone. Switch to their authentication context with `+"`"+`--context`+"`"+` to prune them. Contexts for clusters that are not DigitalOcean Kubernetes clusters, or that do not get their credentials from `+"`"+`doctl`+"`"+`, are also left alone.
+`, Writer)
+	AddBoolFlag(cmdPruneConfig, doctl.ArgForce, doctl.ArgShortForce, false, "Remove the contexts without a confirmation prompt")
 	return cmd
 }
 
@@ -520,6 +631,46 @@ This command deletes the specified node in the specified node pool, and then cre
 	AddBoolFlag(cmdKubeNodeReplace, doctl.ArgForce, doctl.ArgShortForce, false, "Replace node without confirmation prompt")
 	AddBoolFlag(cmdKubeNodeReplace, "skip-drain", "", false, "Skip draining the node before replacement")
 
+	cmdKubeNodePoolReplaceNodes := CmdBuilder(cmd, k8sCmdService.RunKubernetesNodePoolReplaceNodes,
+		"replace-nodes <cluster-id|cluster-name> <pool-id|pool-name>", "Replace a node pool's nodes in rolling batches", `
+This command replaces the nodes of the specified node pool a batch at a time. Each node in a batch is drained and replaced with a new node, and the command waits until the old nodes are gone, the new nodes have joined the cluster and are Ready, and no pods are left waiting to be scheduled before it moves on to the next batch. Pods that were already waiting to be scheduled before a batch started are ignored.
+
+Use `+"`"+`--all`+"`"+` to replace every node in the pool, or `+"`"+`--node-ids`+"`"+` to replace specific nodes. The number of nodes replaced at a time is set with `+"`"+`--max-unavailable`+"`"+`.`, Writer)
+	AddBoolFlag(cmdKubeNodePoolReplaceNodes, doctl.ArgNodePoolReplaceAll, "", false, "Replace all of the node pool's nodes")
+	AddStringSliceFlag(cmdKubeNodePoolReplaceNodes, doctl.ArgNodePoolNodeIDs, "", nil,
+		"ID or name of a node to replace; repeat or use a comma-separated list to replace multiple nodes")
+	AddIntFlag(cmdKubeNodePoolReplaceNodes, doctl.ArgNodePoolMaxUnavailable, "", 1, "Number of nodes to replace at a time")
+	AddBoolFlag(cmdKubeNodePoolReplaceNodes, "skip-drain", "", false, "Skip draining the nodes before replacement")
+	AddDurationFlag(cmdKubeNodePoolReplaceNodes, doctl.ArgTimeout, "", 30*time.Minute,
+		"How long to wait for each batch of nodes to be replaced; 0 waits indefinitely")
+	AddBoolFlag(cmdKubeNodePoolReplaceNodes, doctl.ArgForce, doctl.ArgShortForce, false, "Replace the nodes without a confirmation prompt")
+
+	cmdKubeNodePoolApply := CmdBuilder(cmd, k8sCmdService.RunKubernetesNodePoolApply,
+		"apply <cluster-id|cluster-name>", "Reconcile a cluster's node pools with a file", `
+This command makes the node pools of the specified cluster match the ones described in a YAML or JSON file. Node pools are matched by name: pools in the file that don't exist are created, existing pools are updated, and pools that are not in the file are deleted. A plan of the changes is printed and must be confirmed before it is applied.
+
+The file lists the node pools under `+"`"+`node_pools`+"`"+`, using the same fields as the node pool API, for example:
+
+    node_pools:
+      - name: workers
+        size: s-4vcpu-8gb
+        auto_scale: true
+        min_nodes: 2
+        max_nodes: 6
+        tags: [backend]
+        labels:
+          role: worker
+        taints:
+          - key: dedicated
+            value: backend
+            effect: NoSchedule
+
+The size of an existing node pool cannot be changed. To move a pool to a different size, give the new pool a different name so that it is created before the old one is deleted.`, Writer, displayerType(&displayers.KubernetesNodePools{}))
+	AddStringFlag(cmdKubeNodePoolApply, doctl.ArgNodePoolsFile, "f", "",
+		"Path to a YAML or JSON file describing the node pools; use `-` to read from stdin", requiredOpt())
+	AddBoolFlag(cmdKubeNodePoolApply, doctl.ArgForce, "", false,
+		"Apply the changes without a confirmation prompt")
+
 	return cmd
 }
 
@@ -545,6 +696,22 @@ This command adds container registry support to the specified Kubernetes cluster
 This command removes container registry support from the specified Kubernetes cluster(s).`,
 		Writer, aliasOpt("rm"))
 
+	cmdRegistryStatus := CmdBuilder(cmd, k8sCmdService.RunKubernetesRegistryStatus,
+		"status [<cluster-id|cluster-name>...]", "Show the container registry integration status of Kubernetes clusters", `
+This command shows whether container registry support is enabled for the specified Kubernetes clusters, or for all clusters when none are specified. For each running cluster, it also lists the namespaces that have the registry's pull secret, and the namespaces whose default service account uses it.`,
+		Writer, displayerType(&displayers.KubernetesRegistryStatus{}))
+	AddStringFlag(cmdRegistryStatus, doctl.ArgObjectName, "", "",
+		"The name of the pull secret. Defaults to the registry name prefixed with \"registry-\"")
+
+	cmdRegistrySync := CmdBuilder(cmd, k8sCmdService.RunKubernetesRegistrySync,
+		"sync <cluster-id|cluster-name>", "Sync the container registry pull secret to namespaces of a Kubernetes cluster", `
+This command creates or refreshes the registry's docker-registry pull secret in each of the specified namespaces, and adds it to the image pull secrets of each namespace's default service account. Pods in those namespaces can then pull images from the registry without further configuration.`,
+		Writer)
+	AddStringSliceFlag(cmdRegistrySync, doctl.ArgRegistryNamespaces, "", nil,
+		"A comma-separated list of namespaces to sync the pull secret to", requiredOpt())
+	AddStringFlag(cmdRegistrySync, doctl.ArgObjectName, "", "",
+		"The name of the pull secret. Defaults to the registry name prefixed with \"registry-\"")
+
 	return cmd
 }
 
@@ -633,10 +800,19 @@ func (s *KubernetesCommandService) RunKubernetesClusterGet(c *CmdConfig) error {
 	}
 	clusterIDorName := c.Args[0]
 
+	spec, err := c.Doit.GetBool(c.NS, doctl.ArgClusterSpec)
+	if err != nil {
+		return err
+	}
+
 	cluster, err := clusterByIDorName(c.Kubernetes(), clusterIDorName)
 	if err != nil {
 		return err
 	}
+
+	if spec {
+		return writeSpec(c.Out, clusterSpec(cluster), Output)
+	}
 	return displayClusters(c, false, *cluster)
 }
 
@@ -689,15 +865,51 @@ func (s *KubernetesCommandService) RunKubernetesClusterGetUpgrades(c *CmdConfig)
 // RunKubernetesClusterCreate creates a new kubernetes with a given configuration.
 func (s *KubernetesCommandService) RunKubernetesClusterCreate(defaultNodeSize string, defaultNodeCount int) func(*CmdConfig) error {
 	return func(c *CmdConfig) error {
-		err := ensureOneArg(c)
+		specPath, err := c.Doit.GetString(c.NS, doctl.ArgClusterSpec)
 		if err != nil {
 			return err
 		}
-		clusterName := c.Args[0]
-		r := &godo.KubernetesClusterCreateRequest{Name: clusterName}
-		if err := buildClusterCreateRequestFromArgs(c, r, defaultNodeSize, defaultNodeCount); err != nil {
-			return err
+
+		var (
+			r            *godo.KubernetesClusterCreateRequest
+			oneClickApps []string
+		)
+		if specPath != "" {
+			if len(c.Args) > 1 {
+				return doctl.NewTooManyArgsErr(c.NS)
+			}
+			spec, err := readKubernetesClusterSpec(os.Stdin, specPath)
+			if err != nil {
+				return err
+			}
+			if len(c.Args) == 1 {
+				spec.Name = c.Args[0]
+			}
+			if spec.Name == "" {
+				return doctl.NewMissingArgsErr(c.NS)
+			}
+			spec.VersionSlug, err = resolveVersionOrLatest(c, spec.VersionSlug)
+			if err != nil {
+				return err
+			}
+			r = &spec.KubernetesClusterCreateRequest
+			oneClickApps = spec.OneClickApps
+		} else {
+			err := ensureOneArg(c)
+			if err != nil {
+				return err
+			}
+			r = &godo.KubernetesClusterCreateRequest{Name: c.Args[0]}
+			if err := buildClusterCreateRequestFromArgs(c, r, defaultNodeSize, defaultNodeCount); err != nil {
+				return err
+			}
+			oneClickApps, err = c.Doit.GetStringSlice(c.NS, doctl.ArgOneClicks)
+			if err != nil {
+				return err
+			}
 		}
+		clusterName := r.Name
+
 		wait, err := c.Doit.GetBool(c.NS, doctl.ArgCommandWait)
 		if err != nil {
 			return err
@@ -731,10 +943,6 @@ func (s *KubernetesCommandService) RunKubernetesClusterCreate(defaultNodeSize st
 			s.tryUpdateKubeconfig(kube, cluster.ID, clusterName, setCurrentContext)
 		}
 
-		oneClickApps, err := c.Doit.GetStringSlice(c.NS, doctl.ArgOneClicks)
-		if err != nil {
-			return err
-		}
 		if len(oneClickApps) > 0 {
 			oneClicks := c.OneClicks()
 			messageResponse, err := oneClicks.InstallKubernetes(cluster.ID, oneClickApps)
@@ -807,7 +1015,7 @@ func (s *KubernetesCommandService) tryUpdateKubeconfig(kube do.KubernetesService
 			break
 		}
 	}
-	if err := s.writeOrAddToKubeconfig(clusterID, remoteConfig, setCurrentContext, 0); err != nil {
+	if err := s.writeOrAddToKubeconfig(clusterID, remoteConfig, setCurrentContext, 0, ""); err != nil {
 		warn("Couldn't write cluster credentials: %v", err)
 	}
 }
@@ -831,6 +1039,14 @@ func (s *KubernetesCommandService) RunKubernetesClusterUpgrade(c *CmdConfig) err
 		return nil
 	}
 
+	guided, err := c.Doit.GetBool(c.NS, doctl.ArgClusterUpgradeGuided)
+	if err != nil {
+		return err
+	}
+	if guided {
+		return s.runGuidedClusterUpgrade(c, clusterID, version)
+	}
+
 	kube := c.Kubernetes()
 	err = kube.Upgrade(clusterID, version)
 	if err != nil {
@@ -841,6 +1057,181 @@ func (s *KubernetesCommandService) RunKubernetesClusterUpgrade(c *CmdConfig) err
 	return nil
 }
 
+// clusterUpgradePollInterval is how often a cluster's node pools are polled
+// while waiting for a guided upgrade to finish.
+var clusterUpgradePollInterval = 15 * time.Second
+
+// runGuidedClusterUpgrade upgrades a cluster after running preflight checks,
+// then waits for the nodes to be replaced.
+func (s *KubernetesCommandService) runGuidedClusterUpgrade(c *CmdConfig, clusterID, version string) error {
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	kube := c.Kubernetes()
+	cluster, err := kube.Get(clusterID)
+	if err != nil {
+		return err
+	}
+	upgrades, err := kube.GetUpgrades(clusterID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "Cluster:            %s (%s)\n", cluster.Name, cluster.ID)
+	fmt.Fprintf(c.Out, "Current version:    %s\n", cluster.VersionSlug)
+	slugs := make([]string, 0, len(upgrades))
+	for _, u := range upgrades {
+		slugs = append(slugs, u.Slug)
+	}
+	fmt.Fprintf(c.Out, "Available upgrades: %s\n", strings.Join(slugs, ", "))
+	fmt.Fprintf(c.Out, "Target version:     %s\n\n", version)
+
+	// Preflight checks
+	if !cluster.SurgeUpgrade {
+		warn("Surge upgrades are disabled, so nodes will be replaced without extra capacity being added first. Enable them with `doctl kubernetes cluster update %s --surge-upgrade`.", cluster.Name)
+	}
+
+	notice("Running clusterlint")
+	runID, err := kube.RunClusterlint(clusterID, &godo.KubernetesRunClusterlintRequest{})
+	if err != nil {
+		return err
+	}
+	diagnostics, err := waitForClusterlintResults(kube, clusterID, runID, true)
+	if err != nil {
+		return err
+	}
+	sortClusterlintDiagnostics(diagnostics)
+
+	errorCount := 0
+	for _, d := range diagnostics {
+		if d.Severity == "error" {
+			errorCount++
+		}
+		if strings.Contains(strings.ToLower(d.CheckName+" "+d.Message), "deprecated") {
+			object := ""
+			if d.Object != nil {
+				object = fmt.Sprintf(" %s %s/%s", d.Object.Kind, d.Object.Namespace, d.Object.Name)
+			}
+			warn("Deprecated API in use by%s: %s", object, d.Message)
+		}
+	}
+	if len(diagnostics) > 0 {
+		if err := c.Display(&displayers.KubernetesClusterlintDiagnostics{Diagnostics: diagnostics}); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Out)
+	} else {
+		notice("Clusterlint found no issues")
+	}
+
+	if !force {
+		if errorCount > 0 {
+			return fmt.Errorf("clusterlint reported %d error(s); fix them or use the `--%s` flag to upgrade anyway", errorCount, doctl.ArgForce)
+		}
+		if err := AskForConfirm(fmt.Sprintf("upgrade cluster %s from %s to %s", cluster.Name, cluster.VersionSlug, version)); err != nil {
+			return err
+		}
+	}
+
+	// Remember which nodes exist before the upgrade, so we can tell when all
+	// of them have been replaced.
+	pools, err := kube.ListNodePools(clusterID)
+	if err != nil {
+		return err
+	}
+	oldNodes := map[string]bool{}
+	for _, p := range pools {
+		for _, n := range p.Nodes {
+			oldNodes[n.ID] = true
+		}
+	}
+
+	started := time.Now()
+	if err := kube.Upgrade(clusterID, version); err != nil {
+		return err
+	}
+	notice("Upgrading cluster to version %v", version)
+
+	cluster, pools, err = waitForClusterUpgrade(kube, clusterID, version, oldNodes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "\nCluster %s upgraded to %s in %s\n", cluster.Name, cluster.VersionSlug, time.Since(started).Round(time.Second))
+	for _, p := range pools {
+		fmt.Fprintf(c.Out, "  %s: %s\n", p.Name, nodePoolUpgradeProgress(p, oldNodes))
+	}
+	return nil
+}
+
+// waitForClusterUpgrade waits until a cluster runs the given version and all
+// of the nodes it had before the upgrade have been replaced by running nodes.
+func waitForClusterUpgrade(kube do.KubernetesService, clusterID, version string, oldNodes map[string]bool) (*do.KubernetesCluster, do.KubernetesNodePools, error) {
+	const maxAttempts = 480
+
+	failCount := 0
+	lastProgress := map[string]string{}
+	for i := 0; i < maxAttempts; i++ {
+		time.Sleep(clusterUpgradePollInterval)
+
+		cluster, err := kube.Get(clusterID)
+		var pools do.KubernetesNodePools
+		if err == nil {
+			pools, err = kube.ListNodePools(clusterID)
+		}
+		if err != nil {
+			// Allow for transient API failures
+			failCount++
+			if failCount >= maxAPIFailures {
+				return nil, nil, err
+			}
+			continue
+		}
+		failCount = 0
+
+		done := cluster.VersionSlug == version && cluster.Status != nil &&
+			cluster.Status.State == godo.KubernetesClusterStatusRunning
+		for _, p := range pools {
+			progress := nodePoolUpgradeProgress(p, oldNodes)
+			if lastProgress[p.ID] != progress {
+				notice("Node pool %s: %s", p.Name, progress)
+				lastProgress[p.ID] = progress
+			}
+			for _, n := range p.Nodes {
+				if oldNodes[n.ID] || n.Status == nil || n.Status.State != "running" {
+					done = false
+				}
+			}
+		}
+
+		if cluster.Status != nil && cluster.Status.State == godo.KubernetesClusterStatusError {
+			return nil, nil, fmt.Errorf("cluster upgrade failed: %s", cluster.Status.Message)
+		}
+		if done {
+			return cluster, pools, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("timeout waiting for cluster (%s) to be upgraded to %s", clusterID, version)
+}
+
+// nodePoolUpgradeProgress describes how many of a node pool's nodes have been
+// replaced during an upgrade.
+func nodePoolUpgradeProgress(pool do.KubernetesNodePool, oldNodes map[string]bool) string {
+	replaced, running := 0, 0
+	for _, n := range pool.Nodes {
+		if !oldNodes[n.ID] {
+			replaced++
+		}
+		if n.Status != nil && n.Status.State == "running" {
+			running++
+		}
+	}
+	return fmt.Sprintf("%d/%d nodes replaced, %d running", replaced, len(pool.Nodes), running)
+}
+
 func getUpgradeVersionOrLatest(c *CmdConfig, clusterID string) (string, bool, error) {
 	version, err := c.Doit.GetString(c.NS, doctl.ArgClusterVersionSlug)
 	if err != nil {
@@ -1101,98 +1492,345 @@ func (s *KubernetesCommandService) RunKubernetesClusterListAssociatedResources(c
 	return displayAssociatedResources(c, resources)
 }
 
-// Kubeconfig
+// List prices, in USD per month, of cluster resources whose prices aren't
+// available from the API.
+const (
+	haControlPlaneMonthlyPrice   = 40.0
+	loadBalancerNodeMonthlyPrice = 12.0
+	volumeGiBMonthlyPrice        = 0.10
+)
 
-// RunKubernetesKubeconfigShow retrieves an existing kubernetes config and prints it.
-func (s *KubernetesCommandService) RunKubernetesKubeconfigShow(c *CmdConfig) error {
+// loadBalancerSizeUnits is the number of nodes of the legacy load balancer sizes.
+var loadBalancerSizeUnits = map[string]int{
+	"lb-small":  1,
+	"lb-medium": 3,
+	"lb-large":  6,
+}
+
+// RunKubernetesClusterReport estimates the monthly cost of a cluster and its
+// associated resources.
+func (s *KubernetesCommandService) RunKubernetesClusterReport(c *CmdConfig) error {
 	err := ensureOneArg(c)
 	if err != nil {
 		return err
 	}
-	expirySeconds, err := c.Doit.GetInt(c.NS, doctl.ArgKubeConfigExpirySeconds)
+
+	clusterID, err := clusterIDize(c, c.Args[0])
 	if err != nil {
 		return err
 	}
 
-	kube := c.Kubernetes()
-	clusterID, err := clusterIDize(c, c.Args[0])
+	report, err := kubernetesCostReport(c, clusterID)
 	if err != nil {
 		return err
 	}
+	return c.Display(&displayers.KubernetesCost{Report: report})
+}
 
-	var kubeconfig []byte
-	if expirySeconds > 0 {
-		kubeconfig, err = kube.GetKubeConfigWithExpiry(clusterID, int64(expirySeconds))
-	} else {
-		kubeconfig, err = kube.GetKubeConfig(clusterID)
+func kubernetesCostReport(c *CmdConfig, clusterID string) (*displayers.KubernetesCostReport, error) {
+	kube := c.Kubernetes()
+	cluster, err := kube.Get(clusterID)
+	if err != nil {
+		return nil, err
 	}
+	pools, err := kube.ListNodePools(clusterID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	resources, err := kube.ListAssociatedResourcesForDeletion(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := c.Sizes().List()
+	if err != nil {
+		return nil, err
+	}
+	sizesBySlug := make(map[string]do.Size, len(sizes))
+	for _, size := range sizes {
+		sizesBySlug[size.Slug] = size
+	}
+
+	report := &displayers.KubernetesCostReport{
+		ClusterID:   cluster.ID,
+		ClusterName: cluster.Name,
+	}
+	add := func(item displayers.KubernetesCostItem) {
+		report.Items = append(report.Items, item)
+		report.TotalPriceMonthly += item.PriceMonthly
+		report.MinTotalPriceMonthly += item.MinPriceMonthly
+		report.MaxTotalPriceMonthly += item.MaxPriceMonthly
+	}
+	fixed := func(typ, id, name, size string, count int, unitPrice float64) displayers.KubernetesCostItem {
+		price := float64(count) * unitPrice
+		return displayers.KubernetesCostItem{
+			Type:             typ,
+			ID:               id,
+			Name:             name,
+			Size:             size,
+			Count:            count,
+			MinCount:         count,
+			MaxCount:         count,
+			UnitPriceMonthly: unitPrice,
+			PriceMonthly:     price,
+			MinPriceMonthly:  price,
+			MaxPriceMonthly:  price,
+		}
+	}
+
+	if cluster.HA {
+		add(fixed("control-plane", "", "high-availability control plane", "", 1, haControlPlaneMonthlyPrice))
+	}
+
+	for _, pool := range pools {
+		item := fixed("node-pool", pool.ID, pool.Name, pool.Size, pool.Count, 0)
+		if pool.AutoScale {
+			item.MinCount = pool.MinNodes
+			item.MaxCount = pool.MaxNodes
+		}
+		size, ok := sizesBySlug[pool.Size]
+		if !ok {
+			warn("No price is available for node size %s of node pool %s; it is not included in the total.", pool.Size, pool.Name)
+			item.PriceUnknown = true
+			add(item)
+			continue
+		}
+		item.UnitPriceMonthly = size.PriceMonthly
+		item.PriceMonthly = float64(item.Count) * size.PriceMonthly
+		item.MinPriceMonthly = float64(item.MinCount) * size.PriceMonthly
+		item.MaxPriceMonthly = float64(item.MaxCount) * size.PriceMonthly
+		item.CapacityVCPUs = item.Count * size.Vcpus
+		item.CapacityMemoryMiB = item.Count * size.Memory
+		add(item)
 	}
 
-	_, err = c.Out.Write(kubeconfig)
-	return err
-}
+	for _, r := range resources.LoadBalancers {
+		lb, err := c.LoadBalancers().Get(r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get load balancer %s: %v", r.ID, err)
+		}
+		units := int(lb.SizeUnit)
+		if units == 0 {
+			units = loadBalancerSizeUnits[lb.SizeSlug]
+		}
+		if units == 0 {
+			units = 1
+		}
+		add(fixed("load-balancer", lb.ID, lb.Name, fmt.Sprintf("%d node(s)", units), units, loadBalancerNodeMonthlyPrice))
+	}
 
-func cachedExecCredentialPath(id string) string {
-	return filepath.Join(kubeconfigCachePath(), id+".json")
+	for _, r := range resources.Volumes {
+		volume, err := c.Volumes().Get(r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get volume %s: %v", r.ID, err)
+		}
+		add(fixed("volume", volume.ID, volume.Name, fmt.Sprintf("%d GiB", volume.SizeGigaBytes), 1, float64(volume.SizeGigaBytes)*volumeGiBMonthlyPrice))
+	}
+
+	return report, nil
 }
 
-// loadCachedExecCredential attempts to load the cached exec credential from disk. Never errors
-// Returns nil if there's no credential, if it failed to load it, or if it's expired.
-func loadCachedExecCredential(id string) (*clientauthentication.ExecCredential, error) {
-	path := cachedExecCredentialPath(id)
-	f, err := os.Open(path)
+// RunKubernetesClusterLint runs clusterlint against a cluster, or fetches the
+// diagnostics of an earlier run.
+func (s *KubernetesCommandService) RunKubernetesClusterLint(c *CmdConfig) error {
+	err := ensureOneArg(c)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
+		return err
+	}
+
+	clusterID, err := clusterIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
+	runID, err := c.Doit.GetString(c.NS, doctl.ArgClusterlintRunID)
+	if err != nil {
+		return err
+	}
+	wait, err := c.Doit.GetBool(c.NS, doctl.ArgCommandWait)
+	if err != nil {
+		return err
+	}
+
+	req := &godo.KubernetesRunClusterlintRequest{}
+	for _, f := range []struct {
+		flag string
+		dst  *[]string
+	}{
+		{doctl.ArgClusterlintIncludeGroups, &req.IncludeGroups},
+		{doctl.ArgClusterlintExcludeGroups, &req.ExcludeGroups},
+		{doctl.ArgClusterlintIncludeChecks, &req.IncludeChecks},
+		{doctl.ArgClusterlintExcludeChecks, &req.ExcludeChecks},
+	} {
+		values, err := c.Doit.GetStringSlice(c.NS, f.flag)
+		if err != nil {
+			return err
 		}
+		if len(values) > 0 && runID != "" {
+			return fmt.Errorf("--%s cannot be combined with --%s", f.flag, doctl.ArgClusterlintRunID)
+		}
+		*f.dst = values
+	}
 
-		return nil, err
+	kube := c.Kubernetes()
+	if runID == "" {
+		runID, err = kube.RunClusterlint(clusterID, req)
+		if err != nil {
+			return err
+		}
+		if !wait {
+			notice("Clusterlint run %s started. Use `doctl kubernetes cluster lint %s --%s %s` to view its diagnostics.", runID, c.Args[0], doctl.ArgClusterlintRunID, runID)
+			return nil
+		}
 	}
 
-	defer f.Close()
+	diagnostics, err := waitForClusterlintResults(kube, clusterID, runID, wait)
+	if err != nil {
+		return err
+	}
+	sortClusterlintDiagnostics(diagnostics)
 
-	var execCredential *clientauthentication.ExecCredential
-	if err := json.NewDecoder(f).Decode(&execCredential); err != nil {
-		return nil, err
+	if err := c.Display(&displayers.KubernetesClusterlintDiagnostics{Diagnostics: diagnostics}); err != nil {
+		return err
 	}
 
-	if execCredential.Status == nil {
-		// Invalid ExecCredential, remove it
-		err = os.Remove(path)
-		return nil, err
+	errorCount := 0
+	for _, d := range diagnostics {
+		if d.Severity == "error" {
+			errorCount++
+		}
 	}
+	if errorCount > 0 {
+		return fmt.Errorf("clusterlint reported %d error(s)", errorCount)
+	}
+	return nil
+}
 
-	t := execCredential.Status.ExpirationTimestamp
-	if t.IsZero() || t.Time.Before(time.Now()) {
-		err = os.Remove(path)
-		return nil, err
+// clusterlintPollInterval is how often the results of a clusterlint run are
+// polled for while waiting for it to finish.
+var clusterlintPollInterval = 5 * time.Second
+
+// waitForClusterlintResults fetches the diagnostics of a clusterlint run.
+// The API responds with a 404 until the run has finished, which is retried
+// when wait is set.
+func waitForClusterlintResults(kube do.KubernetesService, clusterID, runID string, wait bool) ([]*godo.ClusterlintDiagnostic, error) {
+	const maxAttempts = 120
+
+	req := &godo.KubernetesGetClusterlintRequest{RunId: runID}
+	failCount := 0
+	printNewLineSet := false
+	for i := 0; i < maxAttempts; i++ {
+		if i != 0 {
+			fmt.Fprint(os.Stderr, ".")
+			if !printNewLineSet {
+				printNewLineSet = true
+				defer fmt.Fprintln(os.Stderr)
+			}
+			time.Sleep(clusterlintPollInterval)
+		}
+
+		diagnostics, err := kube.GetClusterlintResults(clusterID, req)
+		if err == nil {
+			return diagnostics, nil
+		}
+		if !wait {
+			return nil, err
+		}
+
+		var errResp *godo.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+			continue
+		}
+		// Allow for transient API failures
+		failCount++
+		if failCount >= maxAPIFailures {
+			return nil, err
+		}
 	}
 
-	return execCredential, nil
+	return nil, fmt.Errorf("timeout waiting for clusterlint run %s to finish", runID)
 }
 
-// cacheExecCredential caches an ExecCredential to the doctl cache directory
-func cacheExecCredential(id string, execCredential *clientauthentication.ExecCredential) error {
-	// Don't bother caching if there's no expiration set
-	if execCredential.Status.ExpirationTimestamp.IsZero() {
-		return nil
+// clusterlintSeverityOrder ranks clusterlint severities from most to least
+// severe.
+var clusterlintSeverityOrder = map[string]int{
+	"error":      0,
+	"warning":    1,
+	"suggestion": 2,
+}
+
+// sortClusterlintDiagnostics groups diagnostics by severity, then by the
+// object they refer to, then by check.
+func sortClusterlintDiagnostics(diagnostics []*godo.ClusterlintDiagnostic) {
+	severity := func(d *godo.ClusterlintDiagnostic) int {
+		if rank, ok := clusterlintSeverityOrder[d.Severity]; ok {
+			return rank
+		}
+		return len(clusterlintSeverityOrder)
+	}
+	object := func(d *godo.ClusterlintDiagnostic) string {
+		if d.Object == nil {
+			return ""
+		}
+		return d.Object.Kind + "/" + d.Object.Namespace + "/" + d.Object.Name
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if severity(a) != severity(b) {
+			return severity(a) < severity(b)
+		}
+		if object(a) != object(b) {
+			return object(a) < object(b)
+		}
+		return a.CheckName < b.CheckName
+	})
+}
+
+// Kubeconfig
+
+// RunKubernetesKubeconfigShow retrieves an existing kubernetes config and prints it.
+func (s *KubernetesCommandService) RunKubernetesKubeconfigShow(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+	expirySeconds, err := c.Doit.GetInt(c.NS, doctl.ArgKubeConfigExpirySeconds)
+	if err != nil {
+		return err
 	}
 
-	cachePath := kubeconfigCachePath()
-	if err := os.MkdirAll(cachePath, os.FileMode(0700)); err != nil {
+	kube := c.Kubernetes()
+	clusterID, err := clusterIDize(c, c.Args[0])
+	if err != nil {
 		return err
 	}
 
-	path := cachedExecCredentialPath(id)
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(0600))
+	var kubeconfig []byte
+	if expirySeconds > 0 {
+		kubeconfig, err = kube.GetKubeConfigWithExpiry(clusterID, int64(expirySeconds))
+	} else {
+		kubeconfig, err = kube.GetKubeConfig(clusterID)
+	}
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	return json.NewEncoder(f).Encode(execCredential)
+	_, err = c.Out.Write(kubeconfig)
+	return err
+}
+
+// removeCachedExecCredential removes a cluster's cached exec credential from
+// the file caches and, if it is the configured backend, from the keyring.
+func removeCachedExecCredential(c *CmdConfig, clusterID, backend string) {
+	dir := kubeconfigCachePath()
+	(&fileCredentialCache{dir: dir}).Remove(clusterID)
+	(&encryptedFileCredentialCache{dir: dir}).Remove(clusterID)
+
+	if backend == credentialCacheKeyring {
+		if cache, err := newExecCredentialCache(backend, dir, c.getContextAccessToken()); err == nil {
+			cache.Remove(clusterID)
+		}
+	}
 }
 
 // RunKubernetesKubeconfigExecCredential displays the exec credential. It is for internal use only.
@@ -1211,16 +1849,37 @@ func (s *KubernetesCommandService) RunKubernetesKubeconfigExecCredential(c *CmdC
 		return fmt.Errorf("Invalid version %q, expected 'v1beta1'", version)
 	}
 
+	backend, err := c.Doit.GetString(c.NS, doctl.ArgKubeconfigCredentialCache)
+	if err != nil {
+		return err
+	}
+
 	kube := c.Kubernetes()
 
 	clusterID := c.Args[0]
-	execCredential, err := loadCachedExecCredential(clusterID)
+
+	// Problems with the cache are not fatal, the credential is fetched from
+	// the API instead. Only report them if we're being verbose.
+	cache, err := newExecCredentialCache(backend, kubeconfigCachePath(), c.getContextAccessToken())
 	if err != nil && Verbose {
 		warn("%v", err)
 	}
+	if cache != nil {
+		unlock, err := lockExecCredentialCache(kubeconfigCachePath(), clusterID)
+		if err != nil && Verbose {
+			warn("%v", err)
+		}
+		if unlock != nil {
+			defer unlock()
+		}
 
-	if execCredential != nil {
-		return json.NewEncoder(c.Out).Encode(execCredential)
+		execCredential, err := cache.Load(clusterID)
+		if err != nil && Verbose {
+			warn("%v", err)
+		}
+		if execCredential != nil {
+			return json.NewEncoder(c.Out).Encode(execCredential)
+		}
 	}
 
 	credentials, err := kube.GetCredentials(clusterID)
@@ -1238,7 +1897,7 @@ func (s *KubernetesCommandService) RunKubernetesKubeconfigExecCredential(c *CmdC
 		Token:                 credentials.Token,
 	}
 
-	execCredential = &clientauthentication.ExecCredential{
+	execCredential := &clientauthentication.ExecCredential{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       execCredentialKind,
 			APIVersion: clientauthentication.SchemeGroupVersion.String(),
@@ -1247,8 +1906,10 @@ func (s *KubernetesCommandService) RunKubernetesKubeconfigExecCredential(c *CmdC
 	}
 
 	// Don't error out when caching credentials, just print it if we're being verbose
-	if err := cacheExecCredential(clusterID, execCredential); err != nil && Verbose {
-		warn("%v", err)
+	if cache != nil {
+		if err := cache.Store(clusterID, execCredential); err != nil && Verbose {
+			warn("%v", err)
+		}
 	}
 
 	return json.NewEncoder(c.Out).Encode(execCredential)
@@ -1281,10 +1942,31 @@ func (s *KubernetesCommandService) RunKubernetesKubeconfigSave(c *CmdConfig) err
 		return err
 	}
 
+	contextName, err := c.Doit.GetString(c.NS, doctl.ArgKubeconfigContextName)
+	if err != nil {
+		return err
+	}
 	if alias != "" {
-		remoteKubeconfig.Contexts[alias] = remoteKubeconfig.Contexts[remoteKubeconfig.CurrentContext]
+		if contextName != "" && contextName != alias {
+			return fmt.Errorf("--%s and --%s cannot be set to different values", doctl.ArgKubernetesAlias, doctl.ArgKubeconfigContextName)
+		}
+		contextName = alias
+	}
+
+	if contextName != "" {
+		remoteKubeconfig.Contexts[contextName] = remoteKubeconfig.Contexts[remoteKubeconfig.CurrentContext]
 		delete(remoteKubeconfig.Contexts, remoteKubeconfig.CurrentContext)
-		remoteKubeconfig.CurrentContext = alias
+		remoteKubeconfig.CurrentContext = contextName
+	}
+
+	namespace, err := c.Doit.GetString(c.NS, doctl.ArgKubeconfigNamespace)
+	if err != nil {
+		return err
+	}
+	if ctx, ok := remoteKubeconfig.Contexts[remoteKubeconfig.CurrentContext]; ok && ctx != nil && namespace != "" {
+		withNamespace := *ctx
+		withNamespace.Namespace = namespace
+		remoteKubeconfig.Contexts[remoteKubeconfig.CurrentContext] = &withNamespace
 	}
 
 	setCurrentContext, err := c.Doit.GetBool(c.NS, doctl.ArgSetCurrentContext)
@@ -1292,13 +1974,88 @@ func (s *KubernetesCommandService) RunKubernetesKubeconfigSave(c *CmdConfig) err
 		return err
 	}
 
-	path := cachedExecCredentialPath(clusterID)
-	_, err = os.Stat(path)
-	if err == nil {
-		os.Remove(path)
+	outputFile, err := c.Doit.GetString(c.NS, doctl.ArgKubeconfigOutputFile)
+	if err != nil {
+		return err
 	}
 
-	return s.writeOrAddToKubeconfig(clusterID, remoteKubeconfig, setCurrentContext, expirySeconds)
+	credentialCache, err := c.Doit.GetString(c.NS, doctl.ArgKubeconfigCredentialCache)
+	if err != nil {
+		return err
+	}
+	switch credentialCache {
+	case "", credentialCacheFile, credentialCacheEncryptedFile, credentialCacheKeyring:
+	default:
+		return fmt.Errorf("unknown credential cache %q; valid values are %s, %s and %s",
+			credentialCache, credentialCacheFile, credentialCacheEncryptedFile, credentialCacheKeyring)
+	}
+
+	removeCachedExecCredential(c, clusterID, credentialCache)
+
+	if outputFile != "" {
+		return writeStandaloneKubeconfig(clusterID, remoteKubeconfig, outputFile, expirySeconds, credentialCache)
+	}
+
+	return s.writeOrAddToKubeconfig(clusterID, remoteKubeconfig, setCurrentContext, expirySeconds, credentialCache)
+}
+
+// RunKubernetesKubeconfigPrune removes the contexts of clusters that no longer
+// exist from your local kubeconfig.
+func (s *KubernetesCommandService) RunKubernetesKubeconfigPrune(c *CmdConfig) error {
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := c.Kubernetes().List()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		existing[cluster.ID] = true
+	}
+
+	localKubeconfig, err := s.KubeconfigProvider.Local()
+	if err != nil {
+		return err
+	}
+	kubectlDefaults := s.KubeconfigProvider.ConfigPath()
+
+	authContext := getCurrentAuthContextFn()
+	stale, skipped := staleKubeconfigContexts(localKubeconfig, existing, authContext)
+	if skipped > 0 {
+		notice("Skipping %d context(s) of clusters in other authentication contexts than %q", skipped, authContext)
+	}
+	if len(stale) == 0 {
+		notice("No contexts of deleted clusters found in kubeconfig file %q", kubectlDefaults)
+		return nil
+	}
+
+	names := make([]string, 0, len(stale))
+	for name := range stale {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(color.Output, "The following contexts belong to clusters that no longer exist:")
+	for _, name := range names {
+		fmt.Fprintf(color.Output, "  %s (cluster %s)\n", name, stale[name].id)
+	}
+
+	if !force {
+		if err := AskForConfirm(fmt.Sprintf("remove %d context(s) from your kubeconfig", len(names))); err != nil {
+			return err
+		}
+	}
+
+	notice("Removing contexts from kubeconfig file found in %q", kubectlDefaults)
+	pruneKubeconfigContexts(localKubeconfig, names)
+	for _, name := range names {
+		removeCachedExecCredential(c, stale[name].id, stale[name].credentialCache)
+	}
+
+	return s.KubeconfigProvider.Write(localKubeconfig)
 }
 
 // RunKubernetesKubeconfigRemove retrieves an existing kubernetes config and removes it from your local kubeconfig.
@@ -1357,6 +2114,78 @@ func (s *KubernetesCommandService) RunKubernetesNodePoolList(c *CmdConfig) error
 	return displayNodePools(c, list...)
 }
 
+// RunKubernetesNodePoolApply reconciles a cluster's node pools with the ones
+// described in a file.
+func (s *KubernetesCommandService) RunKubernetesNodePoolApply(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+	clusterID, err := clusterIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+
+	path, err := c.Doit.GetString(c.NS, doctl.ArgNodePoolsFile)
+	if err != nil {
+		return err
+	}
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	desired, err := readNodePoolsFile(os.Stdin, path)
+	if err != nil {
+		return err
+	}
+
+	kube := c.Kubernetes()
+	current, err := kube.ListNodePools(clusterID)
+	if err != nil {
+		return err
+	}
+
+	plan, err := planNodePools(current, desired.NodePools)
+	if err != nil {
+		return err
+	}
+
+	writeNodePoolPlan(color.Output, plan)
+	if len(plan) == 0 {
+		return displayNodePools(c, current...)
+	}
+
+	if !force {
+		if err := AskForConfirm("apply these changes to the node pools"); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range plan {
+		switch change.action {
+		case nodePoolCreate:
+			if _, err := kube.CreateNodePool(clusterID, change.create); err != nil {
+				return fmt.Errorf("creating node pool %s: %w", change.name, err)
+			}
+		case nodePoolUpdate:
+			if _, err := kube.UpdateNodePool(clusterID, change.current.ID, change.update); err != nil {
+				return fmt.Errorf("updating node pool %s: %w", change.name, err)
+			}
+		case nodePoolDelete:
+			if err := kube.DeleteNodePool(clusterID, change.current.ID); err != nil {
+				return fmt.Errorf("deleting node pool %s: %w", change.name, err)
+			}
+		}
+	}
+
+	pools, err := kube.ListNodePools(clusterID)
+	if err != nil {
+		return err
+	}
+	return displayNodePools(c, pools...)
+}
+
 // RunKubernetesNodePoolCreate creates a new cluster node pool with a given configuration.
 func (s *KubernetesCommandService) RunKubernetesNodePoolCreate(c *CmdConfig) error {
 	err := ensureOneArg(c)
@@ -1530,57 +2359,259 @@ func (s *KubernetesCommandService) RunKubeOptionsListRegion(c *CmdConfig) error
 	if err != nil {
 		return err
 	}
-	item := &displayers.KubernetesRegions{KubernetesRegions: regions}
-	return c.Display(item)
+	item := &displayers.KubernetesRegions{KubernetesRegions: regions}
+	return c.Display(item)
+}
+
+// RunKubeOptionsListNodeSizes lists valid node sizes for kubernetes clusters.
+func (s *KubernetesCommandService) RunKubeOptionsListNodeSizes(c *CmdConfig) error {
+	kube := c.Kubernetes()
+	sizes, err := kube.GetNodeSizes()
+	if err != nil {
+		return err
+	}
+	item := &displayers.KubernetesNodeSizes{KubernetesNodeSizes: sizes}
+	return c.Display(item)
+}
+
+func (s *KubernetesCommandService) RunKubernetesRegistryAdd(c *CmdConfig) error {
+	if len(c.Args) < 1 {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+	clusterUUIDs := make([]string, 0, len(c.Args))
+	for _, arg := range c.Args {
+		clusterID, err := clusterIDize(c, arg)
+		if err != nil {
+			return err
+		}
+		clusterUUIDs = append(clusterUUIDs, clusterID)
+	}
+	r := new(godo.KubernetesClusterRegistryRequest)
+	r.ClusterUUIDs = clusterUUIDs
+
+	kube := c.Kubernetes()
+	return kube.AddRegistry(r)
+}
+
+func (s *KubernetesCommandService) RunKubernetesRegistryRemove(c *CmdConfig) error {
+	if len(c.Args) < 1 {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+	clusterUUIDs := make([]string, 0, len(c.Args))
+	for _, arg := range c.Args {
+		clusterID, err := clusterIDize(c, arg)
+		if err != nil {
+			return err
+		}
+		clusterUUIDs = append(clusterUUIDs, clusterID)
+	}
+	r := new(godo.KubernetesClusterRegistryRequest)
+	r.ClusterUUIDs = clusterUUIDs
+
+	kube := c.Kubernetes()
+	return kube.RemoveRegistry(r)
+}
+
+// RunKubernetesRegistryStatus shows the registry integration status of clusters,
+// including the namespaces that have the registry's pull secret.
+func (s *KubernetesCommandService) RunKubernetesRegistryStatus(c *CmdConfig) error {
+	secretName, err := registryPullSecretName(c)
+	if err != nil {
+		return err
+	}
+
+	kube := c.Kubernetes()
+	var clusters do.KubernetesClusters
+	if len(c.Args) == 0 {
+		clusters, err = kube.List()
+		if err != nil {
+			return err
+		}
+	}
+	for _, arg := range c.Args {
+		cluster, err := clusterByIDorName(kube, arg)
+		if err != nil {
+			return err
+		}
+		clusters = append(clusters, *cluster)
+	}
+
+	statuses := make([]displayers.KubernetesRegistryClusterStatus, 0, len(clusters))
+	for _, cluster := range clusters {
+		status := displayers.KubernetesRegistryClusterStatus{
+			ClusterID:       cluster.ID,
+			ClusterName:     cluster.Name,
+			RegistryEnabled: cluster.RegistryEnabled,
+			SecretName:      secretName,
+		}
+		if cluster.Status == nil || cluster.Status.State != godo.KubernetesClusterStatusRunning {
+			status.Error = "cluster is not running"
+		} else if clientset, err := newKubernetesClientset(c, cluster.ID); err != nil {
+			status.Error = err.Error()
+		} else if status.Namespaces, err = registrySecretNamespaces(context.Background(), clientset, secretName); err != nil {
+			status.Error = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+
+	return c.Display(&displayers.KubernetesRegistryStatus{Clusters: statuses})
+}
+
+// RunKubernetesRegistrySync creates or refreshes the registry's pull secret in
+// namespaces of a cluster, and adds it to their default service accounts.
+func (s *KubernetesCommandService) RunKubernetesRegistrySync(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+	clusterID, err := clusterIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	namespaces, err := c.Doit.GetStringSlice(c.NS, doctl.ArgRegistryNamespaces)
+	if err != nil {
+		return err
+	}
+	if len(namespaces) == 0 {
+		return doctl.NewMissingArgsErr(fmt.Sprintf("%s.%s", c.NS, doctl.ArgRegistryNamespaces))
+	}
+	secretName, err := registryPullSecretName(c)
+	if err != nil {
+		return err
+	}
+
+	dockerCreds, err := c.Registry().DockerCredentials(&godo.RegistryDockerCredentialsRequest{
+		ReadWrite: false,
+	})
+	if err != nil {
+		return err
+	}
+
+	clientset, err := newKubernetesClientset(c, clusterID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var errs error
+	for _, ns := range namespaces {
+		secret := newRegistryPullSecret(secretName, ns, dockerCreds)
+		if err := applyRegistryPullSecret(ctx, clientset, secret); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("namespace %s: %v", ns, err))
+			continue
+		}
+		fmt.Fprintf(c.Out, "Synced secret %s to namespace %s\n", secretName, ns)
+	}
+	return errs
 }
 
-// RunKubeOptionsListNodeSizes lists valid node sizes for kubernetes clusters.
-func (s *KubernetesCommandService) RunKubeOptionsListNodeSizes(c *CmdConfig) error {
-	kube := c.Kubernetes()
-	sizes, err := kube.GetNodeSizes()
+// registryPullSecretName returns the name of the registry's pull secret, given
+// by flag or defaulting to the registry name prefixed with "registry-".
+func registryPullSecretName(c *CmdConfig) (string, error) {
+	secretName, err := c.Doit.GetString(c.NS, doctl.ArgObjectName)
+	if err != nil || secretName != "" {
+		return secretName, err
+	}
+	reg, err := c.Registry().Get()
 	if err != nil {
-		return err
+		return "", err
 	}
-	item := &displayers.KubernetesNodeSizes{KubernetesNodeSizes: sizes}
-	return c.Display(item)
+	return "registry-" + reg.Name, nil
 }
 
-func (s *KubernetesCommandService) RunKubernetesRegistryAdd(c *CmdConfig) error {
-	if len(c.Args) < 1 {
-		return doctl.NewMissingArgsErr(c.NS)
+// registrySecretNamespaces reports, for every namespace of a cluster, whether
+// it has a pull secret and whether its default service account uses it.
+func registrySecretNamespaces(ctx context.Context, clientset kubernetes.Interface, secretName string) ([]displayers.KubernetesRegistryNamespace, error) {
+	core := clientset.CoreV1()
+	namespaces, err := core.Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
 	}
-	clusterUUIDs := make([]string, 0, len(c.Args))
-	for _, arg := range c.Args {
-		clusterID, err := clusterIDize(c, arg)
-		if err != nil {
-			return err
+	secrets, err := core.Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "metadata.name=" + secretName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	serviceAccounts, err := core.ServiceAccounts(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "metadata.name=default",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hasSecret := map[string]bool{}
+	for _, secret := range secrets.Items {
+		if secret.Name == secretName {
+			hasSecret[secret.Namespace] = true
+		}
+	}
+	usesSecret := map[string]bool{}
+	for _, sa := range serviceAccounts.Items {
+		if sa.Name == "default" && hasImagePullSecret(&sa, secretName) {
+			usesSecret[sa.Namespace] = true
 		}
-		clusterUUIDs = append(clusterUUIDs, clusterID)
 	}
-	r := new(godo.KubernetesClusterRegistryRequest)
-	r.ClusterUUIDs = clusterUUIDs
 
-	kube := c.Kubernetes()
-	return kube.AddRegistry(r)
+	out := make([]displayers.KubernetesRegistryNamespace, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		out = append(out, displayers.KubernetesRegistryNamespace{
+			Namespace:             ns.Name,
+			Secret:                hasSecret[ns.Name],
+			DefaultServiceAccount: usesSecret[ns.Name],
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+	return out, nil
 }
 
-func (s *KubernetesCommandService) RunKubernetesRegistryRemove(c *CmdConfig) error {
-	if len(c.Args) < 1 {
-		return doctl.NewMissingArgsErr(c.NS)
-	}
-	clusterUUIDs := make([]string, 0, len(c.Args))
-	for _, arg := range c.Args {
-		clusterID, err := clusterIDize(c, arg)
-		if err != nil {
+// applyRegistryPullSecret creates or refreshes a pull secret, and adds it to
+// the image pull secrets of its namespace's default service account.
+func applyRegistryPullSecret(ctx context.Context, clientset kubernetes.Interface, secret *corev1.Secret) error {
+	secrets := clientset.CoreV1().Secrets(secret.Namespace)
+	existing, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case existing.Type != secret.Type:
+		return fmt.Errorf("secret %s already exists with type %s", secret.Name, existing.Type)
+	default:
+		existing.Data = secret.Data
+		for k, v := range secret.Annotations {
+			if existing.Annotations == nil {
+				existing.Annotations = map[string]string{}
+			}
+			existing.Annotations[k] = v
+		}
+		if _, err := secrets.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
 			return err
 		}
-		clusterUUIDs = append(clusterUUIDs, clusterID)
 	}
-	r := new(godo.KubernetesClusterRegistryRequest)
-	r.ClusterUUIDs = clusterUUIDs
 
-	kube := c.Kubernetes()
-	return kube.RemoveRegistry(r)
+	serviceAccounts := clientset.CoreV1().ServiceAccounts(secret.Namespace)
+	sa, err := serviceAccounts.Get(ctx, "default", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if hasImagePullSecret(sa, secret.Name) {
+		return nil
+	}
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secret.Name})
+	_, err = serviceAccounts.Update(ctx, sa, metav1.UpdateOptions{})
+	return err
+}
+
+func hasImagePullSecret(sa *corev1.ServiceAccount, name string) bool {
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 func buildClusterCreateRequestFromArgs(c *CmdConfig, r *godo.KubernetesClusterCreateRequest, defaultNodeSize string, defaultNodeCount int) error {
@@ -1902,17 +2933,23 @@ func buildNodePoolUpdateRequestFromArgs(c *CmdConfig, r *godo.KubernetesNodePool
 	}
 	r.Count = count
 
-	tags, err := c.Doit.GetStringSlice(c.NS, doctl.ArgTag)
-	if err != nil {
-		return err
+	// Tags and labels are only sent when their flags are set, as empty ones
+	// clear those of the node pool.
+	if c.Doit.IsSet(doctl.ArgTag) {
+		tags, err := c.Doit.GetStringSlice(c.NS, doctl.ArgTag)
+		if err != nil {
+			return err
+		}
+		r.Tags = tags
 	}
-	r.Tags = tags
 
-	labels, err := c.Doit.GetStringMapString(c.NS, doctl.ArgKubernetesLabel)
-	if err != nil {
-		return err
+	if c.Doit.IsSet(doctl.ArgKubernetesLabel) {
+		labels, err := c.Doit.GetStringMapString(c.NS, doctl.ArgKubernetesLabel)
+		if err != nil {
+			return err
+		}
+		r.Labels = labels
 	}
-	r.Labels = labels
 
 	// Check if the taints flag is set so that we can distinguish between not
 	// setting any taints, setting the empty taint (which equals clearing all
@@ -1950,7 +2987,7 @@ func buildNodePoolUpdateRequestFromArgs(c *CmdConfig, r *godo.KubernetesNodePool
 	return nil
 }
 
-func (s *KubernetesCommandService) writeOrAddToKubeconfig(clusterID string, remoteKubeconfig *clientcmdapi.Config, setCurrentContext bool, expirySeconds int) error {
+func (s *KubernetesCommandService) writeOrAddToKubeconfig(clusterID string, remoteKubeconfig *clientcmdapi.Config, setCurrentContext bool, expirySeconds int, credentialCache string) error {
 	localKubeconfig, err := s.KubeconfigProvider.Local()
 	if err != nil {
 		return err
@@ -1958,7 +2995,7 @@ func (s *KubernetesCommandService) writeOrAddToKubeconfig(clusterID string, remo
 
 	kubectlDefaults := s.KubeconfigProvider.ConfigPath()
 	notice("Adding cluster credentials to kubeconfig file found in %q", kubectlDefaults)
-	if err := mergeKubeconfig(clusterID, remoteKubeconfig, localKubeconfig, setCurrentContext, expirySeconds); err != nil {
+	if err := mergeKubeconfig(clusterID, remoteKubeconfig, localKubeconfig, setCurrentContext, expirySeconds, credentialCache); err != nil {
 		return fmt.Errorf("Couldn't use the kubeconfig info received, %v", err)
 	}
 
@@ -1982,10 +3019,122 @@ func removeFromKubeconfig(kubeconfig []byte) error {
 	return clientcmd.ModifyConfig(kubectlDefaults, *currentConfig, false)
 }
 
+// writeStandaloneKubeconfig writes a remote cluster's config to its own
+// kubeconfig file, with the cluster's context set as the current context.
+func writeStandaloneKubeconfig(clusterID string, remote *clientcmdapi.Config, path string, expirySeconds int, credentialCache string) error {
+	standalone := clientcmdapi.NewConfig()
+	if err := mergeKubeconfig(clusterID, remote, standalone, true, expirySeconds, credentialCache); err != nil {
+		return fmt.Errorf("Couldn't use the kubeconfig info received, %v", err)
+	}
+
+	notice("Writing cluster credentials to kubeconfig file %q", path)
+	return clientcmd.WriteToFile(*standalone, path)
+}
+
+// kubeconfigCluster is the DigitalOcean Kubernetes cluster a kubeconfig
+// context belongs to, as written by doctl in its exec-credential command.
+type kubeconfigCluster struct {
+	id              string
+	authContext     string
+	credentialCache string
+}
+
+// kubeconfigContextCluster returns the DigitalOcean Kubernetes cluster a
+// kubeconfig context gets its credentials for from doctl, and whether the
+// context has such a credential command.
+func kubeconfigContextCluster(config *clientcmdapi.Config, ctx *clientcmdapi.Context) (kubeconfigCluster, bool) {
+	auth, ok := config.AuthInfos[ctx.AuthInfo]
+	if !ok || auth == nil || auth.Exec == nil {
+		return kubeconfigCluster{}, false
+	}
+
+	args := auth.Exec.Args
+	execCredential := false
+	for _, arg := range args {
+		if arg == "exec-credential" {
+			execCredential = true
+		}
+	}
+	if !execCredential || len(args) == 0 || !looksLikeUUID(args[len(args)-1]) {
+		return kubeconfigCluster{}, false
+	}
+
+	cluster := kubeconfigCluster{id: args[len(args)-1]}
+	for _, arg := range args {
+		if v := strings.TrimPrefix(arg, "--"+doctl.ArgContext+"="); v != arg {
+			cluster.authContext = v
+		}
+		if v := strings.TrimPrefix(arg, "--"+doctl.ArgKubeconfigCredentialCache+"="); v != arg {
+			cluster.credentialCache = v
+		}
+	}
+	return cluster, true
+}
+
+// staleKubeconfigContexts returns the contexts of DigitalOcean Kubernetes
+// clusters of authContext that are not in existing. Contexts of clusters of
+// other auth contexts are counted as skipped, as existing can't tell whether
+// they still exist. Credential commands written before doctl recorded the
+// auth context use the one doctl runs with, which is authContext.
+func staleKubeconfigContexts(config *clientcmdapi.Config, existing map[string]bool, authContext string) (map[string]kubeconfigCluster, int) {
+	stale := map[string]kubeconfigCluster{}
+	skipped := 0
+	for name, ctx := range config.Contexts {
+		if ctx == nil {
+			continue
+		}
+		cluster, ok := kubeconfigContextCluster(config, ctx)
+		if !ok || existing[cluster.id] {
+			continue
+		}
+		if cluster.authContext != "" && cluster.authContext != authContext {
+			skipped++
+			continue
+		}
+		stale[name] = cluster
+	}
+	return stale, skipped
+}
+
+// pruneKubeconfigContexts removes contexts from a kubeconfig, together with
+// the cluster and user entries that no remaining context refers to.
+func pruneKubeconfigContexts(config *clientcmdapi.Config, names []string) {
+	removed := make([]*clientcmdapi.Context, 0, len(names))
+	for _, name := range names {
+		removed = append(removed, config.Contexts[name])
+		delete(config.Contexts, name)
+		if config.CurrentContext == name {
+			config.CurrentContext = ""
+			notice("The removed context %s was set as the current context in kubectl. Run `kubectl config get-contexts` to see a list of other contexts you can use, and `kubectl config use-context` to specify a new one.", name)
+		}
+	}
+
+	clustersInUse := map[string]bool{}
+	authInfosInUse := map[string]bool{}
+	for _, ctx := range config.Contexts {
+		if ctx != nil {
+			clustersInUse[ctx.Cluster] = true
+			authInfosInUse[ctx.AuthInfo] = true
+		}
+	}
+	for _, ctx := range removed {
+		if ctx == nil {
+			continue
+		}
+		if !clustersInUse[ctx.Cluster] {
+			delete(config.Clusters, ctx.Cluster)
+		}
+		if !authInfosInUse[ctx.AuthInfo] {
+			delete(config.AuthInfos, ctx.AuthInfo)
+		}
+	}
+}
+
 // mergeKubeconfig merges a remote cluster's config file with a local config file,
 // assuming that the current context in the remote config file points to the
-// cluster details to add to the local config.
-func mergeKubeconfig(clusterID string, remote, local *clientcmdapi.Config, setCurrentContext bool, expirySeconds int) error {
+// cluster details to add to the local config. Unless credentialCache is empty,
+// the credential command caches credentials with that backend.
+func mergeKubeconfig(clusterID string, remote, local *clientcmdapi.Config, setCurrentContext bool, expirySeconds int, credentialCache string) error {
 	remoteCtx, ok := remote.Contexts[remote.CurrentContext]
 	if !ok {
 		// this is a bug in the backend, we received incomplete/non-sensical data
@@ -2018,19 +3167,22 @@ func mergeKubeconfig(clusterID string, remote, local *clientcmdapi.Config, setCu
 		}
 	default:
 		// Configure kubectl to call doctl to renew credentials automatically
+		args := []string{
+			"kubernetes",
+			"cluster",
+			"kubeconfig",
+			"exec-credential",
+			"--version=v1beta1",
+			"--context=" + getCurrentAuthContextFn(),
+		}
+		if credentialCache != "" {
+			args = append(args, "--"+doctl.ArgKubeconfigCredentialCache+"="+credentialCache)
+		}
 		local.AuthInfos[remoteCtx.AuthInfo] = &clientcmdapi.AuthInfo{
 			Exec: &clientcmdapi.ExecConfig{
 				APIVersion: clientauthentication.SchemeGroupVersion.String(),
 				Command:    doctl.CommandName(),
-				Args: []string{
-					"kubernetes",
-					"cluster",
-					"kubeconfig",
-					"exec-credential",
-					"--version=v1beta1",
-					"--context=" + getCurrentAuthContextFn(),
-					clusterID,
-				},
+				Args:       append(args, clusterID),
 			},
 		}
 	}
@@ -2210,6 +3362,39 @@ func iDize(c *CmdConfig, resourceIDOrName string, resType string, regionSlug str
 				ids = append(ids, id)
 			}
 		}
+	case "droplet":
+		droplets, err := c.Droplets().List()
+		if err != nil {
+			return "", err
+		}
+		for _, d := range droplets {
+			if d.Name == resourceIDOrName {
+				id := strconv.Itoa(d.ID)
+				ids = append(ids, id)
+			}
+		}
+	case "app":
+		apps, err := c.Apps().List(false)
+		if err != nil {
+			return "", err
+		}
+		for _, a := range apps {
+			if a.Spec != nil && a.Spec.Name == resourceIDOrName {
+				id := a.ID
+				ids = append(ids, id)
+			}
+		}
+	case "database":
+		databases, err := c.Databases().List()
+		if err != nil {
+			return "", err
+		}
+		for _, d := range databases {
+			if d.Name == resourceIDOrName {
+				id := d.ID
+				ids = append(ids, id)
+			}
+		}
 	}
 
 	switch {
@@ -2357,6 +3542,12 @@ func getVersionOrLatest(c *CmdConfig) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return resolveVersionOrLatest(c, version)
+}
+
+// resolveVersionOrLatest returns the given version slug, or the latest
+// available version when it is empty or "latest".
+func resolveVersionOrLatest(c *CmdConfig, version string) (string, error) {
 	if version != "" && version != defaultKubernetesLatestVersion {
 		return version, nil
 	}
@@ -2522,3 +3713,346 @@ func boolPtr(val bool) *bool {
 func intPtr(val int) *int {
 	return &val
 }
+
+// nodePoolsFile describes the desired node pools of a cluster.
+type nodePoolsFile struct {
+	NodePools []*godo.KubernetesNodePoolCreateRequest `json:"node_pools"`
+}
+
+// readNodePoolsFile reads and validates a YAML or JSON node pools file. A path
+// of "-" reads from stdin.
+func readNodePoolsFile(stdin io.Reader, path string) (*nodePoolsFile, error) {
+	var r io.Reader
+	if path == "-" && stdin != nil {
+		r = stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("opening node pools file: %s does not exist", path)
+			}
+			return nil, fmt.Errorf("opening node pools file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading node pools file: %w", err)
+	}
+
+	jsonFile, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing node pools file: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonFile))
+	dec.DisallowUnknownFields()
+
+	var pools nodePoolsFile
+	if err := dec.Decode(&pools); err != nil {
+		return nil, fmt.Errorf("parsing node pools file: %w", err)
+	}
+
+	if errs := validateNodePoolRequests(pools.NodePools); errs != nil {
+		return nil, fmt.Errorf("invalid node pools file: %w", errs)
+	}
+
+	return &pools, nil
+}
+
+// validateNodePoolRequests checks the node pools of a file or spec, returning
+// all of the problems found.
+func validateNodePoolRequests(pools []*godo.KubernetesNodePoolCreateRequest) error {
+	var errs error
+	seen := map[string]bool{}
+	for i, p := range pools {
+		if p == nil {
+			errs = multierror.Append(errs, fmt.Errorf("node_pools[%d]: must not be empty", i))
+			continue
+		}
+		if p.Name == "" {
+			errs = multierror.Append(errs, fmt.Errorf("node_pools[%d]: name is required", i))
+		} else if seen[p.Name] {
+			errs = multierror.Append(errs, fmt.Errorf("node_pools[%d]: duplicate node pool name %q", i, p.Name))
+		}
+		seen[p.Name] = true
+
+		if p.AutoScale {
+			if p.MaxNodes < 1 || p.MinNodes > p.MaxNodes {
+				errs = multierror.Append(errs, fmt.Errorf("node_pools[%d]: max_nodes must be at least 1 and no less than min_nodes", i))
+			}
+		} else {
+			if p.Count < 1 {
+				errs = multierror.Append(errs, fmt.Errorf("node_pools[%d]: count must be at least 1 unless auto_scale is enabled", i))
+			}
+			if p.MinNodes != 0 || p.MaxNodes != 0 {
+				errs = multierror.Append(errs, fmt.Errorf("node_pools[%d]: min_nodes and max_nodes require auto_scale", i))
+			}
+		}
+	}
+	if len(pools) == 0 {
+		errs = multierror.Append(errs, errors.New("node_pools: at least one node pool is required"))
+	}
+	return errs
+}
+
+type nodePoolAction string
+
+const (
+	nodePoolCreate nodePoolAction = "create"
+	nodePoolUpdate nodePoolAction = "update"
+	nodePoolDelete nodePoolAction = "delete"
+)
+
+// nodePoolChange is a single step of a node pool plan.
+type nodePoolChange struct {
+	action  nodePoolAction
+	name    string
+	current *do.KubernetesNodePool
+	create  *godo.KubernetesNodePoolCreateRequest
+	update  *godo.KubernetesNodePoolUpdateRequest
+	changes []fieldChange
+}
+
+// nodePoolSpec returns the configurable fields of an existing node pool.
+// The tags DOKS adds to every node pool are left out.
+func nodePoolSpec(pool *godo.KubernetesNodePool) *godo.KubernetesNodePoolCreateRequest {
+	return &godo.KubernetesNodePoolCreateRequest{
+		Name:      pool.Name,
+		Size:      pool.Size,
+		Count:     pool.Count,
+		Tags:      withoutDOKSTags(pool.Tags),
+		Labels:    pool.Labels,
+		Taints:    pool.Taints,
+		AutoScale: pool.AutoScale,
+		MinNodes:  pool.MinNodes,
+		MaxNodes:  pool.MaxNodes,
+	}
+}
+
+// withoutDOKSTags removes the k8s and k8s:* tags that DOKS adds to clusters
+// and node pools.
+func withoutDOKSTags(tags []string) []string {
+	var out []string
+	for _, t := range tags {
+		if t != "k8s" && !strings.HasPrefix(t, "k8s:") {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// kubernetesClusterSpec describes a cluster to create: the fields of a cluster
+// create request, plus the 1-Click Applications to install on it.
+type kubernetesClusterSpec struct {
+	godo.KubernetesClusterCreateRequest
+	OneClickApps []string `json:"one_click_apps,omitempty"`
+}
+
+// clusterSpec returns the configuration of an existing cluster as a spec that
+// can be used to create a copy of it.
+func clusterSpec(cluster *do.KubernetesCluster) *kubernetesClusterSpec {
+	spec := &kubernetesClusterSpec{
+		KubernetesClusterCreateRequest: godo.KubernetesClusterCreateRequest{
+			Name:         cluster.Name,
+			RegionSlug:   cluster.RegionSlug,
+			VersionSlug:  cluster.VersionSlug,
+			Tags:         withoutDOKSTags(cluster.Tags),
+			VPCUUID:      cluster.VPCUUID,
+			HA:           cluster.HA,
+			AutoUpgrade:  cluster.AutoUpgrade,
+			SurgeUpgrade: cluster.SurgeUpgrade,
+		},
+	}
+	if mp := cluster.MaintenancePolicy; mp != nil {
+		// The duration of the maintenance window is set by DOKS.
+		spec.MaintenancePolicy = &godo.KubernetesMaintenancePolicy{
+			StartTime: mp.StartTime,
+			Day:       mp.Day,
+		}
+	}
+	for _, pool := range cluster.NodePools {
+		spec.NodePools = append(spec.NodePools, nodePoolSpec(pool))
+	}
+	return spec
+}
+
+// readKubernetesClusterSpec reads and validates a YAML or JSON cluster spec.
+// A path of "-" reads from stdin.
+func readKubernetesClusterSpec(stdin io.Reader, path string) (*kubernetesClusterSpec, error) {
+	var r io.Reader
+	if path == "-" && stdin != nil {
+		r = stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("opening cluster spec: %s does not exist", path)
+			}
+			return nil, fmt.Errorf("opening cluster spec: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading cluster spec: %w", err)
+	}
+
+	jsonSpec, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cluster spec: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonSpec))
+	dec.DisallowUnknownFields()
+
+	var spec kubernetesClusterSpec
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("parsing cluster spec: %w", err)
+	}
+
+	var errs error
+	if spec.RegionSlug == "" {
+		errs = multierror.Append(errs, errors.New("region is required"))
+	}
+	errs = multierror.Append(errs, validateNodePoolRequests(spec.NodePools))
+	for i, p := range spec.NodePools {
+		if p != nil && p.Size == "" {
+			errs = multierror.Append(errs, fmt.Errorf("node_pools[%d]: size is required", i))
+		}
+	}
+	if merr, ok := errs.(*multierror.Error); ok && merr.ErrorOrNil() != nil {
+		return nil, fmt.Errorf("invalid cluster spec: %w", merr)
+	}
+
+	return &spec, nil
+}
+
+// nodePoolSpecKeys identifies taints by their key, value and effect when
+// diffing node pools. godo.Taint has no JSON tags, so its fields keep their Go
+// names.
+var nodePoolSpecKeys = map[string]elementKeyFn{
+	"taints": func(m map[string]interface{}) string {
+		return fmt.Sprintf("%v=%v:%v", m["Key"], m["Value"], m["Effect"])
+	},
+}
+
+// planNodePools works out the changes needed to go from the current node
+// pools to the desired ones. Pools are created first and deleted last so
+// that capacity is added before it is removed.
+func planNodePools(current do.KubernetesNodePools, desired []*godo.KubernetesNodePoolCreateRequest) ([]nodePoolChange, error) {
+	byName := map[string]*do.KubernetesNodePool{}
+	for i := range current {
+		byName[current[i].Name] = &current[i]
+	}
+
+	var creates, updates, deletes []nodePoolChange
+	wanted := map[string]bool{}
+	for _, want := range desired {
+		wanted[want.Name] = true
+
+		pool, ok := byName[want.Name]
+		if !ok {
+			if want.Size == "" {
+				return nil, fmt.Errorf("node pool %s: size is required to create it", want.Name)
+			}
+			changes, err := diffFields(map[string]interface{}{}, want, nodePoolSpecKeys)
+			if err != nil {
+				return nil, err
+			}
+			creates = append(creates, nodePoolChange{action: nodePoolCreate, name: want.Name, create: want, changes: changes})
+			continue
+		}
+
+		have := nodePoolSpec(pool.KubernetesNodePool)
+		target := *want
+		if target.Size == "" {
+			target.Size = have.Size
+		}
+		if target.Size != have.Size {
+			return nil, fmt.Errorf("node pool %s: size cannot be changed from %s to %s; use a new node pool name to replace it", want.Name, have.Size, target.Size)
+		}
+		if target.AutoScale && target.Count == 0 {
+			// Leave the node count to the autoscaler.
+			target.Count = have.Count
+		}
+
+		changes, err := diffFields(have, &target, nodePoolSpecKeys)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		// empty values clear the tags, labels and taints of the pool
+		tags := target.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		labels := target.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		taints := target.Taints
+		if taints == nil {
+			taints = []godo.Taint{}
+		}
+		updates = append(updates, nodePoolChange{
+			action:  nodePoolUpdate,
+			name:    want.Name,
+			current: pool,
+			update: &godo.KubernetesNodePoolUpdateRequest{
+				Name:      target.Name,
+				Count:     intPtr(target.Count),
+				Tags:      tags,
+				Labels:    labels,
+				Taints:    &taints,
+				AutoScale: boolPtr(target.AutoScale),
+				MinNodes:  intPtr(target.MinNodes),
+				MaxNodes:  intPtr(target.MaxNodes),
+			},
+			changes: changes,
+		})
+	}
+
+	for i := range current {
+		if !wanted[current[i].Name] {
+			deletes = append(deletes, nodePoolChange{action: nodePoolDelete, name: current[i].Name, current: &current[i]})
+		}
+	}
+
+	return append(append(creates, updates...), deletes...), nil
+}
+
+// writeNodePoolPlan writes a node pool plan, prefixing each changed field
+// with the name of its node pool.
+func writeNodePoolPlan(w io.Writer, plan []nodePoolChange) {
+	if len(plan) == 0 {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+
+	for _, change := range plan {
+		switch change.action {
+		case nodePoolCreate:
+			fmt.Fprintf(w, "Create node pool %s:\n", change.name)
+		case nodePoolUpdate:
+			fmt.Fprintf(w, "Update node pool %s (%s):\n", change.name, change.current.ID)
+		case nodePoolDelete:
+			color.New(color.FgRed).Fprintf(w, "Delete node pool %s (%s) and its %d node(s)\n", change.name, change.current.ID, len(change.current.Nodes))
+			continue
+		}
+
+		changes := make([]fieldChange, len(change.changes))
+		for i, fc := range change.changes {
+			fc.Path = change.name + "." + fc.Path
+			changes[i] = fc
+		}
+		writeFieldChanges(w, changes)
+	}
+}
@@ -0,0 +1,495 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+	clientauthentication "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+)
+
+// The backends that exec credentials can be cached in.
+const (
+	credentialCacheFile          = "file"
+	credentialCacheEncryptedFile = "encrypted-file"
+	credentialCacheKeyring       = "keyring"
+)
+
+const (
+	// credentialCacheLockTimeout is how long to wait for another process to
+	// release the lock on a cached credential.
+	credentialCacheLockTimeout = 10 * time.Second
+	// credentialCacheLockStale is the age after which a lock whose holder is
+	// no longer running is assumed to have been left behind.
+	credentialCacheLockStale = 30 * time.Second
+	// credentialCacheKeyringService is the service name cached credentials
+	// are stored under in the keyring.
+	credentialCacheKeyringService = "doctl-exec-credential"
+)
+
+// execCredentialCache stores Kubernetes exec credentials between invocations
+// of kubectl. Load returns nil if there is no valid credential for a cluster.
+type execCredentialCache interface {
+	Load(clusterID string) (*clientauthentication.ExecCredential, error)
+	Store(clusterID string, execCredential *clientauthentication.ExecCredential) error
+	Remove(clusterID string) error
+}
+
+// newExecCredentialCache returns the credential cache for a backend. Files
+// are kept in dir, and the encrypted-file backend derives its key from the
+// access token so the cache can only be read by the same doctl account.
+func newExecCredentialCache(backend, dir, accessToken string) (execCredentialCache, error) {
+	switch backend {
+	case "", credentialCacheFile:
+		return &fileCredentialCache{dir: dir}, nil
+	case credentialCacheEncryptedFile:
+		return newEncryptedFileCredentialCache(dir, accessToken)
+	case credentialCacheKeyring:
+		kr, err := newKeyring()
+		if err != nil {
+			return nil, err
+		}
+		return &keyringCredentialCache{keyring: kr}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential cache %q; valid values are %s, %s and %s",
+			backend, credentialCacheFile, credentialCacheEncryptedFile, credentialCacheKeyring)
+	}
+}
+
+// validExecCredential reports whether a cached credential can still be used.
+func validExecCredential(execCredential *clientauthentication.ExecCredential) bool {
+	if execCredential == nil || execCredential.Status == nil || execCredential.Status.ExpirationTimestamp == nil {
+		return false
+	}
+	t := execCredential.Status.ExpirationTimestamp
+	return !t.IsZero() && t.Time.After(time.Now())
+}
+
+// cacheableExecCredential reports whether a credential is worth caching.
+// Credentials without an expiry are not cached.
+func cacheableExecCredential(execCredential *clientauthentication.ExecCredential) bool {
+	return execCredential != nil && execCredential.Status != nil &&
+		execCredential.Status.ExpirationTimestamp != nil && !execCredential.Status.ExpirationTimestamp.IsZero()
+}
+
+// lockExecCredentialCache takes a lock on the cached credential of a cluster,
+// so that concurrent kubectl invocations don't all fetch new credentials and
+// race to write them. The returned function releases the lock.
+func lockExecCredentialCache(dir, clusterID string) (func(), error) {
+	if err := os.MkdirAll(dir, os.FileMode(0700)); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, clusterID+".lock")
+	deadline := time.Now().Add(credentialCacheLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, os.FileMode(0600))
+		if err == nil {
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			f.Close()
+			if err != nil {
+				os.Remove(path)
+				return nil, err
+			}
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if staleExecCredentialCacheLock(path) {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the credential cache lock %s", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// staleExecCredentialCacheLock reports whether a lock was left behind by a
+// process that is no longer running. Locks are only considered once they are
+// older than credentialCacheLockStale, so a lock that is still being written
+// is never taken over.
+func staleExecCredentialCacheLock(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) <= credentialCacheLockStale {
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		// the holder died before it could record its PID
+		return true
+	}
+	return !processRunning(pid)
+}
+
+// processRunning reports whether a process with the given PID exists.
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	defer process.Release()
+	if runtime.GOOS == "windows" {
+		// FindProcess opens a handle to the process, which fails if it
+		// doesn't exist.
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// readCredentialFile reads a cached credential file. Files that can be read
+// by other users are removed instead of trusted.
+func readCredentialFile(path string) ([]byte, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("cached credential %s is not a regular file", path)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		os.Remove(path)
+		return nil, fmt.Errorf("removed cached credential %s: permissions %#o are too open", path, info.Mode().Perm())
+	}
+
+	return os.ReadFile(path)
+}
+
+// writeCredentialFile atomically replaces a cached credential file with a
+// file only readable by the current user.
+func writeCredentialFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, os.FileMode(0700)); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(os.FileMode(0600)); err != nil && runtime.GOOS != "windows" {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func removeCredentialFile(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// decodeExecCredential decodes a cached credential, returning nil if it is
+// no longer valid.
+func decodeExecCredential(data []byte) (*clientauthentication.ExecCredential, error) {
+	var execCredential *clientauthentication.ExecCredential
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&execCredential); err != nil {
+		return nil, err
+	}
+	if !validExecCredential(execCredential) {
+		return nil, nil
+	}
+	return execCredential, nil
+}
+
+// fileCredentialCache caches credentials as plain JSON files.
+type fileCredentialCache struct {
+	dir string
+}
+
+var _ execCredentialCache = &fileCredentialCache{}
+
+func (f *fileCredentialCache) path(clusterID string) string {
+	return filepath.Join(f.dir, clusterID+".json")
+}
+
+func (f *fileCredentialCache) Load(clusterID string) (*clientauthentication.ExecCredential, error) {
+	data, err := readCredentialFile(f.path(clusterID))
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	execCredential, err := decodeExecCredential(data)
+	if execCredential == nil {
+		// Invalid or expired credential, remove it
+		removeCredentialFile(f.path(clusterID))
+	}
+	return execCredential, err
+}
+
+func (f *fileCredentialCache) Store(clusterID string, execCredential *clientauthentication.ExecCredential) error {
+	if !cacheableExecCredential(execCredential) {
+		return nil
+	}
+
+	data, err := json.Marshal(execCredential)
+	if err != nil {
+		return err
+	}
+	return writeCredentialFile(f.path(clusterID), data)
+}
+
+func (f *fileCredentialCache) Remove(clusterID string) error {
+	return removeCredentialFile(f.path(clusterID))
+}
+
+// encryptedFileCredentialCache caches credentials in files encrypted with
+// AES-GCM, using a key derived from the doctl access token.
+type encryptedFileCredentialCache struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+var _ execCredentialCache = &encryptedFileCredentialCache{}
+
+func newEncryptedFileCredentialCache(dir, accessToken string) (*encryptedFileCredentialCache, error) {
+	if accessToken == "" {
+		return nil, errors.New("the encrypted-file credential cache requires an access token")
+	}
+
+	key := make([]byte, 32)
+	kdf := hkdf.New(sha256.New, []byte(accessToken), nil, []byte("doctl exec-credential cache"))
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedFileCredentialCache{dir: dir, aead: aead}, nil
+}
+
+func (e *encryptedFileCredentialCache) path(clusterID string) string {
+	return filepath.Join(e.dir, clusterID+".json.enc")
+}
+
+func (e *encryptedFileCredentialCache) Load(clusterID string) (*clientauthentication.ExecCredential, error) {
+	data, err := readCredentialFile(e.path(clusterID))
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	nonceSize := e.aead.NonceSize()
+	if len(data) < nonceSize {
+		removeCredentialFile(e.path(clusterID))
+		return nil, fmt.Errorf("cached credential %s is truncated", e.path(clusterID))
+	}
+	plaintext, err := e.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(clusterID))
+	if err != nil {
+		// The access token has changed or the file was tampered with
+		removeCredentialFile(e.path(clusterID))
+		return nil, nil
+	}
+
+	execCredential, err := decodeExecCredential(plaintext)
+	if execCredential == nil {
+		removeCredentialFile(e.path(clusterID))
+	}
+	return execCredential, err
+}
+
+func (e *encryptedFileCredentialCache) Store(clusterID string, execCredential *clientauthentication.ExecCredential) error {
+	if !cacheableExecCredential(execCredential) {
+		return nil
+	}
+
+	plaintext, err := json.Marshal(execCredential)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	return writeCredentialFile(e.path(clusterID), e.aead.Seal(nonce, nonce, plaintext, []byte(clusterID)))
+}
+
+func (e *encryptedFileCredentialCache) Remove(clusterID string) error {
+	return removeCredentialFile(e.path(clusterID))
+}
+
+// errKeyringItemNotFound is returned by a keyring when it has no secret for
+// the requested service and account.
+var errKeyringItemNotFound = errors.New("keyring item not found")
+
+// keyring stores secrets in an operating system keyring.
+type keyring interface {
+	Get(service, account string) (string, error)
+	Set(service, account, secret string) error
+	Delete(service, account string) error
+}
+
+// keyringCredentialCache caches credentials in a keyring.
+type keyringCredentialCache struct {
+	keyring keyring
+}
+
+var _ execCredentialCache = &keyringCredentialCache{}
+
+func (k *keyringCredentialCache) Load(clusterID string) (*clientauthentication.ExecCredential, error) {
+	secret, err := k.keyring.Get(credentialCacheKeyringService, clusterID)
+	if err != nil {
+		if errors.Is(err, errKeyringItemNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	execCredential, err := decodeExecCredential([]byte(secret))
+	if execCredential == nil {
+		k.Remove(clusterID)
+	}
+	return execCredential, err
+}
+
+func (k *keyringCredentialCache) Store(clusterID string, execCredential *clientauthentication.ExecCredential) error {
+	if !cacheableExecCredential(execCredential) {
+		return nil
+	}
+
+	data, err := json.Marshal(execCredential)
+	if err != nil {
+		return err
+	}
+	return k.keyring.Set(credentialCacheKeyringService, clusterID, string(data))
+}
+
+func (k *keyringCredentialCache) Remove(clusterID string) error {
+	err := k.keyring.Delete(credentialCacheKeyringService, clusterID)
+	if errors.Is(err, errKeyringItemNotFound) {
+		return nil
+	}
+	return err
+}
+
+// newKeyring opens the keyring of the keyring credential cache.
+var newKeyring = func() (keyring, error) {
+	kr, err := newSecretServiceKeyring()
+	if err != nil {
+		return nil, err
+	}
+	return kr, nil
+}
+
+// secretServiceKeyring stores secrets with the freedesktop.org Secret Service
+// API through the secret-tool command from libsecret.
+type secretServiceKeyring struct {
+	secretTool string
+}
+
+var _ keyring = &secretServiceKeyring{}
+
+func newSecretServiceKeyring() (*secretServiceKeyring, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("the keyring credential cache is not supported on %s", runtime.GOOS)
+	}
+	secretTool, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return nil, errors.New("the keyring credential cache requires the secret-tool command from libsecret to access the Secret Service API")
+	}
+	return &secretServiceKeyring{secretTool: secretTool}, nil
+}
+
+func (s *secretServiceKeyring) run(stdin string, args ...string) (string, error) {
+	cmd := exec.Command(s.secretTool, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret-tool %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("secret-tool %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+func (s *secretServiceKeyring) Get(service, account string) (string, error) {
+	secret, err := s.run("", "lookup", "service", service, "account", account)
+	if err != nil {
+		// secret-tool lookup exits with status 1 and no output when nothing
+		// matches.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", errKeyringItemNotFound
+		}
+		return "", err
+	}
+	if secret == "" {
+		return "", errKeyringItemNotFound
+	}
+	return secret, nil
+}
+
+func (s *secretServiceKeyring) Set(service, account, secret string) error {
+	_, err := s.run(secret, "store", "--label", fmt.Sprintf("doctl exec credential for %s", account), "service", service, "account", account)
+	return err
+}
+
+func (s *secretServiceKeyring) Delete(service, account string) error {
+	_, err := s.run("", "clear", "service", service, "account", account)
+	return err
+}
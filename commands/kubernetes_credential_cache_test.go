@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthentication "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+)
+
+// memoryKeyring is a keyring that keeps secrets in memory.
+type memoryKeyring struct {
+	secrets map[string]string
+}
+
+var _ keyring = &memoryKeyring{}
+
+func (m *memoryKeyring) Get(service, account string) (string, error) {
+	secret, ok := m.secrets[service+"/"+account]
+	if !ok {
+		return "", errKeyringItemNotFound
+	}
+	return secret, nil
+}
+
+func (m *memoryKeyring) Set(service, account, secret string) error {
+	m.secrets[service+"/"+account] = secret
+	return nil
+}
+
+func (m *memoryKeyring) Delete(service, account string) error {
+	if _, ok := m.secrets[service+"/"+account]; !ok {
+		return errKeyringItemNotFound
+	}
+	delete(m.secrets, service+"/"+account)
+	return nil
+}
+
+func testExecCredential(expiresAt time.Time) *clientauthentication.ExecCredential {
+	return &clientauthentication.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       execCredentialKind,
+			APIVersion: clientauthentication.SchemeGroupVersion.String(),
+		},
+		Status: &clientauthentication.ExecCredentialStatus{
+			Token:               "secret-token",
+			ExpirationTimestamp: &metav1.Time{Time: expiresAt.Truncate(time.Second)},
+		},
+	}
+}
+
+func TestExecCredentialCaches(t *testing.T) {
+	clusterID := testCluster.ID
+
+	caches := map[string]func(t *testing.T) execCredentialCache{
+		credentialCacheFile: func(t *testing.T) execCredentialCache {
+			return &fileCredentialCache{dir: t.TempDir()}
+		},
+		credentialCacheEncryptedFile: func(t *testing.T) execCredentialCache {
+			cache, err := newEncryptedFileCredentialCache(t.TempDir(), "access-token")
+			require.NoError(t, err)
+			return cache
+		},
+		credentialCacheKeyring: func(t *testing.T) execCredentialCache {
+			return &keyringCredentialCache{keyring: &memoryKeyring{secrets: map[string]string{}}}
+		},
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			cache := newCache(t)
+
+			cred, err := cache.Load(clusterID)
+			require.NoError(t, err)
+			assert.Nil(t, cred)
+
+			valid := testExecCredential(time.Now().Add(time.Hour))
+			require.NoError(t, cache.Store(clusterID, valid))
+			cred, err = cache.Load(clusterID)
+			require.NoError(t, err)
+			assert.Equal(t, valid, cred)
+
+			require.NoError(t, cache.Store(clusterID, testExecCredential(time.Now().Add(-time.Hour))))
+			cred, err = cache.Load(clusterID)
+			require.NoError(t, err)
+			assert.Nil(t, cred)
+
+			require.NoError(t, cache.Store(clusterID, valid))
+			require.NoError(t, cache.Remove(clusterID))
+			require.NoError(t, cache.Remove(clusterID))
+			cred, err = cache.Load(clusterID)
+			require.NoError(t, err)
+			assert.Nil(t, cred)
+		})
+	}
+}
+
+func TestFileCredentialCachePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not enforced on Windows")
+	}
+
+	cache := &fileCredentialCache{dir: t.TempDir()}
+	require.NoError(t, cache.Store(testCluster.ID, testExecCredential(time.Now().Add(time.Hour))))
+
+	path := cache.path(testCluster.ID)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, os.Chmod(path, 0644))
+	cred, err := cache.Load(testCluster.ID)
+	assert.Error(t, err)
+	assert.Nil(t, cred)
+	assert.NoFileExists(t, path)
+}
+
+func TestEncryptedFileCredentialCacheTokenChange(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := newEncryptedFileCredentialCache(dir, "access-token")
+	require.NoError(t, err)
+	require.NoError(t, cache.Store(testCluster.ID, testExecCredential(time.Now().Add(time.Hour))))
+
+	data, err := os.ReadFile(cache.path(testCluster.ID))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-token")
+
+	other, err := newEncryptedFileCredentialCache(dir, "another-token")
+	require.NoError(t, err)
+	cred, err := other.Load(testCluster.ID)
+	require.NoError(t, err)
+	assert.Nil(t, cred)
+	assert.NoFileExists(t, cache.path(testCluster.ID))
+
+	_, err = newEncryptedFileCredentialCache(dir, "")
+	assert.Error(t, err)
+}
+
+func TestLockExecCredentialCache(t *testing.T) {
+	dir := t.TempDir()
+
+	unlock, err := lockExecCredentialCache(dir, testCluster.ID)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var acquiredAt time.Time
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		unlock, err := lockExecCredentialCache(dir, testCluster.ID)
+		if assert.NoError(t, err) {
+			acquiredAt = time.Now()
+			unlock()
+		}
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	releasedAt := time.Now()
+	unlock()
+	wg.Wait()
+
+	assert.True(t, acquiredAt.After(releasedAt), "the second lock was acquired before the first was released")
+	assert.NoFileExists(t, filepath.Join(dir, testCluster.ID+".lock"))
+
+	// stale locks are taken over
+	path := filepath.Join(dir, testCluster.ID+".lock")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	unlock, err = lockExecCredentialCache(dir, testCluster.ID)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestStaleExecCredentialCacheLock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process liveness is checked differently on Windows")
+	}
+
+	path := filepath.Join(t.TempDir(), testCluster.ID+".lock")
+	old := time.Now().Add(-time.Hour)
+	writeLock := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	// recent locks are never stale
+	writeLock("", time.Now())
+	assert.False(t, staleExecCredentialCacheLock(path))
+
+	// old locks whose holder is still running are kept
+	writeLock(strconv.Itoa(os.Getpid()), old)
+	assert.False(t, staleExecCredentialCacheLock(path))
+
+	// old locks whose holder has exited are stale
+	writeLock(strconv.Itoa(math.MaxInt32), old)
+	assert.True(t, staleExecCredentialCacheLock(path))
+
+	// old locks without a holder are stale
+	writeLock("", old)
+	assert.True(t, staleExecCredentialCacheLock(path))
+}
+
+func TestSecretServiceKeyringErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("secret-tool is only used on Linux")
+	}
+
+	fakeSecretTool := func(script string) *secretServiceKeyring {
+		path := filepath.Join(t.TempDir(), "secret-tool")
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0700))
+		return &secretServiceKeyring{secretTool: path}
+	}
+
+	// lookup exits with status 1 and no output when nothing matches
+	kr := fakeSecretTool("exit 1")
+	_, err := kr.Get(credentialCacheKeyringService, testCluster.ID)
+	assert.ErrorIs(t, err, errKeyringItemNotFound)
+
+	// other failures are not mistaken for a missing item
+	kr = fakeSecretTool("exit 2")
+	_, err = kr.Get(credentialCacheKeyringService, testCluster.ID)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errKeyringItemNotFound)
+	assert.Error(t, kr.Set(credentialCacheKeyringService, testCluster.ID, "secret"))
+	assert.Error(t, kr.Delete(credentialCacheKeyringService, testCluster.ID))
+
+	kr = fakeSecretTool("echo 'Cannot create an item in a locked collection' >&2; exit 1")
+	err = kr.Set(credentialCacheKeyringService, testCluster.ID, "secret")
+	assert.EqualError(t, err, "secret-tool store: Cannot create an item in a locked collection")
+	assert.NotErrorIs(t, err, errKeyringItemNotFound)
+}
+
+func TestNewExecCredentialCache(t *testing.T) {
+	cache, err := newExecCredentialCache("", t.TempDir(), "")
+	require.NoError(t, err)
+	assert.IsType(t, &fileCredentialCache{}, cache)
+
+	cache, err = newExecCredentialCache(credentialCacheEncryptedFile, t.TempDir(), "access-token")
+	require.NoError(t, err)
+	assert.IsType(t, &encryptedFileCredentialCache{}, cache)
+
+	_, err = newExecCredentialCache("plaintext", t.TempDir(), "")
+	assert.EqualError(t, err, `unknown credential cache "plaintext"; valid values are file, encrypted-file and keyring`)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeReplacePollInterval is how often the progress of a batch of node
+// replacements is checked.
+var nodeReplacePollInterval = 10 * time.Second
+
+// RunKubernetesNodePoolReplaceNodes replaces the nodes of a node pool in
+// batches, waiting for each batch's replacements to become ready before
+// moving on to the next.
+func (s *KubernetesCommandService) RunKubernetesNodePoolReplaceNodes(c *CmdConfig) error {
+	if len(c.Args) != 2 {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+	clusterID, err := clusterIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	kube := c.Kubernetes()
+	poolID, err := poolIDize(kube, clusterID, c.Args[1])
+	if err != nil {
+		return err
+	}
+
+	all, err := c.Doit.GetBool(c.NS, doctl.ArgNodePoolReplaceAll)
+	if err != nil {
+		return err
+	}
+	nodeIDorNames, err := c.Doit.GetStringSlice(c.NS, doctl.ArgNodePoolNodeIDs)
+	if err != nil {
+		return err
+	}
+	if all == (len(nodeIDorNames) > 0) {
+		return fmt.Errorf("specify either --%s or --%s", doctl.ArgNodePoolReplaceAll, doctl.ArgNodePoolNodeIDs)
+	}
+	maxUnavailable, err := c.Doit.GetInt(c.NS, doctl.ArgNodePoolMaxUnavailable)
+	if err != nil {
+		return err
+	}
+	if maxUnavailable < 1 {
+		return fmt.Errorf("--%s must be at least 1", doctl.ArgNodePoolMaxUnavailable)
+	}
+	skipDrain, err := c.Doit.GetBool(c.NS, "skip-drain")
+	if err != nil {
+		return err
+	}
+	timeout, err := c.Doit.GetDuration(c.NS, doctl.ArgTimeout)
+	if err != nil {
+		return err
+	}
+	force, err := c.Doit.GetBool(c.NS, doctl.ArgForce)
+	if err != nil {
+		return err
+	}
+
+	pool, err := kube.GetNodePool(clusterID, poolID)
+	if err != nil {
+		return err
+	}
+	nodes := pool.Nodes
+	if !all {
+		nodes, err = selectPoolNodes(pool, nodeIDorNames)
+		if err != nil {
+			return err
+		}
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("node pool %s has no nodes to replace", pool.Name)
+	}
+
+	if maxUnavailable >= len(pool.Nodes) {
+		warn("All of node pool %s's nodes may be replaced at once; workloads that only run in this pool will be unavailable until the new nodes are ready.", pool.Name)
+	}
+	if !force && AskForConfirm(fmt.Sprintf("replace %d node(s) in node pool %s, %d at a time?", len(nodes), pool.Name, maxUnavailable)) != nil {
+		return errOperationAborted
+	}
+
+	clientset, err := newKubernetesClientset(c, clusterID)
+	if err != nil {
+		return err
+	}
+
+	started := time.Now()
+	for i := 0; i < len(nodes); i += maxUnavailable {
+		end := i + maxUnavailable
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		batch := nodes[i:end]
+		names := make([]string, 0, len(batch))
+		for _, n := range batch {
+			names = append(names, n.Name)
+		}
+		notice("Replacing %s (%d-%d of %d)", strings.Join(names, ", "), i+1, i+len(batch), len(nodes))
+
+		pendingBefore, err := unscheduledPods(clientset)
+		if err != nil {
+			return err
+		}
+		known := make(map[string]bool, len(pool.Nodes))
+		for _, n := range pool.Nodes {
+			known[n.ID] = true
+		}
+
+		for _, n := range batch {
+			err := kube.DeleteNode(clusterID, poolID, n.ID, &godo.KubernetesNodeDeleteRequest{
+				Replace:   true,
+				SkipDrain: skipDrain,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to replace node %s: %v", n.Name, err)
+			}
+		}
+
+		pool, err = waitForNodeReplacement(kube, clientset, clusterID, poolID, batch, known, pendingBefore, timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(c.Out, "Replaced %d node(s) in node pool %s in %s\n", len(nodes), pool.Name, time.Since(started).Round(time.Second))
+	return nil
+}
+
+// selectPoolNodes returns the nodes of a pool matching the given IDs or names.
+func selectPoolNodes(pool *do.KubernetesNodePool, nodeIDorNames []string) ([]*godo.KubernetesNode, error) {
+	var nodes []*godo.KubernetesNode
+	for _, idOrName := range nodeIDorNames {
+		var found *godo.KubernetesNode
+		for _, n := range pool.Nodes {
+			if n.ID == idOrName || n.Name == idOrName {
+				found = n
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no node with ID or name %q in node pool %s", idOrName, pool.Name)
+		}
+		nodes = append(nodes, found)
+	}
+	return nodes, nil
+}
+
+// waitForNodeReplacement waits until the nodes of a batch have been removed,
+// the same number of new nodes are running and Ready in Kubernetes, and no pods
+// other than those which were already pending are waiting to be scheduled.
+func waitForNodeReplacement(kube do.KubernetesService, clientset kubernetes.Interface, clusterID, poolID string, batch []*godo.KubernetesNode, known map[string]bool, pendingBefore map[types.UID]bool, timeout time.Duration) (*do.KubernetesNodePool, error) {
+	started := time.Now()
+	failCount := 0
+	lastProgress := ""
+	for {
+		time.Sleep(nodeReplacePollInterval)
+		if timeout > 0 && time.Since(started) > timeout {
+			return nil, fmt.Errorf("timed out after %s waiting for replacement nodes: %s", timeout, lastProgress)
+		}
+
+		pool, err := kube.GetNodePool(clusterID, poolID)
+		var nodes *corev1.NodeList
+		if err == nil {
+			nodes, err = clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+		}
+		var pending map[types.UID]bool
+		if err == nil {
+			pending, err = unscheduledPods(clientset)
+		}
+		if err != nil {
+			// Allow for transient API failures
+			failCount++
+			if failCount >= maxAPIFailures {
+				return nil, err
+			}
+			continue
+		}
+		failCount = 0
+
+		done, progress := nodeReplacementProgress(pool, nodes.Items, batch, known, pending, pendingBefore)
+		if progress != lastProgress {
+			notice("%s", progress)
+			lastProgress = progress
+		}
+		if done {
+			return pool, nil
+		}
+	}
+}
+
+// nodeReplacementProgress reports whether a batch of node replacements has
+// completed, along with a description of its progress.
+func nodeReplacementProgress(pool *do.KubernetesNodePool, nodes []corev1.Node, batch []*godo.KubernetesNode, known map[string]bool, pending, pendingBefore map[types.UID]bool) (bool, string) {
+	readyNodes := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		for _, cond := range n.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+				readyNodes[n.Name] = true
+			}
+		}
+	}
+	registered := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		registered[n.Name] = true
+	}
+
+	removed := 0
+	for _, old := range batch {
+		present := registered[old.Name]
+		for _, n := range pool.Nodes {
+			if n.ID == old.ID {
+				present = true
+			}
+		}
+		if !present {
+			removed++
+		}
+	}
+
+	ready := 0
+	for _, n := range pool.Nodes {
+		if !known[n.ID] && n.Status != nil && n.Status.State == "running" && readyNodes[n.Name] && ready < len(batch) {
+			ready++
+		}
+	}
+
+	unscheduled := 0
+	for uid := range pending {
+		if !pendingBefore[uid] {
+			unscheduled++
+		}
+	}
+
+	progress := fmt.Sprintf("%d/%d old nodes removed, %d/%d new nodes ready, %d pod(s) waiting to be scheduled",
+		removed, len(batch), ready, len(batch), unscheduled)
+	return removed == len(batch) && ready == len(batch) && unscheduled == 0, progress
+}
+
+// unscheduledPods returns the UIDs of the pods in a cluster that are pending
+// and have not been assigned to a node.
+func unscheduledPods(clientset kubernetes.Interface) (map[types.UID]bool, error) {
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{
+		FieldSelector: "status.phase=Pending",
+	})
+	if err != nil {
+		return nil, err
+	}
+	uids := make(map[types.UID]bool)
+	for _, p := range pods.Items {
+		if p.Status.Phase == corev1.PodPending && p.Spec.NodeName == "" {
+			uids[p.UID] = true
+		}
+	}
+	return uids, nil
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/godo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// kubernetesRESTConfig returns a client configuration for the Kubernetes API
+// of a cluster, authenticated with the cluster's credentials.
+func kubernetesRESTConfig(c *CmdConfig, clusterID string) (*rest.Config, error) {
+	credentials, err := c.Kubernetes().GetCredentials(clusterID)
+	if err != nil {
+		if errResponse, ok := err.(*godo.ErrorResponse); ok {
+			return nil, fmt.Errorf("Failed to fetch credentials for cluster %q: %v", clusterID, errResponse.Message)
+		}
+		return nil, err
+	}
+
+	return &rest.Config{
+		Host:        credentials.Server,
+		BearerToken: credentials.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   credentials.CertificateAuthorityData,
+			CertData: credentials.ClientCertificateData,
+			KeyData:  credentials.ClientKeyData,
+		},
+		UserAgent: "doctl/" + doctl.DoitVersion.String(),
+	}, nil
+}
+
+// newKubernetesClientset returns a client for the Kubernetes API of a cluster.
+// It is a variable so that tests can substitute a fake clientset.
+var newKubernetesClientset = func(c *CmdConfig, clusterID string) (kubernetes.Interface, error) {
+	config, err := kubernetesRESTConfig(c, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// RunKubernetesClusterPortForward forwards local ports to a pod, or to a pod
+// backing a service or deployment, in a cluster.
+func (s *KubernetesCommandService) RunKubernetesClusterPortForward(c *CmdConfig) error {
+	if len(c.Args) < 3 {
+		return doctl.NewMissingArgsErr(c.NS)
+	}
+	clusterID, err := clusterIDize(c, c.Args[0])
+	if err != nil {
+		return err
+	}
+	resource := c.Args[1]
+	ports := c.Args[2:]
+
+	namespace, err := c.Doit.GetString(c.NS, doctl.ArgObjectNamespace)
+	if err != nil {
+		return err
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	addresses, err := c.Doit.GetStringSlice(c.NS, doctl.ArgPortForwardAddress)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		addresses = []string{"localhost"}
+	}
+
+	config, err := kubernetesRESTConfig(c, clusterID)
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	pod, ports, err := resolvePortForward(context.Background(), clientset, namespace, resource, ports)
+	if err != nil {
+		return err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return err
+	}
+	url := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	fw, err := portforward.NewOnAddresses(dialer, addresses, ports, stop, nil, c.Out, os.Stderr)
+	if err != nil {
+		return err
+	}
+	notice("Forwarding to pod %s/%s; press Ctrl+C to stop", namespace, pod.Name)
+	return fw.ForwardPorts()
+}
+
+// resolvePortForward finds the pod to forward to for a resource given as
+// [pod/|svc/|deploy/]name, and translates the remote ports of a service into
+// the ports of its pod.
+func resolvePortForward(ctx context.Context, clientset kubernetes.Interface, namespace, resource string, ports []string) (*corev1.Pod, []string, error) {
+	kind, name := "pod", resource
+	if i := strings.Index(resource, "/"); i >= 0 {
+		kind, name = strings.ToLower(resource[:i]), resource[i+1:]
+	}
+	if name == "" {
+		return nil, nil, fmt.Errorf("invalid resource %q; use [pod/|svc/|deploy/]<name>", resource)
+	}
+
+	core := clientset.CoreV1()
+	switch kind {
+	case "po", "pod", "pods":
+		pod, err := core.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return nil, nil, fmt.Errorf("pod %s is not running; its phase is %s", name, pod.Status.Phase)
+		}
+		return pod, ports, nil
+
+	case "svc", "service", "services":
+		svc, err := core.Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(svc.Spec.Selector) == 0 {
+			return nil, nil, fmt.Errorf("service %s has no selector, so it has no pods to forward to", name)
+		}
+		pod, err := runningPod(ctx, clientset, namespace, labels.SelectorFromSet(svc.Spec.Selector).String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("service %s: %v", name, err)
+		}
+		podPorts, err := servicePortsToPodPorts(svc, pod, ports)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pod, podPorts, nil
+
+	case "deploy", "deployment", "deployments":
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return nil, nil, err
+		}
+		pod, err := runningPod(ctx, clientset, namespace, selector.String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("deployment %s: %v", name, err)
+		}
+		return pod, ports, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported resource type %q; use pod, svc or deploy", kind)
+}
+
+// runningPod returns a running pod matching a label selector, preferring
+// pods that aren't being deleted.
+func runningPod(ctx context.Context, clientset kubernetes.Interface, namespace, selector string) (*corev1.Pod, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	var found *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if pod.DeletionTimestamp == nil {
+			return pod, nil
+		}
+		if found == nil {
+			found = pod
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no running pods match %s", selector)
+	}
+	return found, nil
+}
+
+// servicePortsToPodPorts translates [local:]remote port mappings, where the
+// remote port is a port of the service, to the matching ports of a pod.
+func servicePortsToPodPorts(svc *corev1.Service, pod *corev1.Pod, ports []string) ([]string, error) {
+	out := make([]string, 0, len(ports))
+	for _, mapping := range ports {
+		local, remote := "", mapping
+		if i := strings.LastIndex(mapping, ":"); i >= 0 {
+			local, remote = mapping[:i], mapping[i+1:]
+		}
+		port, err := strconv.Atoi(remote)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q: %v", mapping, err)
+		}
+
+		var svcPort *corev1.ServicePort
+		for i := range svc.Spec.Ports {
+			if int(svc.Spec.Ports[i].Port) == port {
+				svcPort = &svc.Spec.Ports[i]
+				break
+			}
+		}
+		if svcPort == nil {
+			return nil, fmt.Errorf("service %s has no port %d", svc.Name, port)
+		}
+
+		target, err := podTargetPort(pod, svcPort)
+		if err != nil {
+			return nil, fmt.Errorf("service %s port %d: %v", svc.Name, port, err)
+		}
+		// Keep listening on the service port locally when no local port is given.
+		if !strings.Contains(mapping, ":") {
+			local = remote
+		}
+		out = append(out, fmt.Sprintf("%s:%d", local, target))
+	}
+	return out, nil
+}
+
+// podTargetPort returns the container port of a pod that a service port
+// targets.
+func podTargetPort(pod *corev1.Pod, svcPort *corev1.ServicePort) (int32, error) {
+	switch {
+	case svcPort.TargetPort.Type == intstr.String:
+		for _, container := range pod.Spec.Containers {
+			for _, p := range container.Ports {
+				if p.Name == svcPort.TargetPort.StrVal {
+					return p.ContainerPort, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no port named %q", pod.Name, svcPort.TargetPort.StrVal)
+	case svcPort.TargetPort.IntVal != 0:
+		return svcPort.TargetPort.IntVal, nil
+	default:
+		return svcPort.Port, nil
+	}
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesRESTConfig(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().GetCredentials(testCluster.ID).Return(&do.KubernetesClusterCredentials{
+			KubernetesClusterCredentials: &godo.KubernetesClusterCredentials{
+				Server:                   "https://cde2c0d6-41e3-479e-ba60-ad971227232b.k8s.ondigitalocean.com",
+				CertificateAuthorityData: []byte("ca"),
+				Token:                    "token",
+			},
+		}, nil)
+
+		restConfig, err := kubernetesRESTConfig(config, testCluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "https://cde2c0d6-41e3-479e-ba60-ad971227232b.k8s.ondigitalocean.com", restConfig.Host)
+		assert.Equal(t, "token", restConfig.BearerToken)
+		assert.Equal(t, []byte("ca"), restConfig.CAData)
+	})
+}
+
+func TestResolvePortForward(t *testing.T) {
+	labels := map[string]string{"app": "dashboard"}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring", Labels: labels},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "dashboard",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 3000}, {Name: "metrics", ContainerPort: 9090}},
+			}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		pod("dashboard-pending", corev1.PodPending),
+		pod("dashboard-running", corev1.PodRunning),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Namespace: "monitoring"},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+					{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt(9090)},
+					{Name: "debug", Port: 6060},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "monitoring"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 443}}},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Namespace: "monitoring"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		resource string
+		ports    []string
+		pod      string
+		want     []string
+		err      string
+	}{
+		{
+			name:     "pod",
+			resource: "dashboard-running",
+			ports:    []string{"8080:3000"},
+			pod:      "dashboard-running",
+			want:     []string{"8080:3000"},
+		},
+		{
+			name:     "pod not running",
+			resource: "pod/dashboard-pending",
+			ports:    []string{"3000"},
+			err:      "pod dashboard-pending is not running; its phase is Pending",
+		},
+		{
+			name:     "service",
+			resource: "svc/dashboard",
+			ports:    []string{"8080:80", "9090", ":6060"},
+			pod:      "dashboard-running",
+			want:     []string{"8080:3000", "9090:9090", ":6060"},
+		},
+		{
+			name:     "service port not found",
+			resource: "service/dashboard",
+			ports:    []string{"8080:8080"},
+			err:      "service dashboard has no port 8080",
+		},
+		{
+			name:     "service without selector",
+			resource: "svc/external",
+			ports:    []string{"443"},
+			err:      "service external has no selector, so it has no pods to forward to",
+		},
+		{
+			name:     "deployment",
+			resource: "deploy/dashboard",
+			ports:    []string{"8080:3000"},
+			pod:      "dashboard-running",
+			want:     []string{"8080:3000"},
+		},
+		{
+			name:     "unsupported",
+			resource: "statefulset/dashboard",
+			ports:    []string{"80"},
+			err:      `unsupported resource type "statefulset"; use pod, svc or deploy`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod, ports, err := resolvePortForward(ctx, clientset, "monitoring", tt.resource, tt.ports)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.pod, pod.Name)
+			assert.Equal(t, tt.want, ports)
+		})
+	}
+}
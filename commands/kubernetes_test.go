@@ -1,9 +1,16 @@
 package commands
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/digitalocean/doctl"
 	"github.com/digitalocean/doctl/do"
@@ -11,6 +18,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -207,6 +221,9 @@ func TestKubernetesClusterCommand(t *testing.T) {
 		"registry",
 		"delete-selective",
 		"list-associated-resources",
+		"lint",
+		"report",
+		"port-forward",
 	)
 }
 
@@ -222,6 +239,8 @@ func TestKubernetesNodePoolCommand(t *testing.T) {
 		"delete",
 		"delete-node",
 		"replace-node",
+		"apply",
+		"replace-nodes",
 	)
 }
 
@@ -392,6 +411,202 @@ func TestKubernetesKubeconfigSave(t *testing.T) {
 	})
 }
 
+func TestKubernetesKubeconfigSaveOptions(t *testing.T) {
+	remote := func() clientcmdapi.Config {
+		return clientcmdapi.Config{
+			CurrentContext: "do-sfo2-antoine_s_cluster",
+			Contexts: map[string]*clientcmdapi.Context{
+				"do-sfo2-antoine_s_cluster": {Cluster: "do-sfo2-antoine_s_cluster", AuthInfo: "do-sfo2-antoine_s_cluster-admin"},
+			},
+			Clusters: map[string]*clientcmdapi.Cluster{
+				"do-sfo2-antoine_s_cluster": {Server: "https://" + testCluster.ID + ".k8s.ondigitalocean.com"},
+			},
+			AuthInfos: map[string]*clientcmdapi.AuthInfo{
+				"do-sfo2-antoine_s_cluster-admin": {Token: "secret"},
+			},
+		}
+	}
+
+	// rename the context and set its namespace
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		provider := &mockKubeconfigProvider{local: remote(), remote: *clientcmdapi.NewConfig()}
+		k8sCmdService := &KubernetesCommandService{KubeconfigProvider: provider}
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgKubernetesAlias, "staging")
+		config.Doit.Set(config.NS, doctl.ArgKubeconfigNamespace, "apps")
+		config.Doit.Set(config.NS, doctl.ArgSetCurrentContext, true)
+
+		err := k8sCmdService.RunKubernetesKubeconfigSave(config)
+		require.NoError(t, err)
+
+		assert.Equal(t, "staging", provider.written.CurrentContext)
+		require.Contains(t, provider.written.Contexts, "staging")
+		assert.NotContains(t, provider.written.Contexts, "do-sfo2-antoine_s_cluster")
+		assert.Equal(t, "apps", provider.written.Contexts["staging"].Namespace)
+	})
+
+	// name the context with --context-name
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		provider := &mockKubeconfigProvider{local: remote(), remote: *clientcmdapi.NewConfig()}
+		k8sCmdService := &KubernetesCommandService{KubeconfigProvider: provider}
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgKubeconfigContextName, "staging")
+		config.Doit.Set(config.NS, doctl.ArgSetCurrentContext, true)
+
+		err := k8sCmdService.RunKubernetesKubeconfigSave(config)
+		require.NoError(t, err)
+
+		assert.Equal(t, "staging", provider.written.CurrentContext)
+		require.Contains(t, provider.written.Contexts, "staging")
+		assert.NotContains(t, provider.written.Contexts, "do-sfo2-antoine_s_cluster")
+	})
+
+	// --alias and --context-name must agree
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		provider := &mockKubeconfigProvider{local: remote(), remote: *clientcmdapi.NewConfig()}
+		k8sCmdService := &KubernetesCommandService{KubeconfigProvider: provider}
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgKubeconfigContextName, "staging")
+		config.Doit.Set(config.NS, doctl.ArgKubernetesAlias, "production")
+
+		err := k8sCmdService.RunKubernetesKubeconfigSave(config)
+		assert.EqualError(t, err, "--alias and --context-name cannot be set to different values")
+	})
+
+	// write a standalone kubeconfig file
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		provider := &mockKubeconfigProvider{local: remote(), remote: *clientcmdapi.NewConfig()}
+		k8sCmdService := &KubernetesCommandService{KubeconfigProvider: provider}
+		path := filepath.Join(t.TempDir(), "kubeconfig")
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgKubeconfigOutputFile, path)
+
+		err := k8sCmdService.RunKubernetesKubeconfigSave(config)
+		require.NoError(t, err)
+
+		// the local kubeconfig is left alone
+		assert.Empty(t, provider.written.Contexts)
+
+		written, err := clientcmd.LoadFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "do-sfo2-antoine_s_cluster", written.CurrentContext)
+		assert.Contains(t, written.Contexts, "do-sfo2-antoine_s_cluster")
+		assert.Equal(t, "https://"+testCluster.ID+".k8s.ondigitalocean.com", written.Clusters["do-sfo2-antoine_s_cluster"].Server)
+		require.NotNil(t, written.AuthInfos["do-sfo2-antoine_s_cluster-admin"].Exec)
+		assert.Equal(t, testCluster.ID, written.AuthInfos["do-sfo2-antoine_s_cluster-admin"].Exec.Args[len(written.AuthInfos["do-sfo2-antoine_s_cluster-admin"].Exec.Args)-1])
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+}
+
+func TestKubernetesKubeconfigPrune(t *testing.T) {
+	defer func(fn func() string) { getCurrentAuthContextFn = fn }(getCurrentAuthContextFn)
+	getCurrentAuthContextFn = func() string { return "team-a" }
+
+	deletedID := "cde2c0d6-41e3-479e-ba60-ad9712272399"
+	otherTeamID := "cde2c0d6-41e3-479e-ba60-ad9712272398"
+	execArgs := func(authContext, clusterID string) []string {
+		args := []string{"kubernetes", "cluster", "kubeconfig", "exec-credential", "--version=v1beta1"}
+		if authContext != "" {
+			args = append(args, "--context="+authContext)
+		}
+		return append(args, "--credential-cache=keyring", clusterID)
+	}
+	local := func() clientcmdapi.Config {
+		return clientcmdapi.Config{
+			CurrentContext: "do-nyc1-deleted",
+			Contexts: map[string]*clientcmdapi.Context{
+				"do-sfo2-antoine_s_cluster": {Cluster: "do-sfo2-antoine_s_cluster", AuthInfo: "do-sfo2-antoine_s_cluster-admin"},
+				"do-nyc1-deleted":           {Cluster: "do-nyc1-deleted", AuthInfo: "do-nyc1-deleted-admin"},
+				"deleted-legacy":            {Cluster: "do-nyc1-deleted", AuthInfo: "legacy-admin"},
+				"do-ams3-other-team":        {Cluster: "do-ams3-other-team", AuthInfo: "do-ams3-other-team-admin"},
+				"token-user":                {Cluster: "do-nyc1-deleted", AuthInfo: "token-user"},
+				"minikube":                  {Cluster: "minikube", AuthInfo: "minikube"},
+			},
+			Clusters: map[string]*clientcmdapi.Cluster{
+				"do-sfo2-antoine_s_cluster": {Server: "https://" + testCluster.ID + ".k8s.ondigitalocean.com"},
+				"do-nyc1-deleted":           {Server: "https://" + deletedID + ".k8s.ondigitalocean.com"},
+				"do-ams3-other-team":        {Server: "https://" + otherTeamID + ".k8s.ondigitalocean.com"},
+				"minikube":                  {Server: "https://192.168.49.2:8443"},
+			},
+			AuthInfos: map[string]*clientcmdapi.AuthInfo{
+				"do-sfo2-antoine_s_cluster-admin": {Exec: &clientcmdapi.ExecConfig{Args: execArgs("team-a", testCluster.ID)}},
+				"do-nyc1-deleted-admin":           {Exec: &clientcmdapi.ExecConfig{Args: execArgs("team-a", deletedID)}},
+				"legacy-admin":                    {Exec: &clientcmdapi.ExecConfig{Args: execArgs("", deletedID)}},
+				"do-ams3-other-team-admin":        {Exec: &clientcmdapi.ExecConfig{Args: execArgs("team-b", otherTeamID)}},
+				"token-user":                      {Token: "secret"},
+				"minikube":                        {ClientCertificate: "/tmp/minikube.crt"},
+			},
+		}
+	}
+
+	kr := &memoryKeyring{secrets: map[string]string{
+		credentialCacheKeyringService + "/" + deletedID:   "{}",
+		credentialCacheKeyringService + "/" + otherTeamID: "{}",
+	}}
+	defer func(fn func() (keyring, error)) { newKeyring = fn }(newKeyring)
+	newKeyring = func() (keyring, error) { return kr, nil }
+
+	// contexts of team-b clusters are left alone when pruning team-a
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().List().Return(testClusterList, nil)
+		provider := &mockKubeconfigProvider{remote: local()}
+		k8sCmdService := &KubernetesCommandService{KubeconfigProvider: provider}
+
+		config.Doit.Set(config.NS, doctl.ArgForce, true)
+
+		err := k8sCmdService.RunKubernetesKubeconfigPrune(config)
+		require.NoError(t, err)
+
+		assert.Equal(t, "", provider.written.CurrentContext)
+		assert.ElementsMatch(t, []string{"do-sfo2-antoine_s_cluster", "do-ams3-other-team", "token-user", "minikube"}, mapKeys(provider.written.Contexts))
+		assert.ElementsMatch(t, []string{"do-sfo2-antoine_s_cluster", "do-nyc1-deleted", "do-ams3-other-team", "minikube"}, mapKeys(provider.written.Clusters))
+		assert.ElementsMatch(t, []string{"do-sfo2-antoine_s_cluster-admin", "do-ams3-other-team-admin", "token-user", "minikube"}, mapKeys(provider.written.AuthInfos))
+
+		// the keyring credentials of the pruned cluster are removed
+		assert.Equal(t, []string{credentialCacheKeyringService + "/" + otherTeamID}, mapKeys(kr.secrets))
+	})
+
+	// nothing to prune
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().List().Return(append(testClusterList, do.KubernetesCluster{
+			KubernetesCluster: &godo.KubernetesCluster{ID: deletedID},
+		}), nil)
+		provider := &mockKubeconfigProvider{remote: local()}
+		k8sCmdService := &KubernetesCommandService{KubeconfigProvider: provider}
+
+		err := k8sCmdService.RunKubernetesKubeconfigPrune(config)
+		require.NoError(t, err)
+		assert.Nil(t, provider.written.Contexts)
+	})
+
+	cfg := local()
+	stale, skipped := staleKubeconfigContexts(&cfg, map[string]bool{testCluster.ID: true}, "team-a")
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, map[string]kubeconfigCluster{
+		"do-nyc1-deleted": {id: deletedID, authContext: "team-a", credentialCache: "keyring"},
+		"deleted-legacy":  {id: deletedID, credentialCache: "keyring"},
+	}, stale)
+
+	stale, skipped = staleKubeconfigContexts(&cfg, map[string]bool{otherTeamID: true}, "team-b")
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, []string{"deleted-legacy"}, mapKeys(stale))
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func TestKubernetesKubeconfigShow(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		kubeconfig := []byte(`i'm some yaml`)
@@ -538,6 +753,136 @@ func TestKubernetesCreate(t *testing.T) {
 	})
 }
 
+func TestKubernetesCreateFromSpec(t *testing.T) {
+	cluster := &do.KubernetesCluster{KubernetesCluster: &godo.KubernetesCluster{
+		ID:          testCluster.ID,
+		Name:        "production",
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.27.4-do.0",
+		VPCUUID:     "vpc-uuid",
+		Tags:        []string{"k8s", "k8s:" + testCluster.ID, "team:web"},
+		HA:          true,
+		AutoUpgrade: true,
+		MaintenancePolicy: &godo.KubernetesMaintenancePolicy{
+			StartTime: "04:00",
+			Duration:  "4h0m0s",
+			Day:       godo.KubernetesMaintenanceDaySunday,
+		},
+		NodePools: []*godo.KubernetesNodePool{{
+			ID:        testNodePool.ID,
+			Name:      "workers",
+			Size:      "s-4vcpu-8gb",
+			Count:     3,
+			Tags:      []string{"k8s", "k8s:" + testCluster.ID, "k8s:worker", "backend"},
+			Labels:    map[string]string{"role": "worker"},
+			AutoScale: true,
+			MinNodes:  2,
+			MaxNodes:  6,
+		}},
+	}}
+	expected := &godo.KubernetesClusterCreateRequest{
+		Name:        "production",
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.27.4-do.0",
+		VPCUUID:     "vpc-uuid",
+		Tags:        []string{"team:web"},
+		HA:          true,
+		AutoUpgrade: true,
+		MaintenancePolicy: &godo.KubernetesMaintenancePolicy{
+			StartTime: "04:00",
+			Day:       godo.KubernetesMaintenanceDaySunday,
+		},
+		NodePools: []*godo.KubernetesNodePoolCreateRequest{{
+			Name:      "workers",
+			Size:      "s-4vcpu-8gb",
+			Count:     3,
+			Tags:      []string{"backend"},
+			Labels:    map[string]string{"role": "worker"},
+			AutoScale: true,
+			MinNodes:  2,
+			MaxNodes:  6,
+		}},
+	}
+	file := filepath.Join(t.TempDir(), "cluster.yaml")
+
+	// export the spec of an existing cluster
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().Get(testCluster.ID).Return(cluster, nil)
+
+		var buf bytes.Buffer
+		config.Out = &buf
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgClusterSpec, true)
+
+		err := testK8sCmdService().RunKubernetesClusterGet(config)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "name: production\n")
+		assert.NotContains(t, buf.String(), "k8s:")
+		assert.NotContains(t, buf.String(), "4h0m0s")
+
+		spec := buf.String() + "one_click_apps:\n- monitoring\n"
+		require.NoError(t, os.WriteFile(file, []byte(spec), 0644))
+	})
+
+	// and create a copy of it under a new name
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		r := *expected
+		r.Name = "staging"
+		tm.kubernetes.EXPECT().Create(&r).Return(cluster, nil)
+		tm.oneClick.EXPECT().InstallKubernetes(testCluster.ID, []string{"monitoring"})
+
+		config.Args = append(config.Args, "staging")
+		config.Doit.Set(config.NS, doctl.ArgClusterSpec, file)
+
+		err := testK8sCmdService().RunKubernetesClusterCreate("c-8", 3)(config)
+		assert.NoError(t, err)
+	})
+
+	// the latest version is looked up when the spec doesn't pin one
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		latest := filepath.Join(t.TempDir(), "cluster.yaml")
+		require.NoError(t, os.WriteFile(latest, []byte(`name: production
+region: nyc1
+version: latest
+node_pools:
+  - name: workers
+    size: s-4vcpu-8gb
+    count: 3
+`), 0644))
+
+		r := &godo.KubernetesClusterCreateRequest{
+			Name:        "production",
+			RegionSlug:  "nyc1",
+			VersionSlug: "1.13.1-do.1",
+			NodePools:   []*godo.KubernetesNodePoolCreateRequest{{Name: "workers", Size: "s-4vcpu-8gb", Count: 3}},
+		}
+		tm.kubernetes.EXPECT().GetVersions().Return(testClusterUpgrades, nil)
+		tm.kubernetes.EXPECT().Create(r).Return(cluster, nil)
+
+		config.Doit.Set(config.NS, doctl.ArgClusterSpec, latest)
+
+		err := testK8sCmdService().RunKubernetesClusterCreate("c-8", 3)(config)
+		assert.NoError(t, err)
+	})
+}
+
+func TestReadKubernetesClusterSpec(t *testing.T) {
+	_, err := readKubernetesClusterSpec(strings.NewReader(`name: production
+node_pools:
+  - name: workers
+    count: 3
+`), "-")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "region is required")
+	assert.Contains(t, err.Error(), "node_pools[0]: size is required")
+
+	_, err = readKubernetesClusterSpec(strings.NewReader("name: production\nregion: nyc1\n"), "-")
+	assert.ErrorContains(t, err, "node_pools: at least one node pool is required")
+
+	_, err = readKubernetesClusterSpec(strings.NewReader("name: production\nregion: nyc1\nnode_pool: []\n"), "-")
+	assert.ErrorContains(t, err, `unknown field "node_pool"`)
+}
+
 func TestKubernetesUpdate(t *testing.T) {
 	// by id
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
@@ -651,6 +996,109 @@ func TestKubernetesUpgrade(t *testing.T) {
 	})
 }
 
+func TestKubernetesUpgradeGuided(t *testing.T) {
+	testUpgradeVersion := testClusterUpgrades[0].Slug
+	runID := "50c2f44c-011d-493e-aee5-361a4a0d1844"
+
+	poolWithNodes := func(nodes ...*godo.KubernetesNode) do.KubernetesNodePools {
+		return do.KubernetesNodePools{{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID:    testNodePool.ID,
+			Name:  testNodePool.Name,
+			Nodes: nodes,
+		}}}
+	}
+	running := &godo.KubernetesNodeStatus{State: "running"}
+	oldNode := &godo.KubernetesNode{ID: "old", Name: "pool-old", Status: running}
+	newNode := &godo.KubernetesNode{ID: "new", Name: "pool-new", Status: &godo.KubernetesNodeStatus{State: "provisioning"}}
+	newNodeRunning := &godo.KubernetesNode{ID: "new", Name: "pool-new", Status: running}
+
+	upgraded := do.KubernetesCluster{KubernetesCluster: &godo.KubernetesCluster{
+		ID:          testCluster.ID,
+		Name:        testCluster.Name,
+		VersionSlug: testUpgradeVersion,
+		Status:      &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusRunning},
+	}}
+	upgrading := do.KubernetesCluster{KubernetesCluster: &godo.KubernetesCluster{
+		ID:          testCluster.ID,
+		Name:        testCluster.Name,
+		VersionSlug: testCluster.VersionSlug,
+		Status:      &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusUpgrading},
+	}}
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		defer func(d time.Duration) { clusterUpgradePollInterval = d }(clusterUpgradePollInterval)
+		clusterUpgradePollInterval = 0
+
+		gomock.InOrder(
+			tm.kubernetes.EXPECT().Get(testCluster.ID).Return(&testCluster, nil),
+			tm.kubernetes.EXPECT().GetUpgrades(testCluster.ID).Return(testClusterUpgrades, nil),
+			tm.kubernetes.EXPECT().RunClusterlint(testCluster.ID, &godo.KubernetesRunClusterlintRequest{}).Return(runID, nil),
+			tm.kubernetes.EXPECT().GetClusterlintResults(testCluster.ID, &godo.KubernetesGetClusterlintRequest{RunId: runID}).Return(nil, nil),
+			tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(poolWithNodes(oldNode), nil),
+			tm.kubernetes.EXPECT().Upgrade(testCluster.ID, testUpgradeVersion).Return(nil),
+			tm.kubernetes.EXPECT().Get(testCluster.ID).Return(&upgrading, nil),
+			tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(poolWithNodes(oldNode, newNode), nil),
+			tm.kubernetes.EXPECT().Get(testCluster.ID).Return(&upgraded, nil),
+			tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(poolWithNodes(newNode), nil),
+			tm.kubernetes.EXPECT().Get(testCluster.ID).Return(&upgraded, nil),
+			tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(poolWithNodes(newNodeRunning), nil),
+		)
+
+		var buf bytes.Buffer
+		config.Out = &buf
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgVersion, testUpgradeVersion)
+		config.Doit.Set(config.NS, doctl.ArgClusterUpgradeGuided, true)
+		config.Doit.Set(config.NS, doctl.ArgForce, true)
+
+		err := testK8sCmdService().RunKubernetesClusterUpgrade(config)
+		assert.NoError(t, err)
+
+		out := buf.String()
+		assert.Contains(t, out, "Current version:    1.13.0\n")
+		assert.Contains(t, out, "Target version:     1.13.1-do.1\n")
+		assert.Contains(t, out, "Cluster antoine_s_cluster upgraded to 1.13.1-do.1")
+		assert.Contains(t, out, "antoine_s_pool: 1/1 nodes replaced, 1 running\n")
+	})
+
+	// clusterlint errors block the upgrade unless forced
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().Get(testCluster.ID).Return(&testCluster, nil)
+		tm.kubernetes.EXPECT().GetUpgrades(testCluster.ID).Return(testClusterUpgrades, nil)
+		tm.kubernetes.EXPECT().RunClusterlint(testCluster.ID, &godo.KubernetesRunClusterlintRequest{}).Return(runID, nil)
+		tm.kubernetes.EXPECT().GetClusterlintResults(testCluster.ID, &godo.KubernetesGetClusterlintRequest{RunId: runID}).Return([]*godo.ClusterlintDiagnostic{{
+			CheckName: "admission-controller-webhook",
+			Severity:  "error",
+			Message:   "Webhook matches objects in kube-system",
+		}}, nil)
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgVersion, testUpgradeVersion)
+		config.Doit.Set(config.NS, doctl.ArgClusterUpgradeGuided, true)
+
+		err := testK8sCmdService().RunKubernetesClusterUpgrade(config)
+		assert.EqualError(t, err, "clusterlint reported 1 error(s); fix them or use the `--force` flag to upgrade anyway")
+	})
+}
+
+func TestWaitForClusterUpgradeTimeout(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		defer func(d time.Duration) { clusterUpgradePollInterval = d }(clusterUpgradePollInterval)
+		clusterUpgradePollInterval = 0
+
+		upgrading := do.KubernetesCluster{KubernetesCluster: &godo.KubernetesCluster{
+			ID:          testCluster.ID,
+			VersionSlug: testCluster.VersionSlug,
+			Status:      &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusUpgrading},
+		}}
+		tm.kubernetes.EXPECT().Get(testCluster.ID).Return(&upgrading, nil).Times(480)
+		tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(nil, nil).Times(480)
+
+		_, _, err := waitForClusterUpgrade(tm.kubernetes, testCluster.ID, "1.13.1-do.1", nil)
+		assert.EqualError(t, err, "timeout waiting for cluster ("+testCluster.ID+") to be upgraded to 1.13.1-do.1")
+	})
+}
+
 func TestKubernetesDelete(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		// shouldn't call `DeleteNodePool` so we don't set any expectations
@@ -814,6 +1262,315 @@ func TestKubernetesListAssociatedResources(t *testing.T) {
 	})
 }
 
+func TestKubernetesClusterReport(t *testing.T) {
+	pools := do.KubernetesNodePools{
+		{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID: "pool-1", Name: "web", Size: "s-2vcpu-4gb", Count: 3,
+		}},
+		{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID: "pool-2", Name: "workers", Size: "s-4vcpu-8gb", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 5,
+		}},
+		{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID: "pool-3", Name: "gpu", Size: "gpu-h100x1-80gb", Count: 1,
+		}},
+	}
+	sizes := do.Sizes{
+		{Size: &godo.Size{Slug: "s-2vcpu-4gb", Vcpus: 2, Memory: 4096, PriceMonthly: 24}},
+		{Size: &godo.Size{Slug: "s-4vcpu-8gb", Vcpus: 4, Memory: 8192, PriceMonthly: 48}},
+	}
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().Get(testCluster.ID).Return(&testCluster, nil)
+		tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(pools, nil)
+		tm.kubernetes.EXPECT().ListAssociatedResourcesForDeletion(testCluster.ID).Return(&do.KubernetesAssociatedResources{
+			KubernetesAssociatedResources: &godo.KubernetesAssociatedResources{
+				LoadBalancers: []*godo.AssociatedResource{{ID: "lb-1", Name: "ingress"}},
+				Volumes:       []*godo.AssociatedResource{{ID: "vol-1", Name: "pvc-1"}},
+			},
+		}, nil)
+		tm.sizes.EXPECT().List().Return(sizes, nil)
+		tm.loadBalancers.EXPECT().Get("lb-1").Return(&do.LoadBalancer{LoadBalancer: &godo.LoadBalancer{ID: "lb-1", Name: "ingress", SizeUnit: 2}}, nil)
+		tm.volumes.EXPECT().Get("vol-1").Return(&do.Volume{Volume: &godo.Volume{ID: "vol-1", Name: "pvc-1", SizeGigaBytes: 10}}, nil)
+
+		report, err := kubernetesCostReport(config, testCluster.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, testCluster.Name, report.ClusterName)
+		require.Len(t, report.Items, 6)
+		assert.Equal(t, "control-plane", report.Items[0].Type)
+		assert.Equal(t, 40.0, report.Items[0].PriceMonthly)
+		assert.Equal(t, 72.0, report.Items[1].PriceMonthly)
+		assert.Equal(t, 6, report.Items[1].CapacityVCPUs)
+		assert.Equal(t, 96.0, report.Items[2].PriceMonthly)
+		assert.Equal(t, 48.0, report.Items[2].MinPriceMonthly)
+		assert.Equal(t, 240.0, report.Items[2].MaxPriceMonthly)
+		assert.True(t, report.Items[3].PriceUnknown)
+		assert.Equal(t, 24.0, report.Items[4].PriceMonthly)
+		assert.Equal(t, 1.0, report.Items[5].PriceMonthly)
+
+		assert.InDelta(t, 233.0, report.TotalPriceMonthly, 0.001)
+		assert.InDelta(t, 185.0, report.MinTotalPriceMonthly, 0.001)
+		assert.InDelta(t, 377.0, report.MaxTotalPriceMonthly, 0.001)
+	})
+}
+
+func TestKubernetesClusterLint(t *testing.T) {
+	runID := "50c2f44c-011d-493e-aee5-361a4a0d1844"
+	warning := &godo.ClusterlintDiagnostic{
+		CheckName: "unused-config-map",
+		Severity:  "warning",
+		Message:   "Unused config map",
+		Object:    &godo.ClusterlintObject{Kind: "config map", Name: "foo", Namespace: "kube-system"},
+	}
+	diagnostics := []*godo.ClusterlintDiagnostic{
+		warning,
+		{
+			CheckName: "admission-controller-webhook",
+			Severity:  "error",
+			Message:   "Webhook matches objects in kube-system",
+			Object:    &godo.ClusterlintObject{Kind: "validating webhook configuration", Name: "bar"},
+		},
+	}
+
+	// start a run without waiting
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().RunClusterlint(testCluster.ID, &godo.KubernetesRunClusterlintRequest{
+			IncludeGroups: []string{"doks"},
+			ExcludeChecks: []string{"bare-pods"},
+		}).Return(runID, nil)
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgClusterlintIncludeGroups, []string{"doks"})
+		config.Doit.Set(config.NS, doctl.ArgClusterlintExcludeChecks, []string{"bare-pods"})
+
+		err := testK8sCmdService().RunKubernetesClusterLint(config)
+		assert.NoError(t, err)
+	})
+
+	// wait for the run to finish, failing on errors
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		defer func(d time.Duration) { clusterlintPollInterval = d }(clusterlintPollInterval)
+		clusterlintPollInterval = 0
+
+		notFound := &godo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		req := &godo.KubernetesGetClusterlintRequest{RunId: runID}
+		gomock.InOrder(
+			tm.kubernetes.EXPECT().RunClusterlint(testCluster.ID, &godo.KubernetesRunClusterlintRequest{}).Return(runID, nil),
+			tm.kubernetes.EXPECT().GetClusterlintResults(testCluster.ID, req).Return(nil, notFound),
+			tm.kubernetes.EXPECT().GetClusterlintResults(testCluster.ID, req).Return(diagnostics, nil),
+		)
+
+		var buf bytes.Buffer
+		config.Out = &buf
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgCommandWait, true)
+
+		err := testK8sCmdService().RunKubernetesClusterLint(config)
+		assert.EqualError(t, err, "clusterlint reported 1 error(s)")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[1], "admission-controller-webhook")
+		assert.Contains(t, lines[2], "unused-config-map")
+	})
+
+	// fetch an earlier run
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().GetClusterlintResults(testCluster.ID, &godo.KubernetesGetClusterlintRequest{RunId: runID}).Return([]*godo.ClusterlintDiagnostic{warning}, nil)
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgClusterlintRunID, runID)
+
+		err := testK8sCmdService().RunKubernetesClusterLint(config)
+		assert.NoError(t, err)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgClusterlintRunID, runID)
+		config.Doit.Set(config.NS, doctl.ArgClusterlintIncludeChecks, []string{"bare-pods"})
+
+		err := testK8sCmdService().RunKubernetesClusterLint(config)
+		assert.EqualError(t, err, "--include-checks cannot be combined with --run-id")
+	})
+}
+
+func TestWaitForClusterlintResultsTimeout(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		defer func(d time.Duration) { clusterlintPollInterval = d }(clusterlintPollInterval)
+		clusterlintPollInterval = 0
+
+		notFound := &godo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		tm.kubernetes.EXPECT().GetClusterlintResults(testCluster.ID, &godo.KubernetesGetClusterlintRequest{RunId: "typo"}).Return(nil, notFound).Times(120)
+
+		_, err := waitForClusterlintResults(tm.kubernetes, testCluster.ID, "typo", true)
+		assert.EqualError(t, err, "timeout waiting for clusterlint run typo to finish")
+	})
+}
+
+func TestKubernetesNodePoolApply(t *testing.T) {
+	current := do.KubernetesNodePools{
+		{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID:     "ede2c0d6-41e3-479e-ba60-ad9712272321",
+			Name:   "web",
+			Size:   "s-2vcpu-4gb",
+			Count:  3,
+			Tags:   []string{"k8s", "k8s:" + testCluster.ID, "k8s:worker", "frontend"},
+			Labels: map[string]string{"role": "web"},
+			Nodes:  testNodes,
+		}},
+		{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID:    "ede2c0d6-41e3-479e-ba60-ad9712272322",
+			Name:  "legacy",
+			Size:  "s-1vcpu-2gb",
+			Count: 1,
+		}},
+	}
+	file := filepath.Join(t.TempDir(), "pools.yaml")
+	err := os.WriteFile(file, []byte(`node_pools:
+  - name: web
+    auto_scale: true
+    min_nodes: 2
+    max_nodes: 5
+    tags: [frontend]
+    labels:
+      role: web
+    taints:
+      - key: dedicated
+        value: web
+        effect: NoSchedule
+  - name: workers
+    size: s-4vcpu-8gb
+    count: 2
+`), 0644)
+	require.NoError(t, err)
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		taints := []godo.Taint{{Key: "dedicated", Value: "web", Effect: "NoSchedule"}}
+		gomock.InOrder(
+			tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(current, nil),
+			tm.kubernetes.EXPECT().CreateNodePool(testCluster.ID, &godo.KubernetesNodePoolCreateRequest{
+				Name:  "workers",
+				Size:  "s-4vcpu-8gb",
+				Count: 2,
+			}).Return(&testNodePool, nil),
+			tm.kubernetes.EXPECT().UpdateNodePool(testCluster.ID, current[0].ID, &godo.KubernetesNodePoolUpdateRequest{
+				Name:      "web",
+				Count:     intPtr(3),
+				Tags:      []string{"frontend"},
+				Labels:    map[string]string{"role": "web"},
+				Taints:    &taints,
+				AutoScale: boolPtr(true),
+				MinNodes:  intPtr(2),
+				MaxNodes:  intPtr(5),
+			}).Return(&testNodePool, nil),
+			tm.kubernetes.EXPECT().DeleteNodePool(testCluster.ID, current[1].ID).Return(nil),
+			tm.kubernetes.EXPECT().ListNodePools(testCluster.ID).Return(testNodePools, nil),
+		)
+
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgNodePoolsFile, file)
+		config.Doit.Set(config.NS, doctl.ArgForce, true)
+
+		err := testK8sCmdService().RunKubernetesNodePoolApply(config)
+		assert.NoError(t, err)
+	})
+}
+
+func TestPlanNodePools(t *testing.T) {
+	current := do.KubernetesNodePools{
+		{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID:    "ede2c0d6-41e3-479e-ba60-ad9712272321",
+			Name:  "web",
+			Size:  "s-2vcpu-4gb",
+			Count: 3,
+			Tags:  []string{"k8s", "k8s:worker"},
+		}},
+	}
+
+	plan, err := planNodePools(current, []*godo.KubernetesNodePoolCreateRequest{{Name: "web", Count: 3}})
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+
+	plan, err = planNodePools(current, []*godo.KubernetesNodePoolCreateRequest{{Name: "web", Count: 4}})
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	var buf bytes.Buffer
+	writeNodePoolPlan(&buf, plan)
+	assert.Equal(t, "Update node pool web (ede2c0d6-41e3-479e-ba60-ad9712272321):\n~ web.count: 3 -> 4\n", buf.String())
+
+	taints := []godo.Taint{{Key: "dedicated", Value: "web", Effect: "NoSchedule"}, {Key: "gpu", Value: "true", Effect: "NoExecute"}}
+	plan, err = planNodePools(current, []*godo.KubernetesNodePoolCreateRequest{{Name: "web", Count: 3, Taints: taints}})
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	buf.Reset()
+	writeNodePoolPlan(&buf, plan)
+	assert.Equal(t, `Update node pool web (ede2c0d6-41e3-479e-ba60-ad9712272321):
++ web.taints[dedicated=web:NoSchedule].Effect: NoSchedule
++ web.taints[dedicated=web:NoSchedule].Key: dedicated
++ web.taints[dedicated=web:NoSchedule].Value: web
++ web.taints[gpu=true:NoExecute].Effect: NoExecute
++ web.taints[gpu=true:NoExecute].Key: gpu
++ web.taints[gpu=true:NoExecute].Value: true
+`, buf.String())
+
+	labelled := do.KubernetesNodePools{
+		{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID:     "ede2c0d6-41e3-479e-ba60-ad9712272321",
+			Name:   "web",
+			Size:   "s-2vcpu-4gb",
+			Count:  3,
+			Tags:   []string{"k8s", "k8s:worker", "frontend"},
+			Labels: map[string]string{"role": "web"},
+		}},
+	}
+	plan, err = planNodePools(labelled, []*godo.KubernetesNodePoolCreateRequest{{Name: "web", Count: 3}})
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	assert.Equal(t, []string{}, plan[0].update.Tags)
+	assert.Equal(t, map[string]string{}, plan[0].update.Labels)
+	assert.Equal(t, &[]godo.Taint{}, plan[0].update.Taints)
+	buf.Reset()
+	writeNodePoolPlan(&buf, plan)
+	assert.Equal(t, `Update node pool web (ede2c0d6-41e3-479e-ba60-ad9712272321):
+- web.labels.role: web
+- web.tags: ["frontend"]
+`, buf.String())
+
+	_, err = planNodePools(current, []*godo.KubernetesNodePoolCreateRequest{{Name: "web", Size: "s-4vcpu-8gb", Count: 3}})
+	assert.EqualError(t, err, "node pool web: size cannot be changed from s-2vcpu-4gb to s-4vcpu-8gb; use a new node pool name to replace it")
+
+	_, err = planNodePools(current, []*godo.KubernetesNodePoolCreateRequest{{Name: "web", Count: 3}, {Name: "new", Count: 1}})
+	assert.EqualError(t, err, "node pool new: size is required to create it")
+}
+
+func TestReadNodePoolsFile(t *testing.T) {
+	_, err := readNodePoolsFile(strings.NewReader(`node_pools:
+  - name: a
+    count: 0
+  - name: a
+    count: 1
+    min_nodes: 1
+  - size: s-1vcpu-2gb
+    auto_scale: true
+    min_nodes: 3
+    max_nodes: 2
+`), "-")
+	require.Error(t, err)
+	for _, msg := range []string{
+		"node_pools[0]: count must be at least 1 unless auto_scale is enabled",
+		"node_pools[1]: duplicate node pool name \"a\"",
+		"node_pools[1]: min_nodes and max_nodes require auto_scale",
+		"node_pools[2]: name is required",
+		"node_pools[2]: max_nodes must be at least 1 and no less than min_nodes",
+	} {
+		assert.Contains(t, err.Error(), msg)
+	}
+
+	_, err = readNodePoolsFile(strings.NewReader("node_pools:\n  - name: a\n    nodes: 3\n"), "-")
+	assert.ErrorContains(t, err, `unknown field "nodes"`)
+}
+
 func TestKubernetesNodePool_Get(t *testing.T) {
 	// by id
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
@@ -984,7 +1741,7 @@ func createTestNodePoolUpdateRequest() godo.KubernetesNodePoolUpdateRequest {
 		Name:   testNodePool.Name,
 		Count:  &testNodePool.Count,
 		Tags:   testNodePool.Tags,
-		Labels: map[string]string{},
+		Labels: nil,
 		Taints: nil,
 	}
 }
@@ -1061,6 +1818,29 @@ func TestKubernetesNodePool_Update(t *testing.T) {
 		})
 	})
 
+	t.Run("count only", func(t *testing.T) {
+		withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+			count := 5
+			var got *godo.KubernetesNodePoolUpdateRequest
+			tm.kubernetes.EXPECT().UpdateNodePool(testCluster.ID, testNodePool.ID, gomock.Any()).DoAndReturn(
+				func(_, _ string, r *godo.KubernetesNodePoolUpdateRequest) (*do.KubernetesNodePool, error) {
+					got = r
+					return &testNodePool, nil
+				})
+
+			config.Args = append(config.Args, testCluster.ID, testNodePool.ID)
+
+			config.Doit.Set(config.NS, doctl.ArgNodePoolCount, count)
+
+			err := testK8sCmdService().RunKubernetesNodePoolUpdate(config)
+			assert.NoError(t, err)
+			require.NotNil(t, got)
+			assert.Equal(t, &count, got.Count)
+			assert.Nil(t, got.Tags)
+			assert.Nil(t, got.Labels)
+		})
+	})
+
 	t.Run("with autoscale config", func(t *testing.T) {
 		withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 			testNodePool := testNodePool
@@ -1180,6 +1960,160 @@ func TestKubernetesNodePool_Recycle(t *testing.T) {
 	})
 }
 
+func TestKubernetesNodePool_ReplaceNodes(t *testing.T) {
+	defer func(interval time.Duration, newClientset func(*CmdConfig, string) (kubernetes.Interface, error)) {
+		nodeReplacePollInterval = interval
+		newKubernetesClientset = newClientset
+	}(nodeReplacePollInterval, newKubernetesClientset)
+	nodeReplacePollInterval = 0
+
+	node := func(id, name string) *godo.KubernetesNode {
+		return &godo.KubernetesNode{ID: id, Name: name, Status: &godo.KubernetesNodeStatus{State: "running"}}
+	}
+	k8sNode := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		}
+	}
+	pool := func(nodes ...*godo.KubernetesNode) *do.KubernetesNodePool {
+		return &do.KubernetesNodePool{KubernetesNodePool: &godo.KubernetesNodePool{
+			ID:    testNodePool.ID,
+			Name:  testNodePool.Name,
+			Count: len(nodes),
+			Nodes: nodes,
+		}}
+	}
+	n1, n2, n3, n4 := node("node-1", "pool-1"), node("node-2", "pool-2"), node("node-3", "pool-3"), node("node-4", "pool-4")
+
+	// a pod that was already unschedulable doesn't block the replacement
+	clientset := fake.NewSimpleClientset(k8sNode("pool-1"), k8sNode("pool-2"), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "too-big", Namespace: "default", UID: "too-big"},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	})
+	newKubernetesClientset = func(*CmdConfig, string) (kubernetes.Interface, error) {
+		return clientset, nil
+	}
+	replace := func(oldName, newName string) func(string, string, string, *godo.KubernetesNodeDeleteRequest) error {
+		return func(string, string, string, *godo.KubernetesNodeDeleteRequest) error {
+			ctx := context.Background()
+			require.NoError(t, clientset.CoreV1().Nodes().Delete(ctx, oldName, metav1.DeleteOptions{}))
+			_, err := clientset.CoreV1().Nodes().Create(ctx, k8sNode(newName), metav1.CreateOptions{})
+			return err
+		}
+	}
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		req := &godo.KubernetesNodeDeleteRequest{Replace: true}
+		gomock.InOrder(
+			tm.kubernetes.EXPECT().GetNodePool(testCluster.ID, testNodePool.ID).Return(pool(n1, n2), nil),
+			tm.kubernetes.EXPECT().DeleteNode(testCluster.ID, testNodePool.ID, n1.ID, req).DoAndReturn(replace("pool-1", "pool-3")),
+			tm.kubernetes.EXPECT().GetNodePool(testCluster.ID, testNodePool.ID).Return(pool(n2, n3), nil),
+			tm.kubernetes.EXPECT().DeleteNode(testCluster.ID, testNodePool.ID, n2.ID, req).DoAndReturn(replace("pool-2", "pool-4")),
+			tm.kubernetes.EXPECT().GetNodePool(testCluster.ID, testNodePool.ID).Return(pool(n3), nil),
+			tm.kubernetes.EXPECT().GetNodePool(testCluster.ID, testNodePool.ID).Return(pool(n3, n4), nil),
+		)
+
+		config.Args = append(config.Args, testCluster.ID, testNodePool.ID)
+		config.Doit.Set(config.NS, doctl.ArgNodePoolReplaceAll, true)
+		config.Doit.Set(config.NS, doctl.ArgNodePoolMaxUnavailable, 1)
+		config.Doit.Set(config.NS, doctl.ArgForce, true)
+
+		err := testK8sCmdService().RunKubernetesNodePoolReplaceNodes(config)
+		assert.NoError(t, err)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.kubernetes.EXPECT().GetNodePool(testCluster.ID, testNodePool.ID).Return(pool(n3, n4), nil)
+
+		config.Args = append(config.Args, testCluster.ID, testNodePool.ID)
+		config.Doit.Set(config.NS, doctl.ArgNodePoolNodeIDs, []string{"pool-5"})
+		config.Doit.Set(config.NS, doctl.ArgNodePoolMaxUnavailable, 1)
+		config.Doit.Set(config.NS, doctl.ArgForce, true)
+
+		err := testK8sCmdService().RunKubernetesNodePoolReplaceNodes(config)
+		assert.EqualError(t, err, `no node with ID or name "pool-5" in node pool antoine_s_pool`)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Args = append(config.Args, testCluster.ID, testNodePool.ID)
+		config.Doit.Set(config.NS, doctl.ArgNodePoolReplaceAll, true)
+		config.Doit.Set(config.NS, doctl.ArgNodePoolNodeIDs, []string{"pool-3"})
+
+		err := testK8sCmdService().RunKubernetesNodePoolReplaceNodes(config)
+		assert.EqualError(t, err, "specify either --all or --node-ids")
+	})
+}
+
+func TestNodeReplacementProgress(t *testing.T) {
+	old := &godo.KubernetesNode{ID: "node-1", Name: "pool-1"}
+	replacement := &godo.KubernetesNode{ID: "node-3", Name: "pool-3", Status: &godo.KubernetesNodeStatus{State: "running"}}
+	other := &godo.KubernetesNode{ID: "node-2", Name: "pool-2", Status: &godo.KubernetesNodeStatus{State: "running"}}
+	known := map[string]bool{old.ID: true, other.ID: true}
+	ready := func(name string, status corev1.ConditionStatus) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}},
+		}
+	}
+	pool := func(nodes ...*godo.KubernetesNode) *do.KubernetesNodePool {
+		return &do.KubernetesNodePool{KubernetesNodePool: &godo.KubernetesNodePool{Nodes: nodes}}
+	}
+
+	tests := []struct {
+		name     string
+		pool     *do.KubernetesNodePool
+		nodes    []corev1.Node
+		pending  map[types.UID]bool
+		done     bool
+		progress string
+	}{
+		{
+			name:     "draining",
+			pool:     pool(old, other),
+			nodes:    []corev1.Node{ready("pool-1", corev1.ConditionTrue), ready("pool-2", corev1.ConditionTrue)},
+			progress: "0/1 old nodes removed, 0/1 new nodes ready, 0 pod(s) waiting to be scheduled",
+		},
+		{
+			name:     "still registered in kubernetes",
+			pool:     pool(other, replacement),
+			nodes:    []corev1.Node{ready("pool-1", corev1.ConditionFalse), ready("pool-2", corev1.ConditionTrue), ready("pool-3", corev1.ConditionTrue)},
+			progress: "0/1 old nodes removed, 1/1 new nodes ready, 0 pod(s) waiting to be scheduled",
+		},
+		{
+			name:     "new node not ready",
+			pool:     pool(other, replacement),
+			nodes:    []corev1.Node{ready("pool-2", corev1.ConditionTrue), ready("pool-3", corev1.ConditionFalse)},
+			progress: "1/1 old nodes removed, 0/1 new nodes ready, 0 pod(s) waiting to be scheduled",
+		},
+		{
+			name:     "pods waiting",
+			pool:     pool(other, replacement),
+			nodes:    []corev1.Node{ready("pool-2", corev1.ConditionTrue), ready("pool-3", corev1.ConditionTrue)},
+			pending:  map[types.UID]bool{"evicted": true, "already-pending": true},
+			progress: "1/1 old nodes removed, 1/1 new nodes ready, 1 pod(s) waiting to be scheduled",
+		},
+		{
+			name:     "done",
+			pool:     pool(other, replacement),
+			nodes:    []corev1.Node{ready("pool-2", corev1.ConditionTrue), ready("pool-3", corev1.ConditionTrue)},
+			pending:  map[types.UID]bool{"already-pending": true},
+			done:     true,
+			progress: "1/1 old nodes removed, 1/1 new nodes ready, 0 pod(s) waiting to be scheduled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, progress := nodeReplacementProgress(tt.pool, tt.nodes, []*godo.KubernetesNode{old}, known, tt.pending, map[types.UID]bool{"already-pending": true})
+			assert.Equal(t, tt.done, done)
+			assert.Equal(t, tt.progress, progress)
+		})
+	}
+}
+
 func TestKubernetesNodePool_Delete(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		// shouldn't call `DeleteNodePool` so we don't set any expectations
@@ -1307,6 +2241,120 @@ func TestKubernetes_DOCRIntegration(t *testing.T) {
 	})
 }
 
+func TestKubernetesRegistryStatus(t *testing.T) {
+	defer func(newClientset func(*CmdConfig, string) (kubernetes.Interface, error)) {
+		newKubernetesClientset = newClientset
+	}(newKubernetesClientset)
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "jobs"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry-" + testRegistryName, Namespace: "default"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry-" + testRegistryName, Namespace: "apps"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "jobs"}},
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "default"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-" + testRegistryName}},
+		},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "apps"}},
+	)
+	newKubernetesClientset = func(*CmdConfig, string) (kubernetes.Interface, error) {
+		return clientset, nil
+	}
+
+	running := do.KubernetesCluster{KubernetesCluster: &godo.KubernetesCluster{
+		ID:              testCluster.ID,
+		Name:            testCluster.Name,
+		RegistryEnabled: true,
+		Status:          &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusRunning},
+	}}
+	provisioning := do.KubernetesCluster{KubernetesCluster: &godo.KubernetesCluster{
+		ID:     "ede2c0d6-41e3-479e-ba60-ad9712272324",
+		Name:   "new_cluster",
+		Status: &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusProvisioning},
+	}}
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.registry.EXPECT().Get().Return(&testRegistry, nil)
+		tm.kubernetes.EXPECT().List().Return(do.KubernetesClusters{running, provisioning}, nil)
+
+		var out bytes.Buffer
+		config.Out = &out
+
+		err := testK8sCmdService().RunKubernetesRegistryStatus(config)
+		require.NoError(t, err)
+		assert.Equal(t, `ID                                      Name                 Registry Enabled    Secret                         Namespaces With Secret             Default Service Accounts Using Secret
+cde2c0d6-41e3-479e-ba60-ad971227232b    antoine_s_cluster    true                registry-container-registry    apps,default                       default
+ede2c0d6-41e3-479e-ba60-ad9712272324    new_cluster          false               registry-container-registry    unknown: cluster is not running    unknown
+`, out.String())
+	})
+}
+
+func TestKubernetesRegistrySync(t *testing.T) {
+	defer func(newClientset func(*CmdConfig, string) (kubernetes.Interface, error)) {
+		newKubernetesClientset = newClientset
+	}(newKubernetesClientset)
+
+	secretName := "registry-" + testRegistryName
+	clientset := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "default"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{".dockerconfigjson": []byte("expired")},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "default"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: secretName}},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "apps"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "other"}},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "jobs"}, Type: corev1.SecretTypeOpaque},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "jobs"}},
+	)
+	newKubernetesClientset = func(*CmdConfig, string) (kubernetes.Interface, error) {
+		return clientset, nil
+	}
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.registry.EXPECT().Get().Return(&testRegistry, nil)
+		tm.registry.EXPECT().DockerCredentials(&godo.RegistryDockerCredentialsRequest{ReadWrite: false}).
+			Return(&godo.DockerCredentials{DockerConfigJSON: []byte("fresh")}, nil)
+
+		var out bytes.Buffer
+		config.Out = &out
+		config.Args = append(config.Args, testCluster.ID)
+		config.Doit.Set(config.NS, doctl.ArgRegistryNamespaces, []string{"default", "apps", "jobs"})
+
+		err := testK8sCmdService().RunKubernetesRegistrySync(config)
+		assert.EqualError(t, err, "1 error occurred:\n\t* namespace jobs: secret "+secretName+" already exists with type Opaque\n\n")
+		assert.Equal(t, "Synced secret "+secretName+" to namespace default\nSynced secret "+secretName+" to namespace apps\n", out.String())
+
+		ctx := context.Background()
+		for _, ns := range []string{"default", "apps"} {
+			secret, err := clientset.CoreV1().Secrets(ns).Get(ctx, secretName, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, []byte("fresh"), secret.Data[".dockerconfigjson"])
+		}
+
+		sa, err := clientset.CoreV1().ServiceAccounts("default").Get(ctx, "default", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []corev1.LocalObjectReference{{Name: secretName}}, sa.ImagePullSecrets)
+		sa, err = clientset.CoreV1().ServiceAccounts("apps").Get(ctx, "default", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []corev1.LocalObjectReference{{Name: "other"}, {Name: secretName}}, sa.ImagePullSecrets)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Args = append(config.Args, testCluster.ID)
+
+		err := testK8sCmdService().RunKubernetesRegistrySync(config)
+		assert.Error(t, err)
+	})
+}
+
 type nilCluster struct {
 	do.KubernetesService
 }
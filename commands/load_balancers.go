@@ -14,10 +14,15 @@ limitations under the License.
 package commands
 
 import (
+	"bytes"
 	_ "embed"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -26,7 +31,9 @@ import (
 	"github.com/digitalocean/doctl/commands/displayers"
 	"github.com/digitalocean/doctl/do"
 	"github.com/digitalocean/godo"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -50,15 +57,45 @@ With the load-balancer command, you can list, create, or delete load balancers,
 	}
 
 	forwardingRulesTxt := "A comma-separated list of key-value pairs representing forwarding rules, which define how traffic is routed, e.g.: `entry_protocol:tcp,entry_port:3306,target_protocol:tcp,target_port:3306`."
-	CmdBuilder(cmd, RunLoadBalancerGet, "get <id>", "Retrieve a load balancer", "Use this command to retrieve information about a load balancer instance, including:\n\n"+lbDetail, Writer,
+	specTxt := "Path to a YAML or JSON load balancer spec; use `-` to read from stdin. When used, the other configuration flags are ignored"
+	specDetail := `
+
+Instead of flags, the load balancer's configuration can be provided as a YAML or JSON spec using the ` + "`" + `--spec` + "`" + ` flag. The spec uses the same fields as the ` + "`" + `load_balancers` + "`" + ` API request, for example:
+
+    name: web
+    region: nyc1
+    size_unit: 1
+    tag: web
+    forwarding_rules:
+      - entry_protocol: https
+        entry_port: 443
+        target_protocol: http
+        target_port: 8080
+        certificate_id: 892071a0-bb95-49bc-8021-3afd67a210bf
+    health_check:
+      protocol: http
+      port: 8080
+      path: /healthz
+      check_interval_seconds: 10
+      response_timeout_seconds: 5
+      healthy_threshold: 5
+      unhealthy_threshold: 3
+    sticky_sessions:
+      type: none
+
+Use ` + "`" + `doctl compute load-balancer get <id> --spec` + "`" + ` to export the spec of an existing load balancer.`
+
+	cmdRecordGet := CmdBuilder(cmd, RunLoadBalancerGet, "get <id>", "Retrieve a load balancer", "Use this command to retrieve information about a load balancer instance, including:\n\n"+lbDetail+"\n\nUse the `--spec` flag to output the load balancer's configuration as a spec that can be used with the `create` and `update` commands.", Writer,
 		aliasOpt("g"), displayerType(&displayers.LoadBalancer{}))
+	AddBoolFlag(cmdRecordGet, doctl.ArgLoadBalancerSpec, "", false,
+		"Output the load balancer's configuration as a YAML spec, or JSON with `--output json`")
 
 	cmdRecordCreate := CmdBuilder(cmd, RunLoadBalancerCreate, "create",
-		"Create a new load balancer", "Use this command to create a new load balancer on your account. Valid forwarding rules are:\n"+forwardingDetail, Writer, aliasOpt("c"))
+		"Create a new load balancer", "Use this command to create a new load balancer on your account. Valid forwarding rules are:\n"+forwardingDetail+specDetail, Writer, aliasOpt("c"))
 	AddStringFlag(cmdRecordCreate, doctl.ArgLoadBalancerName, "", "",
-		"The load balancer's name", requiredOpt())
+		"The load balancer's name; required unless `--spec` is used")
 	AddStringFlag(cmdRecordCreate, doctl.ArgRegionSlug, "", "",
-		"The load balancer's region, e.g.: `nyc1`", requiredOpt())
+		"The load balancer's region, e.g.: `nyc1`; required unless `--spec` is used")
 	AddStringFlag(cmdRecordCreate, doctl.ArgSizeSlug, "", "",
 		fmt.Sprintf("The load balancer's size, e.g.: `lb-small`. Only one of %s and %s should be used", doctl.ArgSizeSlug, doctl.ArgSizeUnit))
 	AddIntFlag(cmdRecordCreate, doctl.ArgSizeUnit, "", 0,
@@ -90,9 +127,12 @@ With the load-balancer command, you can list, create, or delete load balancers,
 		"A comma-separated list of ALLOW rules for the load balancer, e.g.: `ip:1.2.3.4,cidr:1.2.0.0/16`")
 	AddStringSliceFlag(cmdRecordCreate, doctl.ArgDenyList, "", []string{},
 		"A comma-separated list of DENY rules for the load balancer, e.g.: `ip:1.2.3.4,cidr:1.2.0.0/16`")
+	AddStringFlag(cmdRecordCreate, doctl.ArgLoadBalancerSpec, "", "", specTxt)
 
 	cmdRecordUpdate := CmdBuilder(cmd, RunLoadBalancerUpdate, "update <id>",
-		"Update a load balancer's configuration", `Use this command to update the configuration of a specified load balancer.`, Writer, aliasOpt("u"))
+		"Update a load balancer's configuration", `Use this command to update the configuration of a specified load balancer.`+specDetail+`
+
+When updating with `+"`"+`--spec`+"`"+`, the spec may be partial: its fields are merged into the load balancer's current configuration. Forwarding rules are matched by their entry protocol and port, so rules not mentioned in the spec are kept; use `+"`"+`remove-forwarding-rules`+"`"+` to remove one. A field can be cleared by setting it to `+"`"+`null`+"`"+`. The changes are shown before they are applied. Use the `+"`"+`--prompt`+"`"+` flag to confirm the changes before they are applied.`, Writer, aliasOpt("u"))
 	AddStringFlag(cmdRecordUpdate, doctl.ArgLoadBalancerName, "", "",
 		"The load balancer's name")
 	AddStringFlag(cmdRecordUpdate, doctl.ArgRegionSlug, "", "",
@@ -126,10 +166,26 @@ With the load-balancer command, you can list, create, or delete load balancers,
 		"A comma-separated list of ALLOW rules for the load balancer, e.g.: `ip:1.2.3.4,cidr:1.2.0.0/16`")
 	AddStringSliceFlag(cmdRecordUpdate, doctl.ArgDenyList, "", []string{},
 		"A comma-separated list of DENY rules for the load balancer, e.g.: `ip:1.2.3.4,cidr:1.2.0.0/16`")
+	AddStringFlag(cmdRecordUpdate, doctl.ArgLoadBalancerSpec, "", "", "Path to a YAML or JSON spec containing the fields to change; use `-` to read from stdin. When used, the other configuration flags are ignored")
+	AddBoolFlag(cmdRecordUpdate, doctl.ArgLoadBalancerPrompt, "", false, "Confirm the changes from `--spec` before applying them")
 
 	CmdBuilder(cmd, RunLoadBalancerList, "list", "List load balancers", "Use this command to get a list of the load balancers on your account, including the following information for each:\n\n"+lbDetail, Writer,
 		aliasOpt("ls"), displayerType(&displayers.LoadBalancer{}))
 
+	cmdRecordStatus := CmdBuilder(cmd, RunLoadBalancerStatus, "status <id>", "Show the status of a load balancer's backends and rules",
+		`Use this command to show the status of a load balancer together with each Droplet behind it and each of its forwarding rules.
+
+For forwarding rules that terminate TLS, the certificate's expiry date is shown. The rule's status is `+"`"+`expiring`+"`"+` when its certificate expires within 30 days, and `+"`"+`expired`+"`"+` once it has.
+
+The API does not report the results of individual health checks, so the status shown for a backend is the status of its Droplet. An `+"`"+`active`+"`"+` Droplet may still be failing the load balancer's health check.
+
+Use the `+"`"+`--watch`+"`"+` flag to refresh the status every `+"`"+`--interval`+"`"+` seconds. Changes since the previous refresh are highlighted. Combined with `+"`"+`add-droplets`+"`"+` and `+"`"+`remove-droplets`+"`"+`, this lets you follow a blue/green swap as it happens.`, Writer,
+		displayerType(&displayers.LoadBalancerStatus{}))
+	AddBoolFlag(cmdRecordStatus, doctl.ArgLoadBalancerWatch, "", false,
+		"Keep refreshing the status and highlight changes until interrupted")
+	AddIntFlag(cmdRecordStatus, doctl.ArgLoadBalancerWatchInterval, "", 10,
+		"Number of seconds between refreshes when using `--watch`")
+
 	cmdRunRecordDelete := CmdBuilder(cmd, RunLoadBalancerDelete, "delete <id>",
 		"Permanently delete a load balancer", `Use this command to permanently delete the specified load balancer. This is irreversible.`, Writer, aliasOpt("d", "rm"))
 	AddBoolFlag(cmdRunRecordDelete, doctl.ArgForce, doctl.ArgShortForce, false,
@@ -164,16 +220,78 @@ func RunLoadBalancerGet(c *CmdConfig) error {
 	}
 	id := c.Args[0]
 
+	spec, err := c.Doit.GetBool(c.NS, doctl.ArgLoadBalancerSpec)
+	if err != nil {
+		return err
+	}
+
 	lbs := c.LoadBalancers()
 	lb, err := lbs.Get(id)
 	if err != nil {
 		return err
 	}
 
+	if spec {
+		return writeSpec(c.Out, loadBalancerSpec(lb), Output)
+	}
+
 	item := &displayers.LoadBalancer{LoadBalancers: do.LoadBalancers{*lb}}
 	return c.Display(item)
 }
 
+// RunLoadBalancerStatus shows the status of a load balancer's backends and
+// forwarding rules, optionally refreshing it until interrupted.
+func RunLoadBalancerStatus(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+	id := c.Args[0]
+
+	watch, err := c.Doit.GetBool(c.NS, doctl.ArgLoadBalancerWatch)
+	if err != nil {
+		return err
+	}
+	interval, err := c.Doit.GetInt(c.NS, doctl.ArgLoadBalancerWatchInterval)
+	if err != nil {
+		return err
+	}
+	if watch && interval < 1 {
+		return fmt.Errorf("--%s must be at least 1 second", doctl.ArgLoadBalancerWatchInterval)
+	}
+	if watch && Output == "json" {
+		// each refresh would write a separate JSON document
+		return fmt.Errorf("--%s cannot be used with JSON output", doctl.ArgLoadBalancerWatch)
+	}
+
+	var previous *displayers.LoadBalancerStatusReport
+	for {
+		report, err := loadBalancerStatus(c, id, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if watch {
+			fmt.Fprintf(c.Out, "%s\n", time.Now().Format(time.RFC1123))
+		}
+		if err := c.Display(&displayers.LoadBalancerStatus{Report: report}); err != nil {
+			return err
+		}
+		if previous != nil {
+			for _, t := range loadBalancerStatusTransitions(previous, report) {
+				notice("%s", t)
+			}
+		}
+
+		if !watch {
+			return nil
+		}
+		previous = report
+		fmt.Fprintln(c.Out)
+		time.Sleep(time.Duration(interval) * time.Second)
+	}
+}
+
 // RunLoadBalancerList lists load balancers.
 func RunLoadBalancerList(c *CmdConfig) error {
 	lbs := c.LoadBalancers()
@@ -188,11 +306,28 @@ func RunLoadBalancerList(c *CmdConfig) error {
 
 // RunLoadBalancerCreate creates a new load balancer with a given configuration.
 func RunLoadBalancerCreate(c *CmdConfig) error {
+	specPath, err := c.Doit.GetString(c.NS, doctl.ArgLoadBalancerSpec)
+	if err != nil {
+		return err
+	}
+
 	r := new(godo.LoadBalancerRequest)
-	if err := buildRequestFromArgs(c, r); err != nil {
+	if specPath != "" {
+		_, r, err = readLoadBalancerSpec(os.Stdin, specPath)
+		if err != nil {
+			return err
+		}
+	} else if err := buildRequestFromArgs(c, r); err != nil {
 		return err
 	}
 
+	if r.Name == "" {
+		return doctl.NewMissingArgsErr(fmt.Sprintf("%s.%s", c.NS, doctl.ArgLoadBalancerName))
+	}
+	if r.Region == "" {
+		return doctl.NewMissingArgsErr(fmt.Sprintf("%s.%s", c.NS, doctl.ArgRegionSlug))
+	}
+
 	lbs := c.LoadBalancers()
 	lb, err := lbs.Create(r)
 	if err != nil {
@@ -232,12 +367,54 @@ func RunLoadBalancerUpdate(c *CmdConfig) error {
 	}
 	lbID := c.Args[0]
 
-	r := new(godo.LoadBalancerRequest)
-	if err := buildRequestFromArgs(c, r); err != nil {
+	specPath, err := c.Doit.GetString(c.NS, doctl.ArgLoadBalancerSpec)
+	if err != nil {
 		return err
 	}
 
 	lbs := c.LoadBalancers()
+	r := new(godo.LoadBalancerRequest)
+	if specPath != "" {
+		prompt, err := c.Doit.GetBool(c.NS, doctl.ArgLoadBalancerPrompt)
+		if err != nil {
+			return err
+		}
+
+		patch, _, err := readLoadBalancerSpec(os.Stdin, specPath)
+		if err != nil {
+			return err
+		}
+
+		live, err := lbs.Get(lbID)
+		if err != nil {
+			return err
+		}
+
+		current := loadBalancerSpec(live)
+		r, err = mergeLoadBalancerSpec(current, patch)
+		if err != nil {
+			return err
+		}
+
+		changes, err := diffFields(current, r, loadBalancerSpecKeys)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(color.Output, "Changes to load balancer %s (%s):\n", live.Name, live.ID)
+		writeFieldChanges(color.Output, changes)
+		if len(changes) == 0 {
+			item := &displayers.LoadBalancer{LoadBalancers: do.LoadBalancers{*live}}
+			return c.Display(item)
+		}
+
+		if prompt && AskForConfirm("apply these changes to the load balancer?") != nil {
+			return errOperationAborted
+		}
+	} else if err := buildRequestFromArgs(c, r); err != nil {
+		return err
+	}
+
 	lb, err := lbs.Update(lbID, r)
 	if err != nil {
 		return err
@@ -568,6 +745,103 @@ func buildRequestFromArgs(c *CmdConfig, r *godo.LoadBalancerRequest) error {
 	return nil
 }
 
+// loadBalancerSpecKeys identifies forwarding rules by their entry protocol and
+// port when diffing and merging load balancer specs.
+var loadBalancerSpecKeys = map[string]elementKeyFn{
+	"forwarding_rules": func(m map[string]interface{}) string {
+		return fmt.Sprintf("%v:%v", m["entry_protocol"], m["entry_port"])
+	},
+}
+
+// readLoadBalancerSpec reads a YAML or JSON load balancer spec, returning both
+// its raw fields and the request it describes. A path of "-" reads from stdin.
+func readLoadBalancerSpec(stdin io.Reader, path string) (map[string]interface{}, *godo.LoadBalancerRequest, error) {
+	var r io.Reader
+	if path == "-" && stdin != nil {
+		r = stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil, fmt.Errorf("opening load balancer spec: %s does not exist", path)
+			}
+			return nil, nil, fmt.Errorf("opening load balancer spec: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading load balancer spec: %w", err)
+	}
+
+	jsonSpec, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing load balancer spec: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonSpec))
+	dec.DisallowUnknownFields()
+
+	var req godo.LoadBalancerRequest
+	if err := dec.Decode(&req); err != nil {
+		return nil, nil, fmt.Errorf("parsing load balancer spec: %w", err)
+	}
+
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(jsonSpec, &raw); err != nil {
+		return nil, nil, fmt.Errorf("parsing load balancer spec: %w", err)
+	}
+
+	return raw, &req, nil
+}
+
+// loadBalancerSpec returns the configuration of a load balancer as a request
+// that can be used to recreate or update it.
+func loadBalancerSpec(lb *do.LoadBalancer) *godo.LoadBalancerRequest {
+	r := lb.AsRequest()
+	r.Tags = lb.Tags
+	r.ValidateOnly = false
+	// The algorithm is deprecated and ignored by the API.
+	r.Algorithm = ""
+	// Droplet IDs are derived from the tag when one is set, and the two are
+	// mutually exclusive in a request. The same goes for size and size unit.
+	if r.Tag != "" {
+		r.DropletIDs = nil
+	}
+	if r.SizeUnit > 0 {
+		r.SizeSlug = ""
+	}
+	return r
+}
+
+// mergeLoadBalancerSpec merges the fields of a partial spec into the current
+// configuration of a load balancer.
+func mergeLoadBalancerSpec(current *godo.LoadBalancerRequest, patch map[string]interface{}) (*godo.LoadBalancerRequest, error) {
+	base, err := toJSONObject(current)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusive := [][2]string{{"tag", "droplet_ids"}, {"size", "size_unit"}}
+	for _, pair := range exclusive {
+		if _, ok := patch[pair[0]]; ok {
+			delete(base, pair[1])
+		}
+		if _, ok := patch[pair[1]]; ok {
+			delete(base, pair[0])
+		}
+	}
+
+	merged := new(godo.LoadBalancerRequest)
+	if err := fromJSONObject(mergeJSONFields(base, patch, loadBalancerSpecKeys), merged); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
 func waitForActiveLoadBalancer(lbs do.LoadBalancersService, lbID string) error {
 	const maxAttempts = 180
 	const wantStatus = "active"
@@ -609,3 +883,183 @@ func waitForActiveLoadBalancer(lbs do.LoadBalancersService, lbID string) error {
 		lbID,
 	)
 }
+
+// lbCertExpiryWarningDays is how close to its expiry a certificate is
+// reported as expiring.
+const lbCertExpiryWarningDays = 30
+
+// loadBalancerStatus collects the status of a load balancer, the Droplets
+// behind it and its forwarding rules.
+func loadBalancerStatus(c *CmdConfig, id string, now time.Time) (*displayers.LoadBalancerStatusReport, error) {
+	lb, err := c.LoadBalancers().Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &displayers.LoadBalancerStatusReport{
+		ID:              lb.ID,
+		Name:            lb.Name,
+		IP:              lb.IP,
+		Status:          lb.Status,
+		Backends:        []displayers.LoadBalancerBackendStatus{},
+		ForwardingRules: []displayers.LoadBalancerRuleStatus{},
+	}
+	if hc := lb.HealthCheck; hc != nil {
+		report.HealthCheck = fmt.Sprintf("health_check:%s:%d%s", hc.Protocol, hc.Port, hc.Path)
+	}
+
+	ds := c.Droplets()
+	var droplets do.Droplets
+	if len(lb.DropletIDs) == 0 && lb.Tag != "" {
+		droplets, err = ds.ListByTag(lb.Tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(lb.DropletIDs) > 0 {
+		all, err := ds.List()
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[int]do.Droplet, len(all))
+		for _, d := range all {
+			byID[d.ID] = d
+		}
+
+		for _, dropletID := range lb.DropletIDs {
+			d, ok := byID[dropletID]
+			if !ok {
+				warn("Unable to find Droplet %d", dropletID)
+				report.Backends = append(report.Backends, displayers.LoadBalancerBackendStatus{
+					DropletID: dropletID,
+					Status:    "unknown",
+				})
+				continue
+			}
+			droplets = append(droplets, d)
+		}
+	}
+	for _, d := range droplets {
+		publicIP, _ := d.PublicIPv4()
+		privateIP, _ := d.PrivateIPv4()
+		report.Backends = append(report.Backends, displayers.LoadBalancerBackendStatus{
+			DropletID:   d.ID,
+			DropletName: d.Name,
+			Status:      d.Status,
+			PublicIPv4:  publicIP,
+			PrivateIPv4: privateIP,
+		})
+	}
+	sort.SliceStable(report.Backends, func(i, j int) bool {
+		return report.Backends[i].DropletID < report.Backends[j].DropletID
+	})
+
+	certs := map[string]*do.Certificate{}
+	for _, fr := range lb.ForwardingRules {
+		rs := displayers.LoadBalancerRuleStatus{
+			Rule:          fmt.Sprintf("%s:%d -> %s:%d", fr.EntryProtocol, fr.EntryPort, fr.TargetProtocol, fr.TargetPort),
+			Status:        "ok",
+			CertificateID: fr.CertificateID,
+		}
+
+		if fr.CertificateID != "" {
+			cert, ok := certs[fr.CertificateID]
+			if !ok {
+				cert, err = c.Certificates().Get(fr.CertificateID)
+				if err != nil {
+					warn("Unable to retrieve certificate %s: %v", fr.CertificateID, err)
+				}
+				certs[fr.CertificateID] = cert
+			}
+			rs.Status = "unknown"
+			if cert != nil {
+				rs.CertificateName = cert.Name
+				rs.NotAfter = cert.NotAfter
+				rs.Status = certificateExpiryStatus(cert, now, &rs)
+			}
+		}
+
+		report.ForwardingRules = append(report.ForwardingRules, rs)
+	}
+
+	return report, nil
+}
+
+// certificateExpiryStatus returns the status of a forwarding rule based on
+// its certificate's expiry, filling in the number of days left.
+func certificateExpiryStatus(cert *do.Certificate, now time.Time, rs *displayers.LoadBalancerRuleStatus) string {
+	notAfter, err := time.Parse(time.RFC3339, cert.NotAfter)
+	if err != nil {
+		return "unknown"
+	}
+
+	daysLeft := int(notAfter.Sub(now).Hours() / 24)
+	rs.DaysLeft = &daysLeft
+
+	switch {
+	case !now.Before(notAfter):
+		return "expired"
+	case daysLeft < lbCertExpiryWarningDays:
+		return "expiring"
+	default:
+		return "ok"
+	}
+}
+
+// loadBalancerStatusTransitions describes what changed between two status
+// reports of the same load balancer.
+func loadBalancerStatusTransitions(previous, current *displayers.LoadBalancerStatusReport) []string {
+	var transitions []string
+
+	if previous.Status != current.Status {
+		transitions = append(transitions, fmt.Sprintf("load balancer %s: %s -> %s", current.Name, previous.Status, current.Status))
+	}
+
+	backendName := func(b displayers.LoadBalancerBackendStatus) string {
+		if b.DropletName == "" {
+			return fmt.Sprintf("droplet %d", b.DropletID)
+		}
+		return fmt.Sprintf("droplet %s (%d)", b.DropletName, b.DropletID)
+	}
+	before := make(map[int]displayers.LoadBalancerBackendStatus, len(previous.Backends))
+	for _, b := range previous.Backends {
+		before[b.DropletID] = b
+	}
+	for _, b := range current.Backends {
+		old, ok := before[b.DropletID]
+		delete(before, b.DropletID)
+		switch {
+		case !ok:
+			transitions = append(transitions, fmt.Sprintf("%s: added (%s)", backendName(b), b.Status))
+		case old.Status != b.Status:
+			transitions = append(transitions, fmt.Sprintf("%s: %s -> %s", backendName(b), old.Status, b.Status))
+		}
+	}
+	for _, b := range previous.Backends {
+		if _, ok := before[b.DropletID]; ok {
+			transitions = append(transitions, fmt.Sprintf("%s: removed", backendName(b)))
+		}
+	}
+
+	rulesBefore := make(map[string]string, len(previous.ForwardingRules))
+	for _, fr := range previous.ForwardingRules {
+		rulesBefore[fr.Rule] = fr.Status
+	}
+	for _, fr := range current.ForwardingRules {
+		old, ok := rulesBefore[fr.Rule]
+		delete(rulesBefore, fr.Rule)
+		switch {
+		case !ok:
+			transitions = append(transitions, fmt.Sprintf("rule %s: added (%s)", fr.Rule, fr.Status))
+		case old != fr.Status:
+			transitions = append(transitions, fmt.Sprintf("rule %s: %s -> %s", fr.Rule, old, fr.Status))
+		}
+	}
+	for _, fr := range previous.ForwardingRules {
+		if _, ok := rulesBefore[fr.Rule]; ok {
+			transitions = append(transitions, fmt.Sprintf("rule %s: removed", fr.Rule))
+		}
+	}
+
+	return transitions
+}
@@ -1,9 +1,14 @@
 package commands
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/commands/displayers"
 	"github.com/digitalocean/doctl/do"
 	"github.com/digitalocean/godo"
 
@@ -30,7 +35,7 @@ var (
 func TestLoadBalancerCommand(t *testing.T) {
 	cmd := LoadBalancer()
 	assert.NotNil(t, cmd)
-	assertCommandNames(t, cmd, "get", "list", "create", "update", "delete", "add-droplets", "remove-droplets", "add-forwarding-rules", "remove-forwarding-rules")
+	assertCommandNames(t, cmd, "get", "list", "create", "update", "delete", "status", "add-droplets", "remove-droplets", "add-forwarding-rules", "remove-forwarding-rules")
 }
 
 func TestLoadBalancerGet(t *testing.T) {
@@ -45,6 +50,173 @@ func TestLoadBalancerGet(t *testing.T) {
 	})
 }
 
+func TestLoadBalancerStatus(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		lbID := "cde2c0d6-41e3-479e-ba60-ad971227232c"
+		certID := "892071a0-bb95-49bc-8021-3afd67a210bf"
+		lb := do.LoadBalancer{LoadBalancer: &godo.LoadBalancer{
+			ID:         lbID,
+			Name:       "web",
+			IP:         "203.0.113.10",
+			Status:     "active",
+			DropletIDs: []int{2, 1, 4},
+			ForwardingRules: []godo.ForwardingRule{
+				{EntryProtocol: "http", EntryPort: 80, TargetProtocol: "http", TargetPort: 8080},
+				{EntryProtocol: "https", EntryPort: 443, TargetProtocol: "http", TargetPort: 8080, CertificateID: certID},
+			},
+		}}
+		cert := do.Certificate{Certificate: &godo.Certificate{
+			ID:       certID,
+			Name:     "web-cert",
+			NotAfter: time.Now().Add(10 * 24 * time.Hour).Format(time.RFC3339),
+		}}
+
+		tm.loadBalancers.EXPECT().Get(lbID).Return(&lb, nil)
+		tm.droplets.EXPECT().List().Return(do.Droplets{
+			{Droplet: &godo.Droplet{ID: 1, Name: "blue", Status: "off"}},
+			{Droplet: &godo.Droplet{ID: 2, Name: "green", Status: "active"}},
+			{Droplet: &godo.Droplet{ID: 3, Name: "other", Status: "active"}},
+		}, nil)
+		tm.certificates.EXPECT().Get(certID).Return(&cert, nil)
+
+		var buf bytes.Buffer
+		config.Out = &buf
+		config.Args = append(config.Args, lbID)
+
+		err := RunLoadBalancerStatus(config)
+		assert.NoError(t, err)
+
+		out := buf.String()
+		assert.Regexp(t, `load-balancer\s+web\s+active`, out)
+		assert.Regexp(t, `droplet\s+blue \(1\)\s+off`, out)
+		assert.Regexp(t, `droplet\s+green \(2\)\s+active`, out)
+		assert.Regexp(t, `droplet\s+\(?4\)?\s+unknown`, out)
+		assert.NotContains(t, out, "other")
+		assert.Regexp(t, `rule\s+http:80 -> http:8080\s+ok`, out)
+		assert.Regexp(t, `rule\s+https:443 -> http:8080\s+expiring\s+certificate:web-cert`, out)
+	})
+}
+
+func TestLoadBalancerStatusTag(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		lbID := "cde2c0d6-41e3-479e-ba60-ad971227232c"
+		lb := do.LoadBalancer{LoadBalancer: &godo.LoadBalancer{ID: lbID, Name: "web", Status: "active", Tag: "web"}}
+
+		tm.loadBalancers.EXPECT().Get(lbID).Return(&lb, nil)
+		tm.droplets.EXPECT().ListByTag("web").Return(do.Droplets{{Droplet: &godo.Droplet{ID: 1, Name: "blue", Status: "active"}}}, nil)
+
+		var buf bytes.Buffer
+		config.Out = &buf
+		config.Args = append(config.Args, lbID)
+
+		err := RunLoadBalancerStatus(config)
+		assert.NoError(t, err)
+		assert.Regexp(t, `droplet\s+blue \(1\)\s+active`, buf.String())
+	})
+}
+
+func TestLoadBalancerStatusWatchJSON(t *testing.T) {
+	defer func(output string) { Output = output }(Output)
+	Output = "json"
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Args = append(config.Args, "cde2c0d6-41e3-479e-ba60-ad971227232c")
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerWatch, true)
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerWatchInterval, 5)
+
+		err := RunLoadBalancerStatus(config)
+		assert.EqualError(t, err, "--watch cannot be used with JSON output")
+	})
+}
+
+func TestCertificateExpiryStatus(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		notAfter string
+		status   string
+	}{
+		{notAfter: "2023-09-01T00:00:00Z", status: "ok"},
+		{notAfter: "2023-06-20T00:00:00Z", status: "expiring"},
+		{notAfter: "2023-05-01T00:00:00Z", status: "expired"},
+		{notAfter: "not a date", status: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notAfter, func(t *testing.T) {
+			cert := &do.Certificate{Certificate: &godo.Certificate{NotAfter: tt.notAfter}}
+			var rs displayers.LoadBalancerRuleStatus
+			assert.Equal(t, tt.status, certificateExpiryStatus(cert, now, &rs))
+		})
+	}
+}
+
+func TestLoadBalancerStatusTransitions(t *testing.T) {
+	previous := &displayers.LoadBalancerStatusReport{
+		Name:   "web",
+		Status: "active",
+		Backends: []displayers.LoadBalancerBackendStatus{
+			{DropletID: 1, DropletName: "blue", Status: "active"},
+			{DropletID: 2, DropletName: "green", Status: "new"},
+		},
+		ForwardingRules: []displayers.LoadBalancerRuleStatus{{Rule: "https:443 -> http:8080", Status: "ok"}},
+	}
+	current := &displayers.LoadBalancerStatusReport{
+		Name:   "web",
+		Status: "active",
+		Backends: []displayers.LoadBalancerBackendStatus{
+			{DropletID: 2, DropletName: "green", Status: "active"},
+			{DropletID: 3, DropletName: "canary", Status: "new"},
+		},
+		ForwardingRules: []displayers.LoadBalancerRuleStatus{{Rule: "https:443 -> http:8080", Status: "expiring"}},
+	}
+
+	assert.Equal(t, []string{
+		"droplet green (2): new -> active",
+		"droplet canary (3): added (new)",
+		"droplet blue (1): removed",
+		"rule https:443 -> http:8080: ok -> expiring",
+	}, loadBalancerStatusTransitions(previous, current))
+	assert.Empty(t, loadBalancerStatusTransitions(current, current))
+}
+
+func TestLoadBalancerGetSpec(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		lbID := "cde2c0d6-41e3-479e-ba60-ad971227232c"
+		lb := do.LoadBalancer{LoadBalancer: &godo.LoadBalancer{
+			ID:         lbID,
+			Name:       "web",
+			Algorithm:  "round_robin",
+			Region:     &godo.Region{Slug: "nyc1"},
+			SizeSlug:   "lb-small",
+			SizeUnit:   1,
+			Tag:        "web",
+			DropletIDs: []int{1, 2},
+			ForwardingRules: []godo.ForwardingRule{
+				{EntryProtocol: "http", EntryPort: 80, TargetProtocol: "http", TargetPort: 8080},
+			},
+		}}
+		tm.loadBalancers.EXPECT().Get(lbID).Return(&lb, nil)
+
+		var buf bytes.Buffer
+		config.Out = &buf
+		config.Args = append(config.Args, lbID)
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerSpec, true)
+
+		err := RunLoadBalancerGet(config)
+		assert.NoError(t, err)
+		assert.Equal(t, `forwarding_rules:
+- entry_port: 80
+  entry_protocol: http
+  target_port: 8080
+  target_protocol: http
+name: web
+region: nyc1
+size_unit: 1
+tag: web
+`, buf.String())
+	})
+}
+
 func TestLoadBalancerGetNoID(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		err := RunLoadBalancerGet(config)
@@ -139,6 +311,143 @@ func TestLoadBalancerCreate(t *testing.T) {
 	})
 }
 
+func TestLoadBalancerCreateWithSpec(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		spec := filepath.Join(t.TempDir(), "lb.yaml")
+		err := os.WriteFile(spec, []byte(`name: web
+region: nyc1
+size_unit: 2
+tag: web
+forwarding_rules:
+  - entry_protocol: http
+    entry_port: 80
+    target_protocol: http
+    target_port: 8080
+health_check:
+  protocol: http
+  port: 8080
+  path: /healthz
+`), 0600)
+		assert.NoError(t, err)
+
+		r := godo.LoadBalancerRequest{
+			Name:     "web",
+			Region:   "nyc1",
+			SizeUnit: 2,
+			Tag:      "web",
+			ForwardingRules: []godo.ForwardingRule{
+				{EntryProtocol: "http", EntryPort: 80, TargetProtocol: "http", TargetPort: 8080},
+			},
+			HealthCheck: &godo.HealthCheck{Protocol: "http", Port: 8080, Path: "/healthz"},
+		}
+		tm.loadBalancers.EXPECT().Create(&r).Return(&testLoadBalancer, nil)
+
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerSpec, spec)
+
+		err = RunLoadBalancerCreate(config)
+		assert.NoError(t, err)
+	})
+}
+
+func TestLoadBalancerCreateWithSpecUnknownField(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		spec := filepath.Join(t.TempDir(), "lb.yaml")
+		err := os.WriteFile(spec, []byte("name: web\nregion: nyc1\nforwarding_rule: []\n"), 0600)
+		assert.NoError(t, err)
+
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerSpec, spec)
+
+		err = RunLoadBalancerCreate(config)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadBalancerCreateMissingName(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Doit.Set(config.NS, doctl.ArgRegionSlug, "nyc1")
+
+		err := RunLoadBalancerCreate(config)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadBalancerUpdateWithSpec(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		lbID := "cde2c0d6-41e3-479e-ba60-ad971227232c"
+		spec := filepath.Join(t.TempDir(), "lb.yaml")
+		err := os.WriteFile(spec, []byte(`forwarding_rules:
+  - entry_protocol: http
+    entry_port: 80
+    target_port: 8081
+  - entry_protocol: https
+    entry_port: 443
+    target_protocol: http
+    target_port: 8080
+    certificate_id: cert-1
+health_check:
+  port: 8081
+droplet_ids: [3, 4]
+`), 0600)
+		assert.NoError(t, err)
+
+		live := do.LoadBalancer{LoadBalancer: &godo.LoadBalancer{
+			ID:       lbID,
+			Name:     "web",
+			Region:   &godo.Region{Slug: "nyc1"},
+			SizeUnit: 1,
+			Tag:      "web",
+			ForwardingRules: []godo.ForwardingRule{
+				{EntryProtocol: "http", EntryPort: 80, TargetProtocol: "http", TargetPort: 8080},
+				{EntryProtocol: "tcp", EntryPort: 22, TargetProtocol: "tcp", TargetPort: 22},
+			},
+			HealthCheck: &godo.HealthCheck{Protocol: "http", Port: 8080, Path: "/healthz"},
+		}}
+		r := godo.LoadBalancerRequest{
+			Name:       "web",
+			Region:     "nyc1",
+			SizeUnit:   1,
+			DropletIDs: []int{3, 4},
+			ForwardingRules: []godo.ForwardingRule{
+				{EntryProtocol: "http", EntryPort: 80, TargetProtocol: "http", TargetPort: 8081},
+				{EntryProtocol: "tcp", EntryPort: 22, TargetProtocol: "tcp", TargetPort: 22},
+				{EntryProtocol: "https", EntryPort: 443, TargetProtocol: "http", TargetPort: 8080, CertificateID: "cert-1"},
+			},
+			HealthCheck: &godo.HealthCheck{Protocol: "http", Port: 8081, Path: "/healthz"},
+		}
+		tm.loadBalancers.EXPECT().Get(lbID).Return(&live, nil)
+		tm.loadBalancers.EXPECT().Update(lbID, &r).Return(&testLoadBalancer, nil)
+
+		config.Args = append(config.Args, lbID)
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerSpec, spec)
+
+		err = RunLoadBalancerUpdate(config)
+		assert.NoError(t, err)
+	})
+
+	// with --prompt, the changes are not applied unless confirmed
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		lbID := "cde2c0d6-41e3-479e-ba60-ad971227232c"
+		spec := filepath.Join(t.TempDir(), "lb.yaml")
+		err := os.WriteFile(spec, []byte("health_check:\n  port: 8081\n"), 0600)
+		assert.NoError(t, err)
+
+		live := do.LoadBalancer{LoadBalancer: &godo.LoadBalancer{
+			ID:          lbID,
+			Name:        "web",
+			Region:      &godo.Region{Slug: "nyc1"},
+			HealthCheck: &godo.HealthCheck{Protocol: "http", Port: 8080, Path: "/healthz"},
+		}}
+		tm.loadBalancers.EXPECT().Get(lbID).Return(&live, nil)
+
+		config.Args = append(config.Args, lbID)
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerSpec, spec)
+		config.Doit.Set(config.NS, doctl.ArgLoadBalancerPrompt, true)
+
+		err = RunLoadBalancerUpdate(config)
+		assert.Equal(t, errOperationAborted, err)
+	})
+}
+
 func TestLoadBalancerUpdate(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		lbID := "cde2c0d6-41e3-479e-ba60-ad971227232c"
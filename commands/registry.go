@@ -482,11 +482,32 @@ func RunKubernetesManifest(c *CmdConfig) error {
 		return err
 	}
 
+	secret, err := registryPullSecret(c, secretName, secretNamespace)
+	if err != nil {
+		return err
+	}
+
+	serializer := k8sjson.NewSerializerWithOptions(
+		k8sjson.DefaultMetaFactory, nil, nil,
+		k8sjson.SerializerOptions{
+			Yaml:   true,
+			Pretty: true,
+			Strict: true,
+		},
+	)
+
+	return serializer.Encode(secret, c.Out)
+}
+
+// registryPullSecret builds a docker-registry secret with read-only
+// credentials for the registry. An empty name defaults to the registry name
+// prefixed with "registry-".
+func registryPullSecret(c *CmdConfig, secretName, secretNamespace string) (*k8sapiv1.Secret, error) {
 	// if no secret name supplied, use the registry name
 	if secretName == "" {
 		reg, err := c.Registry().Get()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		secretName = "registry-" + reg.Name
 	}
@@ -496,8 +517,14 @@ func RunKubernetesManifest(c *CmdConfig) error {
 		ReadWrite: false,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return newRegistryPullSecret(secretName, secretNamespace, dockerCreds), nil
+}
+
+// newRegistryPullSecret builds a docker-registry secret in a namespace from
+// registry credentials that were already fetched.
+func newRegistryPullSecret(secretName, secretNamespace string, dockerCreds *godo.DockerCredentials) *k8sapiv1.Secret {
 	annotations := map[string]string{}
 
 	if secretNamespace == k8smetav1.NamespaceSystem {
@@ -505,7 +532,7 @@ func RunKubernetesManifest(c *CmdConfig) error {
 	}
 
 	// create the manifest for the secret
-	secret := &k8sapiv1.Secret{
+	return &k8sapiv1.Secret{
 		TypeMeta: k8smetav1.TypeMeta{
 			Kind:       "Secret",
 			APIVersion: "v1",
@@ -520,17 +547,6 @@ func RunKubernetesManifest(c *CmdConfig) error {
 			".dockerconfigjson": dockerCreds.DockerConfigJSON,
 		},
 	}
-
-	serializer := k8sjson.NewSerializerWithOptions(
-		k8sjson.DefaultMetaFactory, nil, nil,
-		k8sjson.SerializerOptions{
-			Yaml:   true,
-			Pretty: true,
-			Strict: true,
-		},
-	)
-
-	return serializer.Encode(secret, c.Out)
 }
 
 // RunDockerConfig generates credentials and prints a Docker config that can be
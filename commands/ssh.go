@@ -195,3 +195,94 @@ func privateIPElsePub(droplet *do.Droplet, choice bool) (string, error) {
 	}
 	return droplet.PublicIPv4()
 }
+
+// dropletByIDOrName returns the Droplet with the given ID or, when the value is
+// not numeric, the single Droplet with that name.
+func dropletByIDOrName(ds do.DropletsService, idOrName string) (*do.Droplet, error) {
+	if id, err := strconv.Atoi(idOrName); err == nil {
+		return ds.Get(id)
+	}
+
+	droplets, err := ds.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []do.Droplet
+	for _, d := range droplets {
+		if d.Name == idOrName {
+			matches = append(matches, d)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, errors.New("Could not find Droplet")
+	case 1:
+		return &matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, d := range matches {
+			ids = append(ids, strconv.Itoa(d.ID))
+		}
+		return nil, fmt.Errorf("There are %d Droplets with the name %q; please provide a specific Droplet ID. [%s]",
+			len(matches), idOrName, strings.Join(ids, ", "))
+	}
+}
+
+// addDropletSSHFlags adds the flags needed by runDropletSSHCommand to a command
+// that connects to a Droplet on the user's behalf.
+func addDropletSSHFlags(cmd *Command) {
+	usr, err := user.Current()
+	checkErr(err)
+
+	AddStringFlag(cmd, doctl.ArgSSHUser, "", "root", "SSH user for connection")
+	AddStringFlag(cmd, doctl.ArgsSSHKeyPath, "", filepath.Join(usr.HomeDir, ".ssh", "id_rsa"), "Path to SSH private key")
+	AddIntFlag(cmd, doctl.ArgsSSHPort, "", 22, "The remote port sshd is running on")
+	AddBoolFlag(cmd, doctl.ArgsSSHPrivateIP, "", false, "SSH to Droplet's private IP address")
+}
+
+// runDropletSSHCommand runs a command on a Droplet over SSH using the
+// connection settings added by addDropletSSHFlags. The command is run as root;
+// for any other user it is wrapped in sudo, so it must not contain single quotes.
+func runDropletSSHCommand(c *CmdConfig, droplet *do.Droplet, command string) error {
+	user, err := c.Doit.GetString(c.NS, doctl.ArgSSHUser)
+	if err != nil {
+		return err
+	}
+
+	keyPath, err := c.Doit.GetString(c.NS, doctl.ArgsSSHKeyPath)
+	if err != nil {
+		return err
+	}
+
+	port, err := c.Doit.GetInt(c.NS, doctl.ArgsSSHPort)
+	if err != nil {
+		return err
+	}
+
+	privateIPChoice, err := c.Doit.GetBool(c.NS, doctl.ArgsSSHPrivateIP)
+	if err != nil {
+		return err
+	}
+
+	ip, err := privateIPElsePub(droplet, privateIPChoice)
+	if err != nil {
+		return err
+	}
+
+	if ip == "" {
+		return errors.New("Could not find Droplet address")
+	}
+
+	if user != "root" {
+		command = "sudo sh -c '" + command + "'"
+	}
+
+	opts := ssh.Options{
+		doctl.ArgsSSHAgentForwarding: false,
+		doctl.ArgSSHCommand:          command,
+	}
+
+	return c.Doit.SSH(user, ip, keyPath, port, opts).Run()
+}
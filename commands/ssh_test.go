@@ -14,10 +14,12 @@ limitations under the License.
 package commands
 
 import (
+	"fmt"
 	"strconv"
 	"testing"
 
 	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/do"
 	"github.com/digitalocean/doctl/pkg/runner"
 	"github.com/digitalocean/doctl/pkg/ssh"
 	"github.com/spf13/cobra"
@@ -66,6 +68,39 @@ func TestSSH_UnknownDroplet(t *testing.T) {
 	})
 }
 
+func TestSSH_DuplicateNames(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		duplicate := *testDroplet.Droplet
+		duplicate.ID = testDroplet.ID + 1
+		tm.droplets.EXPECT().List().Return(do.Droplets{testDroplet, {Droplet: &duplicate}}, nil)
+
+		config.Args = append(config.Args, testDroplet.Name)
+
+		err := RunSSH(config)
+		assert.NoError(t, err)
+	})
+}
+
+func TestDropletByIDOrName(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.droplets.EXPECT().Get(testDroplet.ID).Return(&testDroplet, nil)
+
+		droplet, err := dropletByIDOrName(config.Droplets(), strconv.Itoa(testDroplet.ID))
+		assert.NoError(t, err)
+		assert.Equal(t, &testDroplet, droplet)
+	})
+
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		duplicate := *testDroplet.Droplet
+		duplicate.ID = testDroplet.ID + 1
+		tm.droplets.EXPECT().List().Return(do.Droplets{testDroplet, {Droplet: &duplicate}}, nil)
+
+		_, err := dropletByIDOrName(config.Droplets(), testDroplet.Name)
+		assert.EqualError(t, err, fmt.Sprintf("There are 2 Droplets with the name %q; please provide a specific Droplet ID. [%d, %d]",
+			testDroplet.Name, testDroplet.ID, duplicate.ID))
+	})
+}
+
 func TestSSH_DropletWithNoPublic(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		tm.droplets.EXPECT().List().Return(testPrivateDropletList, nil)
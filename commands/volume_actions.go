@@ -86,9 +86,13 @@ When you attach a pre-formatted volume to Ubuntu, Debian, Fedora, Fedora Atomic,
 		aliasOpt("a"))
 	AddBoolFlag(cmdRunVolumeAttach, doctl.ArgCommandWait, "", false, "Wait for volume to attach")
 
-	cmdRunVolumeDetach := CmdBuilder(cmd, RunVolumeDetach, "detach <volume-id> <droplet-id>", "Detach a volume from a Droplet", `Use this command to detach a block storage volume from a Droplet.`, Writer,
+	cmdRunVolumeDetach := CmdBuilder(cmd, RunVolumeDetach, "detach <volume-id> <droplet-id>", "Detach a volume from a Droplet", `Use this command to detach a block storage volume from a Droplet.
+
+Use the `+"`"+`--unmount-first`+"`"+` flag to have doctl connect to the Droplet over SSH, unmount the volume and remove it from `+"`"+`/etc/fstab`+"`"+` before detaching it.`, Writer,
 		aliasOpt("d"))
 	AddBoolFlag(cmdRunVolumeDetach, doctl.ArgCommandWait, "", false, "Wait for volume to detach")
+	AddBoolFlag(cmdRunVolumeDetach, doctl.ArgVolumeUnmountFirst, "", false, "Unmount the volume on the Droplet over SSH before detaching it")
+	addDropletSSHFlags(cmdRunVolumeDetach)
 
 	CmdBuilder(cmd, RunVolumeDetach, "detach-by-droplet-id <volume-id> <droplet-id>", "(Deprecated) Detach a volume. Use `detach` instead.", "This command detaches a volume. This command is deprecated. Use `doctl compute volume-action detach` instead.",
 		Writer)
@@ -136,12 +140,39 @@ func RunVolumeDetach(c *CmdConfig) error {
 		if err != nil {
 			return nil, err
 		}
+
+		unmountFirst, err := c.Doit.GetBool(c.NS, doctl.ArgVolumeUnmountFirst)
+		if err != nil {
+			return nil, err
+		}
+		if unmountFirst {
+			err = unmountVolume(c, volumeID, dropletID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		a, err := das.Detach(volumeID, dropletID)
 		return a, err
 	}
 	return performVolumeAction(c, fn)
 }
 
+// unmountVolume unmounts a volume on the Droplet it is attached to.
+func unmountVolume(c *CmdConfig, volumeID string, dropletID int) error {
+	volume, err := c.Volumes().Get(volumeID)
+	if err != nil {
+		return err
+	}
+
+	droplet, err := c.Droplets().Get(dropletID)
+	if err != nil {
+		return err
+	}
+
+	return runDropletSSHCommand(c, droplet, volumeUnmountScript(volume.Name))
+}
+
 // RunVolumeResize resizes a volume
 func RunVolumeResize(c *CmdConfig) error {
 	fn := func(das do.VolumeActionsService) (*do.Action, error) {
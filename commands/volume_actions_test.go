@@ -18,7 +18,10 @@ import (
 	"testing"
 
 	"github.com/digitalocean/doctl"
+	"github.com/digitalocean/doctl/pkg/runner"
+	"github.com/digitalocean/doctl/pkg/ssh"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
 func TestVolumeActionCommand(t *testing.T) {
@@ -50,6 +53,32 @@ func TestVolumeActionsAttach(t *testing.T) {
 	})
 }
 
+func TestVolumeDetachUnmountFirst(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		gomock.InOrder(
+			tm.volumes.EXPECT().Get(testVolume.ID).Return(&testVolume, nil),
+			tm.droplets.EXPECT().Get(testDroplet.ID).Return(&testDroplet, nil),
+			tm.sshRunner.EXPECT().Run().Return(nil),
+			tm.volumeActions.EXPECT().Detach(testVolume.ID, testDroplet.ID).Return(&testAction, nil),
+		)
+
+		tc := config.Doit.(*doctl.TestConfig)
+		tc.SSHFn = func(user, host, keyPath string, port int, opts ssh.Options) runner.Runner {
+			assert.Contains(t, opts[doctl.ArgSSHCommand], "DEVICE=/dev/disk/by-id/scsi-0DO_Volume_test-volume")
+			assert.Contains(t, opts[doctl.ArgSSHCommand], "umount")
+			return tm.sshRunner
+		}
+
+		config.Args = append(config.Args, testVolume.ID)
+		config.Args = append(config.Args, fmt.Sprintf("%d", testDroplet.ID))
+		config.Doit.Set(config.NS, doctl.ArgVolumeUnmountFirst, true)
+		config.Doit.Set(config.NS, doctl.ArgSSHUser, "root")
+
+		err := RunVolumeDetach(config)
+		assert.NoError(t, err)
+	})
+}
+
 func TestVolumeDetach(t *testing.T) {
 	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
 		tm.volumeActions.EXPECT().Detach(testVolume.ID, testDroplet.ID).Return(&testAction, nil)
@@ -15,6 +15,9 @@ package commands
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"strings"
 
 	"github.com/digitalocean/doctl"
 	"github.com/digitalocean/doctl/commands/displayers"
@@ -71,10 +74,65 @@ You can use a block storage volume snapshot ID as a flag with `+"`"+`doctl volum
 	AddStringFlag(cmdRunVolumeSnapshot, doctl.ArgSnapshotDesc, "", "", "Snapshot description")
 	AddStringSliceFlag(cmdRunVolumeSnapshot, doctl.ArgTag, "", []string{}, "Tags to apply to the snapshot; comma separate or repeat `--tag` to add multiple tags at once")
 
+	cmdRunVolumeProvision := CmdBuilder(cmd, RunVolumeProvision, "provision <volume-name>", "Create or attach a volume, then format and mount it on a Droplet", `Use this command to make a block storage volume ready to use on a Droplet in one step.
+
+If no volume with the given name exists in the Droplet's region, it is created. The volume is then attached to the Droplet if it is not already, and doctl connects to the Droplet over SSH to format the volume (only if it does not already contain a filesystem), add it to `+"`"+`/etc/fstab`+"`"+`, and mount it.
+
+The SSH connection uses the same settings as `+"`"+`doctl compute ssh`+"`"+`. Users other than `+"`"+`root`+"`"+` must be able to run `+"`"+`sudo`+"`"+` without a password.`, Writer,
+		aliasOpt("p"), displayerType(&displayers.Volume{}))
+	AddStringFlag(cmdRunVolumeProvision, doctl.ArgDroplet, "", "", "The ID or name of the Droplet to provision the volume on", requiredOpt())
+	AddStringFlag(cmdRunVolumeProvision, doctl.ArgVolumeSize, "", "", "Volume size; required if the volume does not exist yet")
+	AddStringFlag(cmdRunVolumeProvision, doctl.ArgVolumeDesc, "", "", "Volume description, used if the volume is created")
+	AddStringSliceFlag(cmdRunVolumeProvision, doctl.ArgTag, "", []string{}, "Tags to apply to the volume if it is created; comma separate or repeat `--tag` to add multiple tags at once")
+	AddStringFlag(cmdRunVolumeProvision, doctl.ArgVolumeFilesystem, "", "ext4", "Filesystem to format the volume with if it is unformatted (ext4 or xfs)")
+	AddStringFlag(cmdRunVolumeProvision, doctl.ArgVolumeMountPath, "", "", "Absolute path to mount the volume at (default `/mnt/<volume-name>`)")
+	addDropletSSHFlags(cmdRunVolumeProvision)
+
 	return cmd
 
 }
 
+var (
+	volumeNameRE      = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	volumeMountPathRE = regexp.MustCompile(`^/[A-Za-z0-9._/-]*$`)
+)
+
+// volumeDevicePath returns the stable device path of an attached volume on a Droplet.
+func volumeDevicePath(name string) string {
+	return "/dev/disk/by-id/scsi-0DO_Volume_" + name
+}
+
+// volumeProvisionScript builds the shell script that formats an attached volume
+// if needed, persists it in /etc/fstab and mounts it. It is safe to run repeatedly.
+func volumeProvisionScript(name, fsType, mountPath string) string {
+	device := volumeDevicePath(name)
+	return strings.Join([]string{
+		"set -e",
+		"DEVICE=" + device,
+		`for i in $(seq 1 30); do [ -b "$DEVICE" ] && break; sleep 1; done`,
+		`[ -b "$DEVICE" ] || { echo "volume device $DEVICE not found" >&2; exit 1; }`,
+		`blkid "$DEVICE" >/dev/null 2>&1 || mkfs.` + fsType + ` -q "$DEVICE"`,
+		`FSTYPE=$(blkid -o value -s TYPE "$DEVICE")`,
+		"mkdir -p " + mountPath,
+		`grep -q "^$DEVICE " /etc/fstab || echo "$DEVICE ` + mountPath + ` $FSTYPE defaults,nofail,discard,noatime 0 2" >> /etc/fstab`,
+		"mountpoint -q " + mountPath + " || mount " + mountPath,
+	}, "\n")
+}
+
+// volumeUnmountScript builds the shell script that unmounts a volume from a
+// Droplet and removes its /etc/fstab entry.
+func volumeUnmountScript(name string) string {
+	device := volumeDevicePath(name)
+	return strings.Join([]string{
+		"set -e",
+		"DEVICE=" + device,
+		`if [ -b "$DEVICE" ]; then`,
+		`  for MOUNT in $(findmnt -n -o TARGET -S "$(readlink -f "$DEVICE")" || true); do umount "$MOUNT"; done`,
+		"fi",
+		`sed -i "\\|^$DEVICE |d" /etc/fstab`,
+	}, "\n")
+}
+
 // RunVolumeList returns a list of volumes.
 func RunVolumeList(c *CmdConfig) error {
 
@@ -272,3 +330,147 @@ func RunVolumeSnapshot(c *CmdConfig) error {
 	_, err = c.Volumes().CreateSnapshot(req)
 	return err
 }
+
+// volumeSizeGigaBytes parses a human readable volume size, rounding it up to
+// whole GiB as the API only accepts those.
+func volumeSizeGigaBytes(sizeStr string) (int64, error) {
+	size, err := humanize.ParseBytes(sizeStr)
+	if err != nil {
+		return 0, err
+	}
+	gib := int64((size + (1<<30 - 1)) / (1 << 30))
+	if gib == 0 {
+		return 0, fmt.Errorf("Invalid volume size %q; must be at least 1GiB", sizeStr)
+	}
+	return gib, nil
+}
+
+// RunVolumeProvision creates or attaches a volume and formats and mounts it on a Droplet.
+func RunVolumeProvision(c *CmdConfig) error {
+	err := ensureOneArg(c)
+	if err != nil {
+		return err
+	}
+	name := c.Args[0]
+	if !volumeNameRE.MatchString(name) {
+		return fmt.Errorf("Invalid volume name %q; must contain only lowercase letters, digits and '-'", name)
+	}
+
+	dropletIDOrName, err := c.Doit.GetString(c.NS, doctl.ArgDroplet)
+	if err != nil {
+		return err
+	}
+
+	sizeStr, err := c.Doit.GetString(c.NS, doctl.ArgVolumeSize)
+	if err != nil {
+		return err
+	}
+
+	desc, err := c.Doit.GetString(c.NS, doctl.ArgVolumeDesc)
+	if err != nil {
+		return err
+	}
+
+	tags, err := c.Doit.GetStringSlice(c.NS, doctl.ArgTag)
+	if err != nil {
+		return err
+	}
+
+	fsType, err := c.Doit.GetString(c.NS, doctl.ArgVolumeFilesystem)
+	if err != nil {
+		return err
+	}
+	if fsType != "ext4" && fsType != "xfs" {
+		return fmt.Errorf("Unsupported filesystem %q; must be one of ext4 or xfs", fsType)
+	}
+
+	mountPath, err := c.Doit.GetString(c.NS, doctl.ArgVolumeMountPath)
+	if err != nil {
+		return err
+	}
+	if mountPath == "" {
+		mountPath = "/mnt/" + name
+	}
+	mountPath = path.Clean(mountPath)
+	if !volumeMountPathRE.MatchString(mountPath) || mountPath == "/" {
+		return fmt.Errorf("Invalid mount path %q; must be an absolute path containing only letters, digits, '.', '_', '-' and '/'", mountPath)
+	}
+
+	droplet, err := dropletByIDOrName(c.Droplets(), dropletIDOrName)
+	if err != nil {
+		return err
+	}
+
+	vs := c.Volumes()
+	volumes, err := vs.List()
+	if err != nil {
+		return err
+	}
+
+	var volume *do.Volume
+	for i, v := range volumes {
+		if v.Name == name && v.Region != nil && v.Region.Slug == droplet.Region.Slug {
+			volume = &volumes[i]
+			break
+		}
+	}
+
+	if volume == nil {
+		if sizeStr == "" {
+			return fmt.Errorf("Volume %q does not exist in region %s; specify --%s to create it", name, droplet.Region.Slug, doctl.ArgVolumeSize)
+		}
+		size, err := volumeSizeGigaBytes(sizeStr)
+		if err != nil {
+			return err
+		}
+
+		notice("Creating volume %s in region %s", name, droplet.Region.Slug)
+		volume, err = vs.CreateVolume(&godo.VolumeCreateRequest{
+			Name:          name,
+			Region:        droplet.Region.Slug,
+			SizeGigaBytes: size,
+			Description:   desc,
+			Tags:          tags,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	attached := false
+	for _, id := range volume.DropletIDs {
+		if id != droplet.ID {
+			return fmt.Errorf("Volume %q is attached to Droplet %d; detach it first", name, id)
+		}
+		attached = true
+	}
+
+	if !attached {
+		notice("Attaching volume %s to Droplet %s", name, droplet.Name)
+		a, err := c.VolumeActions().Attach(volume.ID, droplet.ID)
+		if err != nil {
+			return err
+		}
+		a, err = actionWait(c, a.ID, 5)
+		if err != nil {
+			return err
+		}
+		if a.Status != "completed" {
+			return fmt.Errorf("Attaching volume %q finished with status %q", name, a.Status)
+		}
+	}
+
+	notice("Formatting and mounting volume %s at %s", name, mountPath)
+	err = runDropletSSHCommand(c, droplet, volumeProvisionScript(name, fsType, mountPath))
+	if err != nil {
+		return err
+	}
+
+	volume, err = vs.Get(volume.ID)
+	if err != nil {
+		return err
+	}
+
+	item := &displayers.Volume{Volumes: []do.Volume{*volume}}
+	return c.Display(item)
+}
@@ -14,10 +14,13 @@ limitations under the License.
 package commands
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/digitalocean/doctl"
 	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/doctl/pkg/runner"
+	"github.com/digitalocean/doctl/pkg/ssh"
 	"github.com/digitalocean/godo"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,7 +43,7 @@ var (
 func TestVolumeCommand(t *testing.T) {
 	cmd := Volume()
 	assert.NotNil(t, cmd)
-	assertCommandNames(t, cmd, "create", "delete", "get", "list", "snapshot")
+	assertCommandNames(t, cmd, "create", "delete", "get", "list", "provision", "snapshot")
 }
 
 func TestVolumesGet(t *testing.T) {
@@ -163,3 +166,125 @@ func TestVolumesSnapshot(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestVolumeProvision(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tcr := godo.VolumeCreateRequest{
+			Name:          "data",
+			Region:        "test0",
+			SizeGigaBytes: 100,
+		}
+		created := do.Volume{Volume: &godo.Volume{ID: "vol-1", Name: "data", Region: &godo.Region{Slug: "test0"}}}
+		attached := do.Volume{Volume: &godo.Volume{ID: "vol-1", Name: "data", Region: &godo.Region{Slug: "test0"}, DropletIDs: []int{testDroplet.ID}}}
+		completed := do.Action{Action: &godo.Action{ID: 1, Status: "completed"}}
+
+		tm.droplets.EXPECT().List().Return(testDropletList, nil)
+		tm.volumes.EXPECT().List().Return(testVolumeList, nil)
+		tm.volumes.EXPECT().CreateVolume(&tcr).Return(&created, nil)
+		tm.volumeActions.EXPECT().Attach("vol-1", testDroplet.ID).Return(&testAction, nil)
+		tm.actions.EXPECT().Get(1).Return(&completed, nil)
+		tm.sshRunner.EXPECT().Run().Return(nil)
+		tm.volumes.EXPECT().Get("vol-1").Return(&attached, nil)
+
+		tc := config.Doit.(*doctl.TestConfig)
+		tc.SSHFn = func(user, host, keyPath string, port int, opts ssh.Options) runner.Runner {
+			assert.Equal(t, "root", user)
+			assert.Equal(t, "8.8.8.8", host)
+			command := opts[doctl.ArgSSHCommand].(string)
+			assert.Contains(t, command, "mkfs.xfs")
+			assert.Contains(t, command, "mkdir -p /data")
+			assert.True(t, strings.HasPrefix(command, "set -e"))
+			return tm.sshRunner
+		}
+
+		config.Args = append(config.Args, "data")
+		config.Doit.Set(config.NS, doctl.ArgDroplet, testDroplet.Name)
+		config.Doit.Set(config.NS, doctl.ArgVolumeSize, "100GiB")
+		config.Doit.Set(config.NS, doctl.ArgVolumeFilesystem, "xfs")
+		config.Doit.Set(config.NS, doctl.ArgVolumeMountPath, "/data/")
+		config.Doit.Set(config.NS, doctl.ArgSSHUser, "root")
+
+		err := RunVolumeProvision(config)
+		assert.NoError(t, err)
+	})
+}
+
+func TestVolumeProvisionAlreadyAttached(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		attached := do.Volume{Volume: &godo.Volume{ID: "vol-1", Name: "data", Region: &godo.Region{Slug: "test0"}, DropletIDs: []int{testDroplet.ID}}}
+
+		tm.droplets.EXPECT().Get(testDroplet.ID).Return(&testDroplet, nil)
+		tm.volumes.EXPECT().List().Return([]do.Volume{attached}, nil)
+		tm.sshRunner.EXPECT().Run().Return(nil)
+		tm.volumes.EXPECT().Get("vol-1").Return(&attached, nil)
+
+		tc := config.Doit.(*doctl.TestConfig)
+		tc.SSHFn = func(user, host, keyPath string, port int, opts ssh.Options) runner.Runner {
+			command := opts[doctl.ArgSSHCommand].(string)
+			assert.True(t, strings.HasPrefix(command, "sudo sh -c 'set -e"))
+			assert.Contains(t, command, "mkfs.ext4")
+			assert.Contains(t, command, "mkdir -p /mnt/data")
+			return tm.sshRunner
+		}
+
+		config.Args = append(config.Args, "data")
+		config.Doit.Set(config.NS, doctl.ArgDroplet, "1")
+		config.Doit.Set(config.NS, doctl.ArgVolumeFilesystem, "ext4")
+		config.Doit.Set(config.NS, doctl.ArgSSHUser, "ubuntu")
+
+		err := RunVolumeProvision(config)
+		assert.NoError(t, err)
+	})
+}
+
+func TestVolumeProvisionMissingSize(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		tm.droplets.EXPECT().Get(testDroplet.ID).Return(&testDroplet, nil)
+		tm.volumes.EXPECT().List().Return(testVolumeList, nil)
+
+		config.Args = append(config.Args, "data")
+		config.Doit.Set(config.NS, doctl.ArgDroplet, "1")
+		config.Doit.Set(config.NS, doctl.ArgVolumeFilesystem, "ext4")
+
+		err := RunVolumeProvision(config)
+		assert.EqualError(t, err, `Volume "data" does not exist in region test0; specify --size to create it`)
+	})
+}
+
+func TestVolumeProvisionInvalidMountPath(t *testing.T) {
+	withTestClient(t, func(config *CmdConfig, tm *tcMocks) {
+		config.Args = append(config.Args, "data")
+		config.Doit.Set(config.NS, doctl.ArgDroplet, "1")
+		config.Doit.Set(config.NS, doctl.ArgVolumeFilesystem, "ext4")
+		config.Doit.Set(config.NS, doctl.ArgVolumeMountPath, "/mnt/da'ta")
+
+		err := RunVolumeProvision(config)
+		assert.Error(t, err)
+	})
+}
+
+func TestVolumeSizeGigaBytes(t *testing.T) {
+	tests := []struct {
+		size string
+		want int64
+		err  string
+	}{
+		{size: "100GiB", want: 100},
+		{size: "100GB", want: 94},
+		{size: "1.5GiB", want: 2},
+		{size: "500MB", want: 1},
+		{size: "0GB", err: `Invalid volume size "0GB"; must be at least 1GiB`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := volumeSizeGigaBytes(tt.size)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
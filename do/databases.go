@@ -15,6 +15,8 @@ package do
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/digitalocean/godo"
@@ -63,6 +65,9 @@ type DatabasePools []DatabasePool
 // DatabaseReplica is a wrapper for godo.DatabaseReplica
 type DatabaseReplica struct {
 	*godo.DatabaseReplica
+	// Size is the size slug of the replica. It is empty if the API doesn't
+	// report it.
+	Size string `json:"size,omitempty"`
 }
 
 // DatabaseReplicas is a slice of DatabaseReplica
@@ -73,6 +78,11 @@ type DatabaseConnection struct {
 	*godo.DatabaseConnection
 }
 
+// DatabaseCA is a wrapper for godo.DatabaseCA
+type DatabaseCA struct {
+	*godo.DatabaseCA
+}
+
 // DatabaseMaintenanceWindow is a wrapper for godo.DatabaseMaintenanceWindow
 type DatabaseMaintenanceWindow struct {
 	*godo.DatabaseMaintenanceWindow
@@ -86,6 +96,24 @@ type DatabaseFirewallRule struct {
 // DatabaseFirewallRules is a slice of DatabaseFirewallRule
 type DatabaseFirewallRules []DatabaseFirewallRule
 
+// PostgreSQLConfig is a wrapper for godo.PostgreSQLConfig
+type PostgreSQLConfig struct {
+	*godo.PostgreSQLConfig
+	// MaxConnections is the connection limit of the cluster. It is nil if
+	// the API doesn't report it.
+	MaxConnections *int `json:"max_connections,omitempty"`
+}
+
+// MySQLConfig is a wrapper for godo.MySQLConfig
+type MySQLConfig struct {
+	*godo.MySQLConfig
+}
+
+// RedisConfig is a wrapper for godo.RedisConfig
+type RedisConfig struct {
+	*godo.RedisConfig
+}
+
 // DatabaseOptions is a wrapper for
 type DatabaseOptions struct {
 	*godo.DatabaseOptions
@@ -103,6 +131,7 @@ type DatabasesService interface {
 	Create(*godo.DatabaseCreateRequest) (*Database, error)
 	Delete(string) error
 	GetConnection(string) (*DatabaseConnection, error)
+	GetCA(string) (*DatabaseCA, error)
 	ListBackups(string) (DatabaseBackups, error)
 	Resize(string, *godo.DatabaseResizeRequest) error
 	Migrate(string, *godo.DatabaseMigrateRequest) error
@@ -124,6 +153,7 @@ type DatabasesService interface {
 	ListPools(string) (DatabasePools, error)
 	CreatePool(string, *godo.DatabaseCreatePoolRequest) (*DatabasePool, error)
 	GetPool(string, string) (*DatabasePool, error)
+	UpdatePool(string, string, *godo.DatabaseUpdatePoolRequest) error
 	DeletePool(string, string) error
 
 	GetReplica(string, string) (*DatabaseReplica, error)
@@ -136,10 +166,22 @@ type DatabasesService interface {
 	GetSQLMode(string) ([]string, error)
 	SetSQLMode(string, ...string) error
 
+	GetEvictionPolicy(string) (string, error)
+	SetEvictionPolicy(string, string) error
+
+	UpgradeMajorVersion(string, *godo.UpgradeVersionRequest) error
+
 	GetFirewallRules(string) (DatabaseFirewallRules, error)
 	UpdateFirewallRules(databaseID string, req *godo.DatabaseUpdateFirewallRulesRequest) error
 
 	ListOptions() (*DatabaseOptions, error)
+
+	GetPostgreSQLConfiguration(string) (*PostgreSQLConfig, error)
+	GetMySQLConfiguration(string) (*MySQLConfig, error)
+	GetRedisConfiguration(string) (*RedisConfig, error)
+	UpdatePostgreSQLConfiguration(string, *godo.PostgreSQLConfig) error
+	UpdateMySQLConfiguration(string, *godo.MySQLConfig) error
+	UpdateRedisConfiguration(string, *godo.RedisConfig) error
 }
 
 type databasesService struct {
@@ -218,6 +260,15 @@ func (ds *databasesService) GetConnection(databaseID string) (*DatabaseConnectio
 	}, nil
 }
 
+func (ds *databasesService) GetCA(databaseID string) (*DatabaseCA, error) {
+	ca, _, err := ds.client.Databases.GetCA(context.TODO(), databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DatabaseCA{DatabaseCA: ca}, nil
+}
+
 func (ds *databasesService) Resize(databaseID string, req *godo.DatabaseResizeRequest) error {
 	_, err := ds.client.Databases.Resize(context.TODO(), databaseID, req)
 
@@ -433,31 +484,72 @@ func (ds *databasesService) GetPool(databaseID, poolName string) (*DatabasePool,
 	return &DatabasePool{DatabasePool: p}, nil
 }
 
+func (ds *databasesService) UpdatePool(databaseID, poolName string, req *godo.DatabaseUpdatePoolRequest) error {
+	_, err := ds.client.Databases.UpdatePool(context.TODO(), databaseID, poolName, req)
+
+	return err
+}
+
 func (ds *databasesService) DeletePool(databaseID, poolName string) error {
 	_, err := ds.client.Databases.DeletePool(context.TODO(), databaseID, poolName)
 
 	return err
 }
 
+const (
+	databaseReplicaPath  = "v2/databases/%s/replicas/%s"
+	databaseReplicasPath = "v2/databases/%s/replicas"
+)
+
+// databaseReplicaRoot is the root of a replica response. Unlike godo's, it
+// decodes the size of the replica, which godo.DatabaseReplica omits.
+type databaseReplicaRoot struct {
+	Replica *DatabaseReplica `json:"replica"`
+}
+
+// databaseReplicasRoot is the root of a replica list response.
+type databaseReplicasRoot struct {
+	Replicas []DatabaseReplica `json:"replicas"`
+}
+
 func (ds *databasesService) GetReplica(databaseID, replicaID string) (*DatabaseReplica, error) {
-	r, _, err := ds.client.Databases.GetReplica(context.TODO(), databaseID, replicaID)
+	path := fmt.Sprintf(databaseReplicaPath, databaseID, replicaID)
+	req, err := ds.client.NewRequest(context.TODO(), http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DatabaseReplica{DatabaseReplica: r}, nil
+	root := new(databaseReplicaRoot)
+	if _, err := ds.client.Do(context.TODO(), req, root); err != nil {
+		return nil, err
+	}
+	r := root.Replica
+	if r == nil {
+		r = &DatabaseReplica{}
+	}
+	if r.DatabaseReplica == nil {
+		r.DatabaseReplica = &godo.DatabaseReplica{}
+	}
+	return r, nil
 }
 
 func (ds *databasesService) ListReplicas(databaseID string) (DatabaseReplicas, error) {
 	f := func(opt *godo.ListOptions) ([]interface{}, *godo.Response, error) {
-		list, resp, err := ds.client.Databases.ListReplicas(context.TODO(), databaseID, opt)
+		path := fmt.Sprintf(databaseReplicasPath+"?page=%d&per_page=%d", databaseID, opt.Page, opt.PerPage)
+		req, err := ds.client.NewRequest(context.TODO(), http.MethodGet, path, nil)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		si := make([]interface{}, len(list))
-		for i := range list {
-			si[i] = list[i]
+		root := new(databaseReplicasRoot)
+		resp, err := ds.client.Do(context.TODO(), req, root)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		si := make([]interface{}, len(root.Replicas))
+		for i := range root.Replicas {
+			si[i] = root.Replicas[i]
 		}
 
 		return si, resp, err
@@ -470,8 +562,11 @@ func (ds *databasesService) ListReplicas(databaseID string) (DatabaseReplicas, e
 
 	list := make(DatabaseReplicas, len(si))
 	for i := range si {
-		r := si[i].(godo.DatabaseReplica)
-		list[i] = DatabaseReplica{DatabaseReplica: &r}
+		r := si[i].(DatabaseReplica)
+		if r.DatabaseReplica == nil {
+			r.DatabaseReplica = &godo.DatabaseReplica{}
+		}
+		list[i] = r
 	}
 	return list, nil
 }
@@ -518,6 +613,22 @@ func (ds *databasesService) SetSQLMode(databaseID string, sqlModes ...string) er
 	return err
 }
 
+func (ds *databasesService) GetEvictionPolicy(databaseID string) (string, error) {
+	policy, _, err := ds.client.Databases.GetEvictionPolicy(context.TODO(), databaseID)
+	return policy, err
+}
+
+func (ds *databasesService) SetEvictionPolicy(databaseID, policy string) error {
+	_, err := ds.client.Databases.SetEvictionPolicy(context.TODO(), databaseID, policy)
+	return err
+}
+
+func (ds *databasesService) UpgradeMajorVersion(databaseID string, req *godo.UpgradeVersionRequest) error {
+	_, err := ds.client.Databases.UpgradeMajorVersion(context.TODO(), databaseID, req)
+
+	return err
+}
+
 func (ds *databasesService) GetFirewallRules(databaseID string) (DatabaseFirewallRules, error) {
 	f := func(opt *godo.ListOptions) ([]interface{}, *godo.Response, error) {
 		list, resp, err := ds.client.Databases.GetFirewallRules(context.TODO(), databaseID)
@@ -560,3 +671,69 @@ func (ds *databasesService) ListOptions() (*DatabaseOptions, error) {
 	}
 	return &DatabaseOptions{DatabaseOptions: options}, nil
 }
+
+const databaseConfigPath = "v2/databases/%s/config"
+
+// databasePostgreSQLConfigRoot is the root of a PostgreSQL configuration
+// response. Unlike godo's, it decodes max_connections, which
+// godo.PostgreSQLConfig omits.
+type databasePostgreSQLConfigRoot struct {
+	Config *PostgreSQLConfig `json:"config"`
+}
+
+func (ds *databasesService) GetPostgreSQLConfiguration(databaseID string) (*PostgreSQLConfig, error) {
+	path := fmt.Sprintf(databaseConfigPath, databaseID)
+	req, err := ds.client.NewRequest(context.TODO(), http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	root := new(databasePostgreSQLConfigRoot)
+	if _, err := ds.client.Do(context.TODO(), req, root); err != nil {
+		return nil, err
+	}
+	cfg := root.Config
+	if cfg == nil {
+		cfg = &PostgreSQLConfig{}
+	}
+	if cfg.PostgreSQLConfig == nil {
+		cfg.PostgreSQLConfig = &godo.PostgreSQLConfig{}
+	}
+	return cfg, nil
+}
+
+func (ds *databasesService) GetMySQLConfiguration(databaseID string) (*MySQLConfig, error) {
+	cfg, _, err := ds.client.Databases.GetMySQLConfig(context.TODO(), databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MySQLConfig{MySQLConfig: cfg}, nil
+}
+
+func (ds *databasesService) GetRedisConfiguration(databaseID string) (*RedisConfig, error) {
+	cfg, _, err := ds.client.Databases.GetRedisConfig(context.TODO(), databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisConfig{RedisConfig: cfg}, nil
+}
+
+func (ds *databasesService) UpdatePostgreSQLConfiguration(databaseID string, cfg *godo.PostgreSQLConfig) error {
+	_, err := ds.client.Databases.UpdatePostgreSQLConfig(context.TODO(), databaseID, cfg)
+
+	return err
+}
+
+func (ds *databasesService) UpdateMySQLConfiguration(databaseID string, cfg *godo.MySQLConfig) error {
+	_, err := ds.client.Databases.UpdateMySQLConfig(context.TODO(), databaseID, cfg)
+
+	return err
+}
+
+func (ds *databasesService) UpdateRedisConfiguration(databaseID string, cfg *godo.RedisConfig) error {
+	_, err := ds.client.Databases.UpdateRedisConfig(context.TODO(), databaseID, cfg)
+
+	return err
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package do_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabasesGetPostgreSQLConfiguration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/v2/databases/db-1/config", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"config": {"work_mem": 8, "max_connections": 100}}`)
+	}))
+	defer server.Close()
+
+	client, err := godo.New(server.Client(), godo.SetBaseURL(server.URL))
+	require.NoError(t, err)
+	dbs := do.NewDatabasesService(client)
+
+	cfg, err := dbs.GetPostgreSQLConfiguration("db-1")
+	require.NoError(t, err)
+	require.NotNil(t, cfg.MaxConnections)
+	assert.Equal(t, 100, *cfg.MaxConnections)
+	require.NotNil(t, cfg.WorkMem)
+	assert.Equal(t, 8, *cfg.WorkMem)
+}
+
+func TestDatabasesReplicaSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		replica := `{"id": "r-1", "name": "replica-1", "region": "nyc1", "status": "online", "size": "db-s-2vcpu-4gb"}`
+		switch r.URL.Path {
+		case "/v2/databases/db-1/replicas":
+			io.WriteString(w, `{"replicas": [`+replica+`]}`)
+		case "/v2/databases/db-1/replicas/replica-1":
+			io.WriteString(w, `{"replica": `+replica+`}`)
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := godo.New(server.Client(), godo.SetBaseURL(server.URL))
+	require.NoError(t, err)
+	dbs := do.NewDatabasesService(client)
+
+	list, err := dbs.ListReplicas("db-1")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "replica-1", list[0].Name)
+	assert.Equal(t, "db-s-2vcpu-4gb", list[0].Size)
+
+	r, err := dbs.GetReplica("db-1", "replica-1")
+	require.NoError(t, err)
+	assert.Equal(t, "r-1", r.ID)
+	assert.Equal(t, "db-s-2vcpu-4gb", r.Size)
+}
@@ -15,6 +15,8 @@ package do
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
 	"github.com/digitalocean/godo"
 )
@@ -99,18 +101,23 @@ type KubernetesService interface {
 	GetNodeSizes() (KubernetesNodeSizes, error)
 	AddRegistry(req *godo.KubernetesClusterRegistryRequest) error
 	RemoveRegistry(req *godo.KubernetesClusterRegistryRequest) error
+
+	RunClusterlint(clusterID string, req *godo.KubernetesRunClusterlintRequest) (string, error)
+	GetClusterlintResults(clusterID string, req *godo.KubernetesGetClusterlintRequest) ([]*godo.ClusterlintDiagnostic, error)
 }
 
 var _ KubernetesService = &kubernetesClusterService{}
 
 type kubernetesClusterService struct {
-	client godo.KubernetesService
+	client     godo.KubernetesService
+	godoClient *godo.Client
 }
 
 // NewKubernetesService builds an instance of KubernetesService.
 func NewKubernetesService(client *godo.Client) KubernetesService {
 	return &kubernetesClusterService{
-		client: client.Kubernetes,
+		client:     client.Kubernetes,
+		godoClient: client,
 	}
 }
 
@@ -291,12 +298,67 @@ func (k8s *kubernetesClusterService) ListNodePools(clusterID string) (Kubernetes
 
 }
 
+const kubernetesNodePoolPath = "v2/kubernetes/clusters/%s/node_pools/%s"
+
+// kubernetesNodePoolUpdateRequest is godo.KubernetesNodePoolUpdateRequest
+// with tags and labels that are sent when they are empty but not nil, so
+// that they can be cleared.
+type kubernetesNodePoolUpdateRequest struct {
+	Name      string             `json:"name,omitempty"`
+	Count     *int               `json:"count,omitempty"`
+	Tags      *[]string          `json:"tags,omitempty"`
+	Labels    *map[string]string `json:"labels,omitempty"`
+	Taints    *[]godo.Taint      `json:"taints,omitempty"`
+	AutoScale *bool              `json:"auto_scale,omitempty"`
+	MinNodes  *int               `json:"min_nodes,omitempty"`
+	MaxNodes  *int               `json:"max_nodes,omitempty"`
+}
+
+// kubernetesNodePoolRoot is the root of an individual node pool response.
+//
+// Copied from godo.
+type kubernetesNodePoolRoot struct {
+	NodePool *godo.KubernetesNodePool `json:"node_pool,omitempty"`
+}
+
 func (k8s *kubernetesClusterService) UpdateNodePool(clusterID, poolID string, req *godo.KubernetesNodePoolUpdateRequest) (*KubernetesNodePool, error) {
-	pool, _, err := k8s.client.UpdateNodePool(context.TODO(), clusterID, poolID, req)
+	clearsTags := req.Tags != nil && len(req.Tags) == 0
+	clearsLabels := req.Labels != nil && len(req.Labels) == 0
+	if !clearsTags && !clearsLabels {
+		pool, _, err := k8s.client.UpdateNodePool(context.TODO(), clusterID, poolID, req)
+		if err != nil {
+			return nil, err
+		}
+		return &KubernetesNodePool{KubernetesNodePool: pool}, nil
+	}
+
+	// godo omits empty tags and labels, which leaves them unchanged
+	update := &kubernetesNodePoolUpdateRequest{
+		Name:      req.Name,
+		Count:     req.Count,
+		Taints:    req.Taints,
+		AutoScale: req.AutoScale,
+		MinNodes:  req.MinNodes,
+		MaxNodes:  req.MaxNodes,
+	}
+	if req.Tags != nil {
+		update.Tags = &req.Tags
+	}
+	if req.Labels != nil {
+		update.Labels = &req.Labels
+	}
+
+	path := fmt.Sprintf(kubernetesNodePoolPath, clusterID, poolID)
+	r, err := k8s.godoClient.NewRequest(context.TODO(), http.MethodPut, path, update)
 	if err != nil {
 		return nil, err
 	}
-	return &KubernetesNodePool{KubernetesNodePool: pool}, nil
+
+	root := new(kubernetesNodePoolRoot)
+	if _, err := k8s.godoClient.Do(context.TODO(), r, root); err != nil {
+		return nil, err
+	}
+	return &KubernetesNodePool{KubernetesNodePool: root.NodePool}, nil
 }
 
 func (k8s *kubernetesClusterService) RecycleNodePoolNodes(clusterID, poolID string, req *godo.KubernetesNodePoolRecycleNodesRequest) error {
@@ -359,3 +421,21 @@ func (k8s *kubernetesClusterService) RemoveRegistry(req *godo.KubernetesClusterR
 	_, err := k8s.client.RemoveRegistry(context.TODO(), req)
 	return err
 }
+
+func (k8s *kubernetesClusterService) RunClusterlint(clusterID string, req *godo.KubernetesRunClusterlintRequest) (string, error) {
+	runID, _, err := k8s.client.RunClusterlint(context.TODO(), clusterID, req)
+	if err != nil {
+		return "", err
+	}
+
+	return runID, nil
+}
+
+func (k8s *kubernetesClusterService) GetClusterlintResults(clusterID string, req *godo.KubernetesGetClusterlintRequest) ([]*godo.ClusterlintDiagnostic, error) {
+	diagnostics, _, err := k8s.client.GetClusterlintResults(context.TODO(), clusterID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return diagnostics, nil
+}
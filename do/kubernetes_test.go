@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Doctl Authors All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package do_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/digitalocean/doctl/do"
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesUpdateNodePoolClears(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/v2/kubernetes/clusters/cluster-1/node_pools/pool-1", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &body))
+
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"node_pool": {"id": "pool-1", "name": "web"}}`)
+	}))
+	defer server.Close()
+
+	client, err := godo.New(server.Client(), godo.SetBaseURL(server.URL))
+	require.NoError(t, err)
+	kube := do.NewKubernetesService(client)
+
+	pool, err := kube.UpdateNodePool("cluster-1", "pool-1", &godo.KubernetesNodePoolUpdateRequest{
+		Name:   "web",
+		Tags:   []string{},
+		Labels: map[string]string{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pool-1", pool.ID)
+	assert.Equal(t, map[string]interface{}{
+		"name":   "web",
+		"tags":   []interface{}{},
+		"labels": map[string]interface{}{},
+	}, body)
+}
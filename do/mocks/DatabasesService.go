@@ -195,6 +195,21 @@ func (mr *MockDatabasesServiceMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDatabasesService)(nil).Get), arg0)
 }
 
+// GetCA mocks base method.
+func (m *MockDatabasesService) GetCA(arg0 string) (*do.DatabaseCA, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCA", arg0)
+	ret0, _ := ret[0].(*do.DatabaseCA)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCA indicates an expected call of GetCA.
+func (mr *MockDatabasesServiceMockRecorder) GetCA(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCA", reflect.TypeOf((*MockDatabasesService)(nil).GetCA), arg0)
+}
+
 // GetConnection mocks base method.
 func (m *MockDatabasesService) GetConnection(arg0 string) (*do.DatabaseConnection, error) {
 	m.ctrl.T.Helper()
@@ -225,6 +240,21 @@ func (mr *MockDatabasesServiceMockRecorder) GetDB(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDB", reflect.TypeOf((*MockDatabasesService)(nil).GetDB), arg0, arg1)
 }
 
+// GetEvictionPolicy mocks base method.
+func (m *MockDatabasesService) GetEvictionPolicy(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvictionPolicy", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvictionPolicy indicates an expected call of GetEvictionPolicy.
+func (mr *MockDatabasesServiceMockRecorder) GetEvictionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvictionPolicy", reflect.TypeOf((*MockDatabasesService)(nil).GetEvictionPolicy), arg0)
+}
+
 // GetFirewallRules mocks base method.
 func (m *MockDatabasesService) GetFirewallRules(arg0 string) (do.DatabaseFirewallRules, error) {
 	m.ctrl.T.Helper()
@@ -255,6 +285,21 @@ func (mr *MockDatabasesServiceMockRecorder) GetMaintenance(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenance", reflect.TypeOf((*MockDatabasesService)(nil).GetMaintenance), arg0)
 }
 
+// GetMySQLConfiguration mocks base method.
+func (m *MockDatabasesService) GetMySQLConfiguration(arg0 string) (*do.MySQLConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMySQLConfiguration", arg0)
+	ret0, _ := ret[0].(*do.MySQLConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMySQLConfiguration indicates an expected call of GetMySQLConfiguration.
+func (mr *MockDatabasesServiceMockRecorder) GetMySQLConfiguration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMySQLConfiguration", reflect.TypeOf((*MockDatabasesService)(nil).GetMySQLConfiguration), arg0)
+}
+
 // GetPool mocks base method.
 func (m *MockDatabasesService) GetPool(arg0, arg1 string) (*do.DatabasePool, error) {
 	m.ctrl.T.Helper()
@@ -270,6 +315,36 @@ func (mr *MockDatabasesServiceMockRecorder) GetPool(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPool", reflect.TypeOf((*MockDatabasesService)(nil).GetPool), arg0, arg1)
 }
 
+// GetPostgreSQLConfiguration mocks base method.
+func (m *MockDatabasesService) GetPostgreSQLConfiguration(arg0 string) (*do.PostgreSQLConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostgreSQLConfiguration", arg0)
+	ret0, _ := ret[0].(*do.PostgreSQLConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostgreSQLConfiguration indicates an expected call of GetPostgreSQLConfiguration.
+func (mr *MockDatabasesServiceMockRecorder) GetPostgreSQLConfiguration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostgreSQLConfiguration", reflect.TypeOf((*MockDatabasesService)(nil).GetPostgreSQLConfiguration), arg0)
+}
+
+// GetRedisConfiguration mocks base method.
+func (m *MockDatabasesService) GetRedisConfiguration(arg0 string) (*do.RedisConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedisConfiguration", arg0)
+	ret0, _ := ret[0].(*do.RedisConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedisConfiguration indicates an expected call of GetRedisConfiguration.
+func (mr *MockDatabasesServiceMockRecorder) GetRedisConfiguration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedisConfiguration", reflect.TypeOf((*MockDatabasesService)(nil).GetRedisConfiguration), arg0)
+}
+
 // GetReplica mocks base method.
 func (m *MockDatabasesService) GetReplica(arg0, arg1 string) (*do.DatabaseReplica, error) {
 	m.ctrl.T.Helper()
@@ -492,6 +567,20 @@ func (mr *MockDatabasesServiceMockRecorder) Resize(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockDatabasesService)(nil).Resize), arg0, arg1)
 }
 
+// SetEvictionPolicy mocks base method.
+func (m *MockDatabasesService) SetEvictionPolicy(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEvictionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEvictionPolicy indicates an expected call of SetEvictionPolicy.
+func (mr *MockDatabasesServiceMockRecorder) SetEvictionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEvictionPolicy", reflect.TypeOf((*MockDatabasesService)(nil).SetEvictionPolicy), arg0, arg1)
+}
+
 // SetSQLMode mocks base method.
 func (m *MockDatabasesService) SetSQLMode(arg0 string, arg1 ...string) error {
 	m.ctrl.T.Helper()
@@ -538,3 +627,73 @@ func (mr *MockDatabasesServiceMockRecorder) UpdateMaintenance(arg0, arg1 interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMaintenance", reflect.TypeOf((*MockDatabasesService)(nil).UpdateMaintenance), arg0, arg1)
 }
+
+// UpdateMySQLConfiguration mocks base method.
+func (m *MockDatabasesService) UpdateMySQLConfiguration(arg0 string, arg1 *godo.MySQLConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMySQLConfiguration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMySQLConfiguration indicates an expected call of UpdateMySQLConfiguration.
+func (mr *MockDatabasesServiceMockRecorder) UpdateMySQLConfiguration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMySQLConfiguration", reflect.TypeOf((*MockDatabasesService)(nil).UpdateMySQLConfiguration), arg0, arg1)
+}
+
+// UpdatePool mocks base method.
+func (m *MockDatabasesService) UpdatePool(arg0, arg1 string, arg2 *godo.DatabaseUpdatePoolRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePool", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePool indicates an expected call of UpdatePool.
+func (mr *MockDatabasesServiceMockRecorder) UpdatePool(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePool", reflect.TypeOf((*MockDatabasesService)(nil).UpdatePool), arg0, arg1, arg2)
+}
+
+// UpdatePostgreSQLConfiguration mocks base method.
+func (m *MockDatabasesService) UpdatePostgreSQLConfiguration(arg0 string, arg1 *godo.PostgreSQLConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostgreSQLConfiguration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePostgreSQLConfiguration indicates an expected call of UpdatePostgreSQLConfiguration.
+func (mr *MockDatabasesServiceMockRecorder) UpdatePostgreSQLConfiguration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostgreSQLConfiguration", reflect.TypeOf((*MockDatabasesService)(nil).UpdatePostgreSQLConfiguration), arg0, arg1)
+}
+
+// UpdateRedisConfiguration mocks base method.
+func (m *MockDatabasesService) UpdateRedisConfiguration(arg0 string, arg1 *godo.RedisConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRedisConfiguration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRedisConfiguration indicates an expected call of UpdateRedisConfiguration.
+func (mr *MockDatabasesServiceMockRecorder) UpdateRedisConfiguration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRedisConfiguration", reflect.TypeOf((*MockDatabasesService)(nil).UpdateRedisConfiguration), arg0, arg1)
+}
+
+// UpgradeMajorVersion mocks base method.
+func (m *MockDatabasesService) UpgradeMajorVersion(arg0 string, arg1 *godo.UpgradeVersionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeMajorVersion", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpgradeMajorVersion indicates an expected call of UpgradeMajorVersion.
+func (mr *MockDatabasesServiceMockRecorder) UpgradeMajorVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeMajorVersion", reflect.TypeOf((*MockDatabasesService)(nil).UpgradeMajorVersion), arg0, arg1)
+}
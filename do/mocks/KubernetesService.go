@@ -164,6 +164,21 @@ func (mr *MockKubernetesServiceMockRecorder) Get(clusterID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockKubernetesService)(nil).Get), clusterID)
 }
 
+// GetClusterlintResults mocks base method.
+func (m *MockKubernetesService) GetClusterlintResults(clusterID string, req *godo.KubernetesGetClusterlintRequest) ([]*godo.ClusterlintDiagnostic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterlintResults", clusterID, req)
+	ret0, _ := ret[0].([]*godo.ClusterlintDiagnostic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterlintResults indicates an expected call of GetClusterlintResults.
+func (mr *MockKubernetesServiceMockRecorder) GetClusterlintResults(clusterID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterlintResults", reflect.TypeOf((*MockKubernetesService)(nil).GetClusterlintResults), clusterID, req)
+}
+
 // GetCredentials mocks base method.
 func (m *MockKubernetesService) GetCredentials(clusterID string) (*do.KubernetesClusterCredentials, error) {
 	m.ctrl.T.Helper()
@@ -357,6 +372,21 @@ func (mr *MockKubernetesServiceMockRecorder) RemoveRegistry(req interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRegistry", reflect.TypeOf((*MockKubernetesService)(nil).RemoveRegistry), req)
 }
 
+// RunClusterlint mocks base method.
+func (m *MockKubernetesService) RunClusterlint(clusterID string, req *godo.KubernetesRunClusterlintRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunClusterlint", clusterID, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunClusterlint indicates an expected call of RunClusterlint.
+func (mr *MockKubernetesServiceMockRecorder) RunClusterlint(clusterID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunClusterlint", reflect.TypeOf((*MockKubernetesService)(nil).RunClusterlint), clusterID, req)
+}
+
 // Update mocks base method.
 func (m *MockKubernetesService) Update(clusterID string, update *godo.KubernetesClusterUpdateRequest) (*do.KubernetesCluster, error) {
 	m.ctrl.T.Helper()